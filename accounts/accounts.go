@@ -0,0 +1,140 @@
+// Package accounts manages the sessions of many members over one API key.
+// Every account has its own client, so that tokens and cached data are never
+// shared between members, while the clients share one HTTP client and its
+// connections.
+package accounts
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+// Account represents an authenticated member session.
+type Account struct {
+	ID    int
+	Login string
+	// Client is the client of the account, bound to its own token.
+	Client *bsclient.BetaSeries
+
+	mutex      sync.Mutex
+	member     *bsclient.Member
+	generation int // incremented by Invalidate
+}
+
+// Member returns the profile of the member, cached by the account until Invalidate.
+// The account is not locked during the request.
+func (a *Account) Member(ctx context.Context) (*bsclient.Member, error) {
+	a.mutex.Lock()
+	member, generation := a.member, a.generation
+	a.mutex.Unlock()
+	if member != nil {
+		return member, nil
+	}
+	member, err := a.Client.WithContext(ctx).MembersInfos(nil)
+	if err != nil {
+		return nil, err
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	// a profile requested before Invalidate may be stale
+	if a.generation == generation {
+		a.member = member
+	}
+	return member, nil
+}
+
+// Invalidate removes the cached data of the account.
+func (a *Account) Invalidate() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.member = nil
+	a.generation++
+}
+
+// Manager holds the accounts of many members. It is safe for concurrent use.
+type Manager struct {
+	key     string
+	options []bsclient.ClientOption
+
+	mutex    sync.RWMutex
+	accounts map[int]*Account
+}
+
+// NewManager returns a manager whose clients use the given API key and
+// options. They share one HTTP client, unless the options set another one.
+func NewManager(key string, options ...bsclient.ClientOption) *Manager {
+	shared := bsclient.WithHTTPClient(bsclient.NewHTTPClient())
+	return &Manager{
+		key:      key,
+		options:  append([]bsclient.ClientOption{shared}, options...),
+		accounts: map[int]*Account{},
+	}
+}
+
+// Login authenticates a member and adds its account, replacing the
+// previous session of the member if any.
+func (m *Manager) Login(ctx context.Context, login, password string) (*Account, error) {
+	bs, err := bsclient.NewBetaseriesClient(m.key, "", "", m.options...)
+	if err != nil {
+		return nil, err
+	}
+	if err := bs.WithContext(ctx).Login(login, password); err != nil {
+		return nil, err
+	}
+	id, name := bs.Identity()
+	if name == "" {
+		name = login
+	}
+	account := &Account{ID: id, Login: name, Client: bs}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.accounts[id] = account
+	return account, nil
+}
+
+// Account returns the account of a member by id.
+func (m *Manager) Account(id int) (*Account, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	account, ok := m.accounts[id]
+	return account, ok
+}
+
+// AccountByLogin returns the account of a member by login, ignoring case.
+func (m *Manager) AccountByLogin(login string) (*Account, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	for _, account := range m.accounts {
+		if strings.EqualFold(account.Login, login) {
+			return account, true
+		}
+	}
+	return nil, false
+}
+
+// Accounts returns the accounts sorted by member id.
+func (m *Manager) Accounts() []*Account {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	accounts := make([]*Account, 0, len(m.accounts))
+	for _, account := range m.accounts {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].ID < accounts[j].ID
+	})
+	return accounts
+}
+
+// Remove removes the account of a member, returning false if there is none.
+func (m *Manager) Remove(id int) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	_, ok := m.accounts[id]
+	delete(m.accounts, id)
+	return ok
+}
@@ -0,0 +1,142 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dns-gh/bs-client/bsclient"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+// members are the members of the fake API, by login.
+var members = map[string]int{"walter": 1, "jesse": 2, "skyler": 3}
+
+// newAPI returns a fake API giving each member the token 'token-<id>',
+// and counting the requests of members/infos.
+func newAPI(c *C, infos *int64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Header.Get("X-BetaSeries-Key"), Equals, "key")
+		switch r.URL.Path {
+		case "/members/auth":
+			login := r.URL.Query().Get("login")
+			id, ok := members[login]
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"errors": [{"code": 4002, "text": "Unknown member."}]}`)
+				return
+			}
+			fmt.Fprintf(w, `{"user": {"id": %d, "login": "%s"}, "token": "token-%d"}`, id, login, id)
+		case "/members/infos":
+			atomic.AddInt64(infos, 1)
+			var id int
+			fmt.Sscanf(r.Header.Get("X-BetaSeries-Token"), "token-%d", &id)
+			fmt.Fprintf(w, `{"member": {"id": %d}}`, id)
+		}
+	}))
+}
+
+func (s *MySuite) TestManager(c *C) {
+	infos := int64(0)
+	server := newAPI(c, &infos)
+	defer server.Close()
+	m := NewManager("key", bsclient.WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	wg := sync.WaitGroup{}
+	for login := range members {
+		wg.Add(1)
+		go func(login string) {
+			defer wg.Done()
+			_, err := m.Login(ctx, login, "password")
+			c.Check(err, IsNil)
+		}(login)
+	}
+	wg.Wait()
+	_, err := m.Login(ctx, "saul", "password")
+	c.Assert(bsclient.ErrorCode(err), Equals, 4002)
+
+	accounts := m.Accounts()
+	c.Assert(accounts, HasLen, 3)
+	c.Assert(accounts[0].ID, Equals, 1)
+	c.Assert(accounts[0].Login, Equals, "walter")
+	jesse, ok := m.AccountByLogin("Jesse")
+	c.Assert(ok, Equals, true)
+	c.Assert(jesse.ID, Equals, 2)
+	account, ok := m.Account(3)
+	c.Assert(ok, Equals, true)
+	c.Assert(account.Login, Equals, "skyler")
+
+	// every account uses its own token, and caches its own data
+	for i := 0; i < 2; i++ {
+		wg := sync.WaitGroup{}
+		for _, account := range accounts {
+			wg.Add(1)
+			go func(account *Account) {
+				defer wg.Done()
+				member, err := account.Member(ctx)
+				c.Check(err, IsNil)
+				c.Check(member.ID, Equals, account.ID)
+			}(account)
+		}
+		wg.Wait()
+	}
+	c.Assert(atomic.LoadInt64(&infos), Equals, int64(3))
+	jesse.Invalidate()
+	_, err = jesse.Member(ctx)
+	c.Assert(err, IsNil)
+	c.Assert(atomic.LoadInt64(&infos), Equals, int64(4))
+
+	c.Assert(m.Remove(2), Equals, true)
+	c.Assert(m.Remove(2), Equals, false)
+	_, ok = m.AccountByLogin("jesse")
+	c.Assert(ok, Equals, false)
+	c.Assert(m.Accounts(), HasLen, 2)
+}
+
+func (s *MySuite) TestMemberUnlocked(c *C) {
+	arrived := make(chan struct{})
+	release := make(chan struct{})
+	infos := int64(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&infos, 1) == 1 {
+			close(arrived)
+			<-release
+		}
+		fmt.Fprint(w, `{"member": {"id": 1}}`)
+	}))
+	defer server.Close()
+	bs, err := bsclient.NewBetaseriesClient("key", "", "", bsclient.WithBaseURL(server.URL))
+	c.Assert(err, IsNil)
+	account := &Account{ID: 1, Client: bs}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := account.Member(context.Background())
+		c.Check(err, IsNil)
+	}()
+	<-arrived
+	// the account is usable during the request
+	account.Invalidate()
+	close(release)
+	<-done
+
+	// the profile requested before Invalidate is not cached
+	_, err = account.Member(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(atomic.LoadInt64(&infos), Equals, int64(2))
+	_, err = account.Member(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(atomic.LoadInt64(&infos), Equals, int64(2))
+}
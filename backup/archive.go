@@ -0,0 +1,100 @@
+// Package backup snapshots the account of a betaseries member into a
+// versioned JSON archive, and restores an archive onto an account.
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+// Version is the version of the archives written by this package.
+const Version = 1
+
+var errUnsupportedVersion = errors.New("unsupported archive version")
+
+// Episode represents the status of an episode in the account.
+type Episode struct {
+	ID         int  `json:"id"`
+	ThetvdbID  int  `json:"thetvdb_id,omitempty"`
+	Season     int  `json:"season"`
+	Episode    int  `json:"episode"`
+	Seen       bool `json:"seen,omitempty"`
+	Downloaded bool `json:"downloaded,omitempty"`
+	Note       int  `json:"note,omitempty"`
+}
+
+// Show represents a show of the account, with its User status, and its
+// episodes which are seen, downloaded or noted.
+type Show struct {
+	Show     bsclient.Show `json:"show"`
+	Episodes []Episode     `json:"episodes"`
+}
+
+// Friend represents a friend or a blocked member.
+type Friend struct {
+	ID    int    `json:"id"`
+	Login string `json:"login"`
+}
+
+// Archive represents the snapshot of the account of a member.
+type Archive struct {
+	Version int              `json:"version"`
+	Created time.Time        `json:"created"`
+	Member  *bsclient.Member `json:"member"`
+	Shows   []Show           `json:"shows"`
+	Friends []Friend         `json:"friends"`
+	Blocked []Friend         `json:"blocked"`
+}
+
+// Write writes the archive as indented JSON.
+func (a *Archive) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(a)
+}
+
+// Read reads an archive, checking its version.
+func Read(r io.Reader) (*Archive, error) {
+	a := &Archive{}
+	if err := json.NewDecoder(r).Decode(a); err != nil {
+		return nil, err
+	}
+	if a.Version < 1 || a.Version > Version {
+		return nil, fmt.Errorf("%w: %d", errUnsupportedVersion, a.Version)
+	}
+	return a, nil
+}
+
+// Save writes the archive file atomically.
+func (a *Archive) Save(path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := a.Write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Load reads an archive file.
+func Load(path string) (*Archive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
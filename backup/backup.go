@@ -0,0 +1,92 @@
+package backup
+
+import (
+	"context"
+	"time"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+// Progress is called after every step of a backup or a restore, with the
+// number of steps done, the total number of steps and the description of the step.
+type Progress func(done, total int, step string)
+
+// source is the part of bsclient.BetaSeries read by Backup.
+type source interface {
+	MembersInfos(opts *bsclient.MembersInfosOptions) (*bsclient.Member, error)
+	ShowsEpisodes(show bsclient.ShowRef, opts *bsclient.ShowsEpisodesOptions) ([]bsclient.Episode, error)
+	FriendsList(opts *bsclient.FriendsListOptions) ([]bsclient.Member, error)
+}
+
+func friends(members []bsclient.Member) []Friend {
+	friends := make([]Friend, 0, len(members))
+	for _, member := range members {
+		friends = append(friends, Friend{ID: member.ID, Login: member.Login})
+	}
+	return friends
+}
+
+// Backup snapshots the account of the identified member. 'progress' may be nil.
+func Backup(ctx context.Context, bs *bsclient.BetaSeries, progress Progress) (*Archive, error) {
+	return backup(bs.WithContext(ctx), progress, time.Now())
+}
+
+func backup(bs source, progress Progress, now time.Time) (*Archive, error) {
+	if progress == nil {
+		progress = func(int, int, string) {}
+	}
+	member, err := bs.MembersInfos(&bsclient.MembersInfosOptions{Summary: true})
+	if err != nil {
+		return nil, err
+	}
+	shows, err := bs.MembersInfos(&bsclient.MembersInfosOptions{Only: "shows"})
+	if err != nil {
+		return nil, err
+	}
+	total := len(shows.Shows) + 3
+	progress(1, total, "member")
+
+	a := &Archive{
+		Version: Version,
+		Created: now.UTC(),
+		Member:  member,
+		Shows:   make([]Show, 0, len(shows.Shows)),
+	}
+	for i, show := range shows.Shows {
+		episodes, err := bs.ShowsEpisodes(show.Ref(), nil)
+		if err != nil && !bsclient.IsNotFound(err) {
+			return nil, err
+		}
+		s := Show{Show: show, Episodes: []Episode{}}
+		for _, episode := range episodes {
+			if !episode.User.Seen && !episode.User.Downloaded && episode.Note.User == 0 {
+				continue
+			}
+			s.Episodes = append(s.Episodes, Episode{
+				ID:         episode.ID,
+				ThetvdbID:  episode.ThetvdbID,
+				Season:     episode.Season,
+				Episode:    episode.Episode,
+				Seen:       episode.User.Seen,
+				Downloaded: episode.User.Downloaded,
+				Note:       episode.Note.User,
+			})
+		}
+		a.Shows = append(a.Shows, s)
+		progress(i+2, total, show.Title)
+	}
+
+	members, err := bs.FriendsList(nil)
+	if err != nil && !bsclient.IsNotFound(err) {
+		return nil, err
+	}
+	a.Friends = friends(members)
+	progress(total-1, total, "friends")
+	members, err = bs.FriendsList(&bsclient.FriendsListOptions{Blocked: true})
+	if err != nil && !bsclient.IsNotFound(err) {
+		return nil, err
+	}
+	a.Blocked = friends(members)
+	progress(total, total, "blocked members")
+	return a, nil
+}
@@ -0,0 +1,228 @@
+package backup
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dns-gh/bs-client/bsclient"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+// fakeAccount is an in-memory betaseries account.
+type fakeAccount struct {
+	id       int
+	shows    []bsclient.Show
+	episodes map[int][]bsclient.Episode
+	friends  []bsclient.Member
+	blocked  []bsclient.Member
+	calls    int
+}
+
+func (f *fakeAccount) show(id int) *bsclient.Show {
+	for i := range f.shows {
+		if f.shows[i].ID == id {
+			return &f.shows[i]
+		}
+	}
+	return nil
+}
+
+func (f *fakeAccount) episode(id int) *bsclient.Episode {
+	for _, episodes := range f.episodes {
+		for i := range episodes {
+			if episodes[i].ID == id {
+				return &episodes[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fakeAccount) MembersInfos(opts *bsclient.MembersInfosOptions) (*bsclient.Member, error) {
+	member := &bsclient.Member{ID: f.id, Login: "member"}
+	if !opts.Summary {
+		member.Shows = append([]bsclient.Show{}, f.shows...)
+	}
+	return member, nil
+}
+
+func (f *fakeAccount) ShowsEpisodes(show bsclient.ShowRef, opts *bsclient.ShowsEpisodesOptions) ([]bsclient.Episode, error) {
+	return append([]bsclient.Episode{}, f.episodes[show.ID]...), nil
+}
+
+func (f *fakeAccount) FriendsList(opts *bsclient.FriendsListOptions) ([]bsclient.Member, error) {
+	if opts != nil && opts.Blocked {
+		return f.blocked, nil
+	}
+	return f.friends, nil
+}
+
+func (f *fakeAccount) ShowAdd(show bsclient.ShowRef, opts *bsclient.ShowAddOptions) (*bsclient.Show, error) {
+	f.calls++
+	f.shows = append(f.shows, bsclient.Show{ID: show.ID})
+	f.episodes[show.ID] = []bsclient.Episode{{ID: show.ID * 10}, {ID: show.ID*10 + 1}}
+	return nil, nil
+}
+
+func (f *fakeAccount) ShowArchive(show bsclient.ShowRef) (*bsclient.Show, error) {
+	f.calls++
+	f.show(show.ID).User.Archived = true
+	return nil, nil
+}
+
+func (f *fakeAccount) ShowFavorite(show bsclient.ShowRef) (*bsclient.Show, error) {
+	f.calls++
+	f.show(show.ID).User.Favorited = true
+	return nil, nil
+}
+
+func (f *fakeAccount) ShowNote(show bsclient.ShowRef, note int) (*bsclient.Show, error) {
+	f.calls++
+	f.show(show.ID).Notes.User = note
+	return nil, nil
+}
+
+func (f *fakeAccount) EpisodeWatched(episode bsclient.EpisodeRef, opts *bsclient.EpisodeWatchedOptions) (*bsclient.Episode, error) {
+	f.calls++
+	e := f.episode(episode.ID)
+	e.User.Seen = true
+	if opts.Note != nil {
+		e.Note.User = *opts.Note
+	}
+	return nil, nil
+}
+
+func (f *fakeAccount) EpisodeDownloaded(episode bsclient.EpisodeRef) (*bsclient.Episode, error) {
+	f.calls++
+	f.episode(episode.ID).User.Downloaded = true
+	return nil, nil
+}
+
+func (f *fakeAccount) EpisodeNote(episode bsclient.EpisodeRef, note int) (*bsclient.Episode, error) {
+	f.calls++
+	f.episode(episode.ID).Note.User = note
+	return nil, nil
+}
+
+func (f *fakeAccount) FriendsFriend(id int) (*bsclient.Member, error) {
+	f.calls++
+	f.friends = append(f.friends, bsclient.Member{ID: id})
+	return nil, nil
+}
+
+func (f *fakeAccount) FriendsBlock(id int) (*bsclient.Member, error) {
+	f.calls++
+	f.blocked = append(f.blocked, bsclient.Member{ID: id})
+	return nil, nil
+}
+
+func newSourceAccount() *fakeAccount {
+	bb := bsclient.Show{ID: 481, Title: "Breaking Bad"}
+	bb.User.Favorited = true
+	bb.Notes.User = 5
+	wire := bsclient.Show{ID: 1, Title: "The Wire"}
+	wire.User.Archived = true
+	seen := bsclient.Episode{ID: 4810, Season: 1, Episode: 1}
+	seen.User.Seen = true
+	seen.Note.User = 4
+	downloaded := bsclient.Episode{ID: 4811, Season: 1, Episode: 2}
+	downloaded.User.Downloaded = true
+	return &fakeAccount{
+		id:    10,
+		shows: []bsclient.Show{bb, wire},
+		episodes: map[int][]bsclient.Episode{
+			481: {seen, downloaded, {ID: 4812, Season: 1, Episode: 3}},
+			1:   {{ID: 10}},
+		},
+		friends: []bsclient.Member{{ID: 2, Login: "friend"}, {ID: 20, Login: "target"}},
+		blocked: []bsclient.Member{{ID: 3, Login: "troll"}},
+	}
+}
+
+func (s *MySuite) TestArchive(c *C) {
+	steps := []string{}
+	a, err := backup(newSourceAccount(), func(done, total int, step string) {
+		c.Assert(total, Equals, 5)
+		steps = append(steps, step)
+	}, time.Date(2016, 3, 10, 12, 0, 0, 0, time.UTC))
+	c.Assert(err, IsNil)
+	c.Assert(steps, DeepEquals, []string{"member", "Breaking Bad", "The Wire", "friends", "blocked members"})
+	c.Assert(a.Member.ID, Equals, 10)
+	c.Assert(a.Shows, HasLen, 2)
+	c.Assert(a.Shows[0].Episodes, DeepEquals, []Episode{
+		{ID: 4810, Season: 1, Episode: 1, Seen: true, Note: 4},
+		{ID: 4811, Season: 1, Episode: 2, Downloaded: true},
+	})
+	c.Assert(a.Shows[1].Episodes, HasLen, 0)
+	c.Assert(a.Blocked, DeepEquals, []Friend{{ID: 3, Login: "troll"}})
+
+	path := filepath.Join(c.MkDir(), "backup.json")
+	c.Assert(a.Save(path), IsNil)
+	loaded, err := Load(path)
+	c.Assert(err, IsNil)
+	c.Assert(loaded, DeepEquals, a)
+
+	_, err = Read(strings.NewReader(`{"version": 2}`))
+	c.Assert(err, ErrorMatches, "unsupported archive version: 2")
+	b := &bytes.Buffer{}
+	c.Assert((&Archive{}).Write(b), IsNil)
+	_, err = Read(b)
+	c.Assert(err, ErrorMatches, "unsupported archive version: 0")
+}
+
+func (s *MySuite) TestRestore(c *C) {
+	a, err := backup(newSourceAccount(), nil, time.Now())
+	c.Assert(err, IsNil)
+
+	// the target already has one of the shows
+	wire := bsclient.Show{ID: 1, Title: "The Wire"}
+	target := &fakeAccount{
+		id:       20,
+		shows:    []bsclient.Show{wire},
+		episodes: map[int][]bsclient.Episode{1: {{ID: 10}}},
+		friends:  []bsclient.Member{{ID: 2}},
+	}
+
+	report, err := (&Restorer{DryRun: true}).restore(target, a)
+	c.Assert(err, IsNil)
+	c.Assert(report.Applied, Equals, 0)
+	c.Assert(target.calls, Equals, 0)
+	kinds := []string{}
+	for _, action := range report.Actions {
+		kinds = append(kinds, action.Kind)
+	}
+	c.Assert(kinds, DeepEquals, []string{
+		ActionShowAdd, ActionEpisodeWatched, ActionEpisodeDownloaded, ActionShowNote, ActionShowFavorite,
+		ActionShowArchive, ActionBlock,
+	})
+	c.Assert(report.Actions[1], DeepEquals, Action{
+		Kind: ActionEpisodeWatched, ID: 4810, Note: 4, Description: "Breaking Bad: S01E01 watched",
+	})
+
+	progress := 0
+	report, err = (&Restorer{Progress: func(done, total int, step string) {
+		progress = done
+		c.Assert(total, Equals, 7)
+	}}).restore(target, a)
+	c.Assert(err, IsNil)
+	c.Assert(report.Applied, Equals, 7)
+	c.Assert(progress, Equals, 7)
+	c.Assert(target.show(481).Notes.User, Equals, 5)
+	c.Assert(target.episode(4810).Note.User, Equals, 4)
+	c.Assert(target.show(1).User.Archived, Equals, true)
+
+	// restoring again does nothing
+	report, err = (&Restorer{}).restore(target, a)
+	c.Assert(err, IsNil)
+	c.Assert(report.Actions, HasLen, 0)
+	c.Assert(target.calls, Equals, 7)
+}
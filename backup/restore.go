@@ -0,0 +1,214 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+// Kinds of restore actions.
+const (
+	ActionShowAdd           = "show-add"
+	ActionShowArchive       = "show-archive"
+	ActionShowFavorite      = "show-favorite"
+	ActionShowNote          = "show-note"
+	ActionEpisodeWatched    = "episode-watched"
+	ActionEpisodeDownloaded = "episode-downloaded"
+	ActionEpisodeNote       = "episode-note"
+	ActionFriend            = "friend"
+	ActionBlock             = "block"
+)
+
+// target is the part of bsclient.BetaSeries used by Restore.
+type target interface {
+	source
+	ShowAdd(show bsclient.ShowRef, opts *bsclient.ShowAddOptions) (*bsclient.Show, error)
+	ShowArchive(show bsclient.ShowRef) (*bsclient.Show, error)
+	ShowFavorite(show bsclient.ShowRef) (*bsclient.Show, error)
+	ShowNote(show bsclient.ShowRef, note int) (*bsclient.Show, error)
+	EpisodeWatched(episode bsclient.EpisodeRef, opts *bsclient.EpisodeWatchedOptions) (*bsclient.Episode, error)
+	EpisodeDownloaded(episode bsclient.EpisodeRef) (*bsclient.Episode, error)
+	EpisodeNote(episode bsclient.EpisodeRef, note int) (*bsclient.Episode, error)
+	FriendsFriend(id int) (*bsclient.Member, error)
+	FriendsBlock(id int) (*bsclient.Member, error)
+}
+
+// Action represents a change applied by a restore.
+type Action struct {
+	Kind string `json:"kind"`
+	// ID is the id of the show, the episode or the member.
+	ID          int    `json:"id"`
+	Note        int    `json:"note,omitempty"`
+	Description string `json:"description"`
+}
+
+// Report represents the actions of a restore, and how many of them were applied.
+type Report struct {
+	Actions []Action `json:"actions"`
+	Applied int      `json:"applied"`
+}
+
+// Restorer replays archives onto the account of the identified member.
+type Restorer struct {
+	Client *bsclient.BetaSeries
+	// DryRun only reports the actions without applying them.
+	DryRun bool
+	// Progress, if set, is called after every applied action.
+	Progress Progress
+}
+
+// NewRestorer creates a restorer.
+func NewRestorer(bs *bsclient.BetaSeries) *Restorer {
+	return &Restorer{Client: bs}
+}
+
+// Restore compares an archive with the account and applies the missing
+// changes: shows, seen and downloaded episodes, notes, favorites, archives,
+// friends and blocked members. It never removes anything, so it can be run
+// again, for instance after an error, without applying anything twice.
+// The member information itself is not restored.
+func (r *Restorer) Restore(ctx context.Context, a *Archive) (*Report, error) {
+	return r.restore(r.Client.WithContext(ctx), a)
+}
+
+func (r *Restorer) restore(bs target, a *Archive) (*Report, error) {
+	actions, err := plan(bs, a)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{Actions: actions}
+	if r.DryRun {
+		return report, nil
+	}
+	for _, action := range actions {
+		if err := apply(bs, action); err != nil {
+			return report, fmt.Errorf("%s: %w", action.Description, err)
+		}
+		report.Applied++
+		if r.Progress != nil {
+			r.Progress(report.Applied, len(actions), action.Description)
+		}
+	}
+	return report, nil
+}
+
+// plan returns the actions needed to restore the archive onto the account.
+func plan(bs target, a *Archive) ([]Action, error) {
+	member, err := bs.MembersInfos(&bsclient.MembersInfosOptions{Only: "shows"})
+	if err != nil {
+		return nil, err
+	}
+	current := map[int]*bsclient.Show{}
+	for i := range member.Shows {
+		current[member.Shows[i].ID] = &member.Shows[i]
+	}
+
+	actions := []Action{}
+	for _, s := range a.Shows {
+		show := &s.Show
+		add := func(kind string, id, note int, format string, args ...interface{}) {
+			actions = append(actions, Action{
+				Kind:        kind,
+				ID:          id,
+				Note:        note,
+				Description: show.Title + ": " + fmt.Sprintf(format, args...),
+			})
+		}
+		cur, ok := current[show.ID]
+		episodes := map[int]bsclient.Episode{}
+		if !ok {
+			cur = &bsclient.Show{}
+			add(ActionShowAdd, show.ID, 0, "add")
+		} else {
+			list, err := bs.ShowsEpisodes(bsclient.ShowID(show.ID), nil)
+			if err != nil && !bsclient.IsNotFound(err) {
+				return nil, err
+			}
+			for _, episode := range list {
+				episodes[episode.ID] = episode
+			}
+		}
+		for _, episode := range s.Episodes {
+			cur := episodes[episode.ID]
+			code := fmt.Sprintf("S%02dE%02d", episode.Season, episode.Episode)
+			if episode.Seen && !cur.User.Seen {
+				add(ActionEpisodeWatched, episode.ID, episode.Note, "%s watched", code)
+			} else if episode.Note > 0 && episode.Note != cur.Note.User {
+				add(ActionEpisodeNote, episode.ID, episode.Note, "%s noted %d", code, episode.Note)
+			}
+			if episode.Downloaded && !cur.User.Downloaded {
+				add(ActionEpisodeDownloaded, episode.ID, 0, "%s downloaded", code)
+			}
+		}
+		if show.Notes.User > 0 && show.Notes.User != cur.Notes.User {
+			add(ActionShowNote, show.ID, show.Notes.User, "noted %d", show.Notes.User)
+		}
+		if show.User.Favorited && !cur.User.Favorited {
+			add(ActionShowFavorite, show.ID, 0, "favorite")
+		}
+		if show.User.Archived && !cur.User.Archived {
+			add(ActionShowArchive, show.ID, 0, "archive")
+		}
+	}
+
+	for _, list := range []struct {
+		kind    string
+		friends []Friend
+		opts    *bsclient.FriendsListOptions
+	}{
+		{ActionFriend, a.Friends, nil},
+		{ActionBlock, a.Blocked, &bsclient.FriendsListOptions{Blocked: true}},
+	} {
+		if len(list.friends) == 0 {
+			continue
+		}
+		members, err := bs.FriendsList(list.opts)
+		if err != nil && !bsclient.IsNotFound(err) {
+			return nil, err
+		}
+		known := map[int]bool{member.ID: true}
+		for _, m := range members {
+			known[m.ID] = true
+		}
+		for _, friend := range list.friends {
+			if !known[friend.ID] {
+				actions = append(actions, Action{
+					Kind:        list.kind,
+					ID:          friend.ID,
+					Description: list.kind + " " + friend.Login,
+				})
+			}
+		}
+	}
+	return actions, nil
+}
+
+func apply(bs target, action Action) error {
+	var err error
+	switch action.Kind {
+	case ActionShowAdd:
+		_, err = bs.ShowAdd(bsclient.ShowID(action.ID), nil)
+	case ActionShowArchive:
+		_, err = bs.ShowArchive(bsclient.ShowID(action.ID))
+	case ActionShowFavorite:
+		_, err = bs.ShowFavorite(bsclient.ShowID(action.ID))
+	case ActionShowNote:
+		_, err = bs.ShowNote(bsclient.ShowID(action.ID), action.Note)
+	case ActionEpisodeWatched:
+		opts := &bsclient.EpisodeWatchedOptions{Bulk: bsclient.Bool(false)}
+		if action.Note > 0 {
+			opts.Note = bsclient.Int(action.Note)
+		}
+		_, err = bs.EpisodeWatched(bsclient.EpisodeID(action.ID), opts)
+	case ActionEpisodeDownloaded:
+		_, err = bs.EpisodeDownloaded(bsclient.EpisodeID(action.ID))
+	case ActionEpisodeNote:
+		_, err = bs.EpisodeNote(bsclient.EpisodeID(action.ID), action.Note)
+	case ActionFriend:
+		_, err = bs.FriendsFriend(action.ID)
+	case ActionBlock:
+		_, err = bs.FriendsBlock(action.ID)
+	}
+	return err
+}
@@ -0,0 +1,145 @@
+// Package batch applies lists of changes to the account of the identified
+// member, like marking hundreds of episodes as watched, with a bounded number
+// of concurrent requests.
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+// DefaultWorkers is the number of concurrent requests of a runner created without one.
+const DefaultWorkers = 4
+
+var errUnknownKind = errors.New("unknown operation")
+
+// Outcome represents the result of an operation.
+type Outcome struct {
+	// Index is the index of the operation in the list given to Run.
+	Index     int
+	Operation Operation
+	Err       error
+	// Skipped is set if the operation was not executed, because a previous
+	// one failed with StopOnError or the context was cancelled.
+	Skipped bool
+}
+
+// Report represents the results of the operations, in the order of the operations.
+type Report struct {
+	Results   []Outcome
+	Succeeded int
+	Failed    int
+	Skipped   int
+}
+
+// Err returns the errors of the failed operations, or nil.
+func (r *Report) Err() error {
+	errs := []error{}
+	for _, result := range r.Results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", result.Operation, result.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Progress is called after every executed operation, with the number of
+// executed operations and the total number of operations.
+type Progress func(done, total int, result Outcome)
+
+// Runner executes operations with a pool of workers.
+type Runner struct {
+	Client *bsclient.BetaSeries
+	// Workers is the number of concurrent requests, DefaultWorkers if 0.
+	Workers int
+	// StopOnError stops executing operations after the first failure,
+	// instead of executing every operation.
+	StopOnError bool
+	// Progress, if set, is called after every executed operation, never concurrently.
+	Progress Progress
+}
+
+// NewRunner creates a runner.
+func NewRunner(bs *bsclient.BetaSeries) *Runner {
+	return &Runner{Client: bs}
+}
+
+// Run executes the operations and reports their results. The error joins
+// the errors of the failed operations, or is the error of the context if it
+// was cancelled before every operation was executed.
+func (r *Runner) Run(ctx context.Context, ops []Operation) (*Report, error) {
+	return r.run(ctx, r.Client.WithContext(ctx), ops)
+}
+
+func (r *Runner) run(ctx context.Context, bs target, ops []Operation) (*Report, error) {
+	workers := r.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	report := &Report{Results: make([]Outcome, len(ops))}
+	for i, op := range ops {
+		report.Results[i] = Outcome{Index: i, Operation: op, Skipped: true}
+	}
+
+	mutex := sync.Mutex{}
+	stopped := false
+	done := 0
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers && w < len(ops); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				err := ops[i].apply(bs)
+				mutex.Lock()
+				result := &report.Results[i]
+				result.Err, result.Skipped = err, false
+				done++
+				if err != nil && r.StopOnError {
+					stopped = true
+				}
+				if r.Progress != nil {
+					r.Progress(done, len(ops), *result)
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for i := range ops {
+		mutex.Lock()
+		stop := stopped
+		mutex.Unlock()
+		if stop {
+			break
+		}
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, result := range report.Results {
+		switch {
+		case result.Skipped:
+			report.Skipped++
+		case result.Err != nil:
+			report.Failed++
+		default:
+			report.Succeeded++
+		}
+	}
+	if report.Skipped > 0 && ctx.Err() != nil {
+		return report, ctx.Err()
+	}
+	return report, report.Err()
+}
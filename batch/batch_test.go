@@ -0,0 +1,167 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dns-gh/bs-client/bsclient"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+var errFake = errors.New("fake error")
+
+// fakeTarget records the operations and fails those of the episodes and shows listed in 'fail'.
+type fakeTarget struct {
+	mutex    sync.Mutex
+	fail     map[int]bool
+	calls    []string
+	inFlight int
+	maxIn    int
+	delay    time.Duration
+}
+
+func (f *fakeTarget) call(name string, id int) error {
+	f.mutex.Lock()
+	f.calls = append(f.calls, name)
+	f.inFlight++
+	if f.inFlight > f.maxIn {
+		f.maxIn = f.inFlight
+	}
+	f.mutex.Unlock()
+	time.Sleep(f.delay)
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.inFlight--
+	if f.fail[id] {
+		return errFake
+	}
+	return nil
+}
+
+func (f *fakeTarget) EpisodeWatched(episode bsclient.EpisodeRef, opts *bsclient.EpisodeWatchedOptions) (*bsclient.Episode, error) {
+	if opts.Bulk == nil || *opts.Bulk {
+		return nil, errors.New("bulk watched")
+	}
+	return &bsclient.Episode{}, f.call("watched", episode.ID)
+}
+
+func (f *fakeTarget) EpisodeDownloaded(episode bsclient.EpisodeRef) (*bsclient.Episode, error) {
+	return &bsclient.Episode{}, f.call("downloaded", episode.ID)
+}
+
+func (f *fakeTarget) EpisodeNote(episode bsclient.EpisodeRef, note int) (*bsclient.Episode, error) {
+	return &bsclient.Episode{}, f.call("episode-note", episode.ID)
+}
+
+func (f *fakeTarget) ShowNote(show bsclient.ShowRef, note int) (*bsclient.Show, error) {
+	return &bsclient.Show{}, f.call("show-note", show.ID)
+}
+
+func (f *fakeTarget) ShowAdd(show bsclient.ShowRef, opts *bsclient.ShowAddOptions) (*bsclient.Show, error) {
+	return &bsclient.Show{}, f.call("add", show.ID)
+}
+
+func (f *fakeTarget) ShowArchive(show bsclient.ShowRef) (*bsclient.Show, error) {
+	return &bsclient.Show{}, f.call("archive", show.ID)
+}
+
+func (f *fakeTarget) ShowFavorite(show bsclient.ShowRef) (*bsclient.Show, error) {
+	return &bsclient.Show{}, f.call("favorite", show.ID)
+}
+
+func watchedOps(n int) []Operation {
+	ops := make([]Operation, n)
+	for i := range ops {
+		ops[i] = Watched(bsclient.EpisodeID(i + 1))
+	}
+	return ops
+}
+
+func (s *MySuite) TestOperations(c *C) {
+	fake := &fakeTarget{}
+	ops := []Operation{
+		Watched(bsclient.EpisodeID(1)),
+		Downloaded(bsclient.EpisodeID(1)),
+		EpisodeNote(bsclient.EpisodeID(1), 4),
+		ShowNote(bsclient.ShowID(2), 5),
+		Add(bsclient.ShowID(2)),
+		Archive(bsclient.ShowID(2)),
+		Favorite(bsclient.ShowID(2)),
+	}
+	report, err := (&Runner{Workers: 1}).run(context.Background(), fake, ops)
+	c.Assert(err, IsNil)
+	c.Assert(report.Succeeded, Equals, 7)
+	c.Assert(fake.calls, DeepEquals, []string{
+		"watched", "downloaded", "episode-note", "show-note", "add", "archive", "favorite",
+	})
+
+	c.Assert(Watched(bsclient.EpisodeID(12)).String(), Equals, "watched episode 12")
+	c.Assert(EpisodeNote(bsclient.EpisodeCode(bsclient.ShowTitle("Lost"), "S01E02"), 4).String(),
+		Equals, `note 4 for S01E02 of show "Lost"`)
+	c.Assert(Add(bsclient.ShowIMDB("tt0903747")).String(), Equals, "add show tt0903747")
+
+	report, err = (&Runner{}).run(context.Background(), fake, []Operation{{Kind: "remove"}})
+	c.Assert(errors.Is(err, errUnknownKind), Equals, true)
+	c.Assert(report.Failed, Equals, 1)
+}
+
+func (s *MySuite) TestBestEffort(c *C) {
+	fake := &fakeTarget{fail: map[int]bool{3: true, 150: true}, delay: time.Millisecond}
+	progress := []int{}
+	r := &Runner{
+		Workers: 8,
+		Progress: func(done, total int, result Outcome) {
+			c.Check(total, Equals, 200)
+			progress = append(progress, done)
+		},
+	}
+	report, err := r.run(context.Background(), fake, watchedOps(200))
+	c.Assert(errors.Is(err, errFake), Equals, true)
+	c.Assert(report.Succeeded, Equals, 198)
+	c.Assert(report.Failed, Equals, 2)
+	c.Assert(report.Skipped, Equals, 0)
+	c.Assert(report.Results[2].Err, Equals, errFake)
+	c.Assert(report.Results[2].Index, Equals, 2)
+	c.Assert(report.Results[149].Err, Equals, errFake)
+	c.Assert(fake.calls, HasLen, 200)
+	c.Assert(fake.maxIn <= 8, Equals, true)
+	c.Assert(fake.maxIn > 1, Equals, true)
+	c.Assert(progress, HasLen, 200)
+	for i, done := range progress {
+		c.Assert(done, Equals, i+1)
+	}
+}
+
+func (s *MySuite) TestStopOnError(c *C) {
+	fake := &fakeTarget{fail: map[int]bool{5: true}}
+	r := &Runner{Workers: 2, StopOnError: true}
+	report, err := r.run(context.Background(), fake, watchedOps(50))
+	c.Assert(errors.Is(err, errFake), Equals, true)
+	c.Assert(report.Failed, Equals, 1)
+	c.Assert(report.Skipped > 0, Equals, true)
+	c.Assert(report.Succeeded+report.Failed+report.Skipped, Equals, 50)
+	c.Assert(report.Results[49].Skipped, Equals, true)
+}
+
+func (s *MySuite) TestCancel(c *C) {
+	fake := &fakeTarget{delay: time.Millisecond}
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Runner{Workers: 1, Progress: func(done, total int, result Outcome) {
+		if done == 3 {
+			cancel()
+		}
+	}}
+	report, err := r.run(ctx, fake, watchedOps(20))
+	c.Assert(err, Equals, context.Canceled)
+	c.Assert(report.Succeeded >= 3, Equals, true)
+	c.Assert(report.Skipped > 0, Equals, true)
+}
@@ -0,0 +1,142 @@
+package batch
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+// Kinds of operations.
+const (
+	OpWatched     = "watched"
+	OpDownloaded  = "downloaded"
+	OpEpisodeNote = "episode-note"
+	OpShowNote    = "show-note"
+	OpAdd         = "add"
+	OpArchive     = "archive"
+	OpFavorite    = "favorite"
+)
+
+// Operation represents a change of an episode or a show of the account.
+type Operation struct {
+	Kind string
+	// Episode is the episode of watched, downloaded and episode-note operations.
+	Episode bsclient.EpisodeRef
+	// Show is the show of the other operations.
+	Show bsclient.ShowRef
+	// Note rates the episode or the show from 1 to 5. It is optional for
+	// watched operations.
+	Note int
+}
+
+// Watched marks an episode as watched, without marking the previous ones.
+func Watched(episode bsclient.EpisodeRef) Operation {
+	return Operation{Kind: OpWatched, Episode: episode}
+}
+
+// Downloaded marks an episode as downloaded.
+func Downloaded(episode bsclient.EpisodeRef) Operation {
+	return Operation{Kind: OpDownloaded, Episode: episode}
+}
+
+// EpisodeNote rates an episode.
+func EpisodeNote(episode bsclient.EpisodeRef, note int) Operation {
+	return Operation{Kind: OpEpisodeNote, Episode: episode, Note: note}
+}
+
+// ShowNote rates a show.
+func ShowNote(show bsclient.ShowRef, note int) Operation {
+	return Operation{Kind: OpShowNote, Show: show, Note: note}
+}
+
+// Add adds a show to the account.
+func Add(show bsclient.ShowRef) Operation {
+	return Operation{Kind: OpAdd, Show: show}
+}
+
+// Archive archives a show.
+func Archive(show bsclient.ShowRef) Operation {
+	return Operation{Kind: OpArchive, Show: show}
+}
+
+// Favorite adds a show to the favorites.
+func Favorite(show bsclient.ShowRef) Operation {
+	return Operation{Kind: OpFavorite, Show: show}
+}
+
+func showString(r bsclient.ShowRef) string {
+	switch {
+	case r.ID != 0:
+		return "show " + strconv.Itoa(r.ID)
+	case r.TVDB != 0:
+		return "show tvdb:" + strconv.Itoa(r.TVDB)
+	case r.IMDB != "":
+		return "show " + r.IMDB
+	case r.TMDB != 0:
+		return "show tmdb:" + strconv.Itoa(r.TMDB)
+	}
+	return fmt.Sprintf("show %q", r.Title)
+}
+
+func episodeString(r bsclient.EpisodeRef) string {
+	switch {
+	case r.ID != 0:
+		return "episode " + strconv.Itoa(r.ID)
+	case r.TVDB != 0:
+		return "episode tvdb:" + strconv.Itoa(r.TVDB)
+	}
+	return r.Code + " of " + showString(r.Show)
+}
+
+// String describes the operation, like 'watched episode 12'.
+func (o Operation) String() string {
+	switch o.Kind {
+	case OpWatched, OpDownloaded:
+		return o.Kind + " " + episodeString(o.Episode)
+	case OpEpisodeNote:
+		return fmt.Sprintf("note %d for %s", o.Note, episodeString(o.Episode))
+	case OpShowNote:
+		return fmt.Sprintf("note %d for %s", o.Note, showString(o.Show))
+	}
+	return o.Kind + " " + showString(o.Show)
+}
+
+// target is the part of bsclient.BetaSeries used by the operations.
+type target interface {
+	EpisodeWatched(episode bsclient.EpisodeRef, opts *bsclient.EpisodeWatchedOptions) (*bsclient.Episode, error)
+	EpisodeDownloaded(episode bsclient.EpisodeRef) (*bsclient.Episode, error)
+	EpisodeNote(episode bsclient.EpisodeRef, note int) (*bsclient.Episode, error)
+	ShowNote(show bsclient.ShowRef, note int) (*bsclient.Show, error)
+	ShowAdd(show bsclient.ShowRef, opts *bsclient.ShowAddOptions) (*bsclient.Show, error)
+	ShowArchive(show bsclient.ShowRef) (*bsclient.Show, error)
+	ShowFavorite(show bsclient.ShowRef) (*bsclient.Show, error)
+}
+
+// apply executes an operation.
+func (o Operation) apply(bs target) error {
+	var err error
+	switch o.Kind {
+	case OpWatched:
+		opts := &bsclient.EpisodeWatchedOptions{Bulk: bsclient.Bool(false)}
+		if o.Note > 0 {
+			opts.Note = bsclient.Int(o.Note)
+		}
+		_, err = bs.EpisodeWatched(o.Episode, opts)
+	case OpDownloaded:
+		_, err = bs.EpisodeDownloaded(o.Episode)
+	case OpEpisodeNote:
+		_, err = bs.EpisodeNote(o.Episode, o.Note)
+	case OpShowNote:
+		_, err = bs.ShowNote(o.Show, o.Note)
+	case OpAdd:
+		_, err = bs.ShowAdd(o.Show, nil)
+	case OpArchive:
+		_, err = bs.ShowArchive(o.Show)
+	case OpFavorite:
+		_, err = bs.ShowFavorite(o.Show)
+	default:
+		err = fmt.Errorf("%w %q", errUnknownKind, o.Kind)
+	}
+	return err
+}
@@ -2,15 +2,18 @@
 package bsclient
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -20,8 +23,9 @@ const (
 )
 
 var (
-	errNoToken    = errors.New("no token")
-	errURLParsing = errors.New("url parsing error")
+	errNoToken       = errors.New("no token")
+	errNoCredentials = errors.New("login and password are required")
+	errURLParsing    = errors.New("url parsing error")
 )
 
 type errorsAPI struct {
@@ -42,6 +46,36 @@ func (e *errAPI) Error() string {
 	return out
 }
 
+// IsNotFound returns true if the error only means that the request returned no items.
+func IsNotFound(err error) bool {
+	switch err {
+	case errNoShowsFound, errNoMembersFound, errNoSubtitlesFound, errNoEpisodesFound,
+		errNoNewsFound, errNoCharactersFound, errNoVideosFound:
+		return true
+	}
+	return false
+}
+
+// IsInvalid returns true if the error comes from invalid parameters,
+// detected before sending any request.
+func IsInvalid(err error) bool {
+	switch err {
+	case errIDMustBeStrictlyPositive, errNoSingleIDUsed, errIDNotProperlySet, errInvalidNote, errNoCredentials:
+		return true
+	}
+	return errors.Is(err, errInvalidOption)
+}
+
+// ErrorCode returns the code of the first error returned by the API,
+// or 0 if 'err' is not an API error.
+func ErrorCode(err error) int {
+	apiErr := &errAPI{}
+	if errors.As(err, &apiErr) && len(apiErr.Errors) > 0 {
+		return apiErr.Errors[0].Code
+	}
+	return 0
+}
+
 // token is a struct return by the betaseries API when requesting a token
 type token struct {
 	User struct {
@@ -54,38 +88,158 @@ type token struct {
 	Errors []interface{} `json:"errors"`
 }
 
-// BetaSeries represents the web client to the BetaSeries API
+// session holds the token of the authenticated member, shared by
+// the copies of a client made by WithContext.
+type session struct {
+	mutex sync.RWMutex
+	token *token
+}
+
+func (s *session) get() *token {
+	if s == nil {
+		return nil
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.token
+}
+
+func (s *session) set(t *token) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.token = t
+}
+
+// BetaSeries represents the web client to the BetaSeries API.
+// A client is safe for concurrent use by multiple goroutines, and is bound
+// to one member at a time: use one client per member, possibly sharing an
+// HTTP client with WithHTTPClient.
 type BetaSeries struct {
-	baseURL    string
-	version    string
-	key        string
-	token      *token
-	httpClient *http.Client
+	baseURL      string
+	version      string
+	key          string
+	session      *session
+	httpClient   *http.Client
+	observers    []Observer
+	interceptors []Interceptor
+	logger       *slog.Logger
+	ctx          context.Context
+}
+
+// WithContext returns a shallow copy of the client whose requests
+// are bound to the given context.
+func (bs *BetaSeries) WithContext(ctx context.Context) *BetaSeries {
+	if ctx == nil {
+		panic("nil context")
+	}
+	client := *bs
+	client.ctx = ctx
+	return &client
+}
+
+func (bs *BetaSeries) context() context.Context {
+	if bs.ctx != nil {
+		return bs.ctx
+	}
+	return context.Background()
 }
 
 func (bs *BetaSeries) getToken() (string, error) {
-	if bs.token != nil {
-		return bs.token.Token, nil
+	if t := bs.session.get(); t != nil {
+		return t.Token, nil
 	}
 	return "", errNoToken
 }
 
-// NewBetaseriesClient creates a betaseries web client
-func NewBetaseriesClient(key, login, password string) (*BetaSeries, error) {
+// Identity returns the id and the login of the authenticated member,
+// or 0 and an empty string if the client is not authenticated.
+func (bs *BetaSeries) Identity() (int, string) {
+	if t := bs.session.get(); t != nil {
+		return t.User.ID, t.User.Login
+	}
+	return 0, ""
+}
+
+// ClientOption configures a client created by NewBetaseriesClient.
+type ClientOption func(bs *BetaSeries)
+
+// WithBaseURL sets the base URL of the API, for instance the one of a proxy.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(bs *BetaSeries) {
+		bs.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sets the HTTP client sending the requests.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(bs *BetaSeries) {
+		bs.httpClient = client
+	}
+}
+
+// Observation describes a request sent to the API.
+type Observation struct {
+	Method string
+	// Endpoint is the path of the API endpoint, like '/shows/display'.
+	Endpoint string
+	// Status is the HTTP status of the response, 0 if none was received.
+	Status   int
+	Duration time.Duration
+	// Header holds the headers of the response, nil if none was received.
+	Header http.Header
+	// Err is the transport or API error of the request, if any.
+	Err error
+}
+
+// Observer is called after every request sent to the API.
+type Observer func(o Observation)
+
+// WithObserver adds a function called after every request, for instance to record metrics.
+func WithObserver(observer Observer) ClientOption {
+	return func(bs *BetaSeries) {
+		bs.observers = append(bs.observers, observer)
+	}
+}
+
+// Interceptor is called before every request sent to the API, 'endpoint'
+// being its path like in Observation. It may set headers of the request, and
+// returns the context to send it with and an observer called once it is done,
+// for instance to trace the request.
+type Interceptor func(req *http.Request, endpoint string) (context.Context, Observer)
+
+// WithInterceptor adds a function called before every request.
+func WithInterceptor(interceptor Interceptor) ClientOption {
+	return func(bs *BetaSeries) {
+		bs.interceptors = append(bs.interceptors, interceptor)
+	}
+}
+
+// NewHTTPClient returns an HTTP client with the timeouts of the clients made
+// by NewBetaseriesClient, to share among several clients with WithHTTPClient.
+func NewHTTPClient() *http.Client {
 	var netTransport = &http.Transport{
 		Dial: (&net.Dialer{
 			Timeout: 5 * time.Second,
 		}).Dial,
 		TLSHandshakeTimeout: 5 * time.Second,
 	}
+	return &http.Client{
+		Timeout:   time.Second * 45,
+		Transport: netTransport,
+	}
+}
+
+// NewBetaseriesClient creates a betaseries web client
+func NewBetaseriesClient(key, login, password string, options ...ClientOption) (*BetaSeries, error) {
 	bs := &BetaSeries{
-		version: bsVersion,
-		baseURL: bsBaseURL,
-		key:     key,
-		httpClient: &http.Client{
-			Timeout:   time.Second * 45,
-			Transport: netTransport,
-		},
+		version:    bsVersion,
+		baseURL:    bsBaseURL,
+		key:        key,
+		session:    &session{},
+		httpClient: NewHTTPClient(),
+	}
+	for _, option := range options {
+		option(bs)
 	}
 	// basic authentication.
 	// TODO: OAUTH 2.0
@@ -97,29 +251,73 @@ func (bs *BetaSeries) doRequest(req *http.Request) (*http.Response, error) {
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("X-BetaSeries-Version", bs.version)
 	req.Header.Set("X-BetaSeries-Key", bs.key)
-	if bs.token != nil {
-		req.Header.Set("X-BetaSeries-Token", bs.token.Token)
+	if t := bs.session.get(); t != nil {
+		req.Header.Set("X-BetaSeries-Token", t.Token)
 	}
 
 	return bs.httpClient.Do(req)
 }
 
 func (bs *BetaSeries) do(method string, u *url.URL) (*http.Response, error) {
-	req, err := http.NewRequest(method, u.String(), nil)
+	req, err := http.NewRequestWithContext(bs.context(), method, u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
+	endpoint := bs.endpoint(req.URL)
+	observers := bs.observers
+	for _, interceptor := range bs.interceptors {
+		ctx, observer := interceptor(req, endpoint)
+		req = req.WithContext(ctx)
+		if observer != nil {
+			observers = append(observers[:len(observers):len(observers)], observer)
+		}
+	}
+	start := time.Now()
 	resp, err := bs.doRequest(req)
 	if err != nil {
+		bs.observe(observers, req, endpoint, nil, start, err)
 		return nil, err
 	}
 	if resp.StatusCode != http.StatusOK {
 		apiErr := decodeErr(resp.Body)
+		bs.observe(observers, req, endpoint, resp, start, apiErr)
 		return nil, apiErr
 	}
+	bs.observe(observers, req, endpoint, resp, start, nil)
 	return resp, nil
 }
 
+// endpoint returns the path of the endpoint of a request URL, without the path of the base URL.
+func (bs *BetaSeries) endpoint(u *url.URL) string {
+	path := u.Path
+	if base, err := url.Parse(bs.baseURL); err == nil {
+		path = strings.TrimPrefix(path, base.Path)
+	}
+	return "/" + strings.TrimPrefix(path, "/")
+}
+
+// observe calls the observers of a request once it is done.
+func (bs *BetaSeries) observe(observers []Observer, req *http.Request, endpoint string,
+	resp *http.Response, start time.Time, err error) {
+	if len(observers) == 0 && bs.logger == nil {
+		return
+	}
+	o := Observation{
+		Method:   req.Method,
+		Endpoint: endpoint,
+		Duration: time.Since(start),
+		Err:      err,
+	}
+	if resp != nil {
+		o.Status = resp.StatusCode
+		o.Header = resp.Header
+	}
+	for _, observer := range observers {
+		observer(o)
+	}
+	bs.log(req, o)
+}
+
 func (bs *BetaSeries) decode(data interface{}, resp *http.Response, usedAPI, query string) error {
 	if err := json.NewDecoder(resp.Body).Decode(data); err != nil {
 		return err
@@ -134,6 +332,15 @@ func decodeErr(r io.Reader) *errAPI {
 	return err
 }
 
+// Login authenticates a member, replacing the token of the client and of
+// its copies made by WithContext.
+func (bs *BetaSeries) Login(login, password string) error {
+	if len(login) == 0 || len(password) == 0 {
+		return errNoCredentials
+	}
+	return bs.retrieveToken(login, password)
+}
+
 func (bs *BetaSeries) retrieveToken(login, password string) error {
 	usedAPI := "/members/auth"
 	if len(login) == 0 || len(password) == 0 {
@@ -142,7 +349,7 @@ func (bs *BetaSeries) retrieveToken(login, password string) error {
 
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
-		log.Fatalln(err)
+		return errURLParsing
 	}
 	q := u.Query()
 	q.Set("login", login)
@@ -160,6 +367,9 @@ func (bs *BetaSeries) retrieveToken(login, password string) error {
 	if err != nil {
 		return err
 	}
-	bs.token = tokenData
+	if bs.session == nil {
+		bs.session = &session{}
+	}
+	bs.session.set(tokenData)
 	return nil
 }
@@ -20,6 +20,7 @@ func (s *MySuite) TestNewBS(c *C) {
 	expected := &BetaSeries{
 		version:    bsVersion,
 		baseURL:    bsBaseURL,
+		session:    &session{},
 		httpClient: bs.httpClient,
 	}
 	c.Assert(bs, DeepEquals, expected)
@@ -32,6 +33,7 @@ func (s *MySuite) TestNewBSGetTokenWithoutAPIKey(c *C) {
 	expected := &BetaSeries{
 		version:    bsVersion,
 		baseURL:    bsBaseURL,
+		session:    &session{},
 		httpClient: bs.httpClient,
 	}
 	c.Assert(bs, DeepEquals, expected)
@@ -61,19 +63,19 @@ func makeClientAndAddShow(c *C) (*BetaSeries, string, int) {
 	key := os.Getenv("BS_API_KEY")
 	bs, err := NewBetaseriesClient(key, "Dev050", "developer")
 	c.Assert(err, IsNil)
-	_, err = bs.EpisodesList(0, 0)
+	_, err = bs.EpisodesList(nil)
 	c.Assert(err, NotNil)
 	// meaning null/nil return
 	c.Assert(err.Error(), Equals, "")
 
-	shows, err := bs.ShowsSearch(tvShowTest)
+	shows, err := bs.ShowsSearch(tvShowTest, nil)
 	c.Assert(err, IsNil)
 	c.Assert(len(shows), Equals, 1)
 
 	// make sure the tv show is not in the user account first
-	bs.ShowRemove(shows[0].ID, 0)
+	bs.ShowRemove(ShowID(shows[0].ID))
 
-	show, err := bs.ShowAdd(shows[0].ID, 0)
+	show, err := bs.ShowAdd(ShowID(shows[0].ID), nil)
 	c.Assert(err, IsNil)
 	c.Assert(show.InAccount, Equals, true)
 	return bs, key, shows[0].ID
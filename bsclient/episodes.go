@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 var (
@@ -23,11 +24,11 @@ type Episode struct {
 		ThetvdbID int    `json:"thetvdb_id"`
 		Title     string `json:"title"`
 	} `json:"show"`
-	Code        string `json:"code"`
-	Global      int    `json:"global"`
-	Special     int    `json:"special"`
-	Description string `json:"description"`
-	Date        string `json:"date"`
+	Code        string    `json:"code"`
+	Global      int       `json:"global"`
+	Special     bool      `json:"special"`
+	Description string    `json:"description"`
+	Date        time.Time `json:"date"`
 	Note        struct {
 		Total int     `json:"total"`
 		Mean  float32 `json:"mean"`
@@ -37,7 +38,7 @@ type Episode struct {
 		Seen       bool `json:"seen"`
 		Downloaded bool `json:"downloaded"`
 	} `json:"user"`
-	Comments  string     `json:"comments"`
+	Comments  int        `json:"comments"`
 	Subtitles []Subtitle `json:"subtitles"`
 }
 
@@ -71,34 +72,21 @@ func (bs *BetaSeries) doGetEpisodes(u *url.URL, usedAPI string) ([]Episode, erro
 	return data.Episodes, nil
 }
 
-// episodeGet returns an episode
+// episodeGet returns an episode, 'q' holding the show or episode reference.
 // Note: scraper and list cannot be requested with this method
-func (bs *BetaSeries) episodeGet(endPoint string, id, theTvdbID int,
-	subtitles bool, number string) (*Episode, error) {
+func (bs *BetaSeries) episodeGet(endPoint string, q url.Values,
+	subtitles bool, extra *RequestOptions) (*Episode, error) {
 	// endPoint can be: display, latest, next, search
 	usedAPI := "/episodes/" + endPoint
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
 		return nil, errURLParsing
 	}
-	q := u.Query()
-
-	if endPoint == "search" {
-		if id > 0 {
-			q.Set("show_id", strconv.Itoa(id))
-		}
-		if number != "" {
-			q.Set("number", number)
-		}
-	} else if id > 0 {
-		q.Set("id", strconv.Itoa(id))
-	} else if theTvdbID > 0 {
-		q.Set("thetvdb_id", strconv.Itoa(theTvdbID))
-	}
 
 	if subtitles {
 		q.Set("subtitles", "true")
 	}
+	extra.applyExtra(q)
 
 	u.RawQuery = q.Encode()
 
@@ -117,7 +105,7 @@ func (bs *BetaSeries) episodeGet(endPoint string, id, theTvdbID int,
 	return episode.Episode, nil
 }
 
-func (bs *BetaSeries) episodeUpdate(method, endpoint string, id, theTvdbID int) (*Episode, error) {
+func (bs *BetaSeries) episodeUpdate(method, endpoint string, ref EpisodeRef) (*Episode, error) {
 	usedAPI := "/episodes/" + endpoint
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
@@ -125,10 +113,8 @@ func (bs *BetaSeries) episodeUpdate(method, endpoint string, id, theTvdbID int)
 	}
 	q := u.Query()
 
-	if id > 0 {
-		q.Set("id", strconv.Itoa(id))
-	} else if theTvdbID > 0 {
-		q.Set("thetvdb_id", strconv.Itoa(theTvdbID))
+	if err := bs.setEpisodeRef(q, ref, episodeParams); err != nil {
+		return nil, err
 	}
 	u.RawQuery = q.Encode()
 
@@ -147,7 +133,10 @@ func (bs *BetaSeries) episodeUpdate(method, endpoint string, id, theTvdbID int)
 	return episode.Episode, nil
 }
 
-func (bs *BetaSeries) episodeUpdateEpisode(endPoint string, id, theTvdbID, note int, bulk, delete bool) (*Episode, error) {
+func (bs *BetaSeries) episodeUpdateEpisode(endPoint string, ref EpisodeRef, opts *EpisodeWatchedOptions) (*Episode, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
 	method := "POST"
 	usedAPI := "/episodes/" + endPoint
 	u, err := url.Parse(bs.baseURL + usedAPI)
@@ -156,22 +145,25 @@ func (bs *BetaSeries) episodeUpdateEpisode(endPoint string, id, theTvdbID, note
 	}
 	q := u.Query()
 
-	if id > 0 {
-		q.Set("id", strconv.Itoa(id))
-	} else if theTvdbID > 0 {
-		q.Set("thetvdb_id", strconv.Itoa(theTvdbID))
+	if err := bs.setEpisodeRef(q, ref, episodeParams); err != nil {
+		return nil, err
 	}
 
 	if endPoint == "watched" {
-		// Note: bulk not optional here since it defaults to true upstream
+		// Note: bulk is always sent since it defaults to true upstream
+		bulk := false
+		if opts.Bulk != nil {
+			bulk = *opts.Bulk
+		}
 		q.Set("bulk", strconv.FormatBool(bulk))
-		if delete {
+		if opts.Delete {
 			q.Set("delete", "true")
 		}
 	}
-	if note > 0 {
-		q.Set("note", strconv.Itoa(note))
+	if opts.Note != nil {
+		q.Set("note", strconv.Itoa(*opts.Note))
 	}
+	opts.applyExtra(q)
 	u.RawQuery = q.Encode()
 
 	resp, err := bs.do(method, u)
@@ -216,57 +208,105 @@ func (bs *BetaSeries) EpisodeScraper(fileName string) (*Episode, error) {
 }
 
 // EpisodeLatest returns the latest episode for a given show
-func (bs *BetaSeries) EpisodeLatest(showID, theTvdbShowID int) (*Episode, error) {
-	return bs.episodeGet("latest", showID, theTvdbShowID, false, "")
+func (bs *BetaSeries) EpisodeLatest(show ShowRef) (*Episode, error) {
+	q := url.Values{}
+	if err := bs.setShowRef(q, show, showTVDBParams); err != nil {
+		return nil, err
+	}
+	return bs.episodeGet("latest", q, false, nil)
 }
 
-// EpisodeDisplay returns the latest episode for a given show
-func (bs *BetaSeries) EpisodeDisplay(showID, theTvdbShowID int, subtitles bool) (*Episode, error) {
-	return bs.episodeGet("display", showID, theTvdbShowID, subtitles, "")
+// EpisodeOptions holds the optional parameters of EpisodeDisplay and EpisodeSearch.
+type EpisodeOptions struct {
+	RequestOptions
+	// Subtitles returns the subtitles of the episode.
+	Subtitles bool
+}
+
+// EpisodeDisplay returns the given episode
+func (bs *BetaSeries) EpisodeDisplay(episode EpisodeRef, opts *EpisodeOptions) (*Episode, error) {
+	if opts == nil {
+		opts = &EpisodeOptions{}
+	}
+	q := url.Values{}
+	if err := bs.setEpisodeRef(q, episode, episodeParams); err != nil {
+		return nil, err
+	}
+	return bs.episodeGet("display", q, opts.Subtitles, &opts.RequestOptions)
 }
 
 // EpisodeNext returns the next episode for a given show
-func (bs *BetaSeries) EpisodeNext(showID, theTvdbShowID int) (*Episode, error) {
-	return bs.episodeGet("next", showID, theTvdbShowID, false, "")
+func (bs *BetaSeries) EpisodeNext(show ShowRef) (*Episode, error) {
+	q := url.Values{}
+	if err := bs.setShowRef(q, show, showTVDBParams); err != nil {
+		return nil, err
+	}
+	return bs.episodeGet("next", q, false, nil)
 }
 
-// EpisodeSearch returns an episode for a given show based on its number
-func (bs *BetaSeries) EpisodeSearch(showID int, subtitles bool, number string) (*Episode, error) {
-	return bs.episodeGet("search", showID, 0, subtitles, number)
+// EpisodeSearch returns an episode for a given show based on its number (S01E01)
+func (bs *BetaSeries) EpisodeSearch(show ShowRef, number string, opts *EpisodeOptions) (*Episode, error) {
+	if opts == nil {
+		opts = &EpisodeOptions{}
+	}
+	q := url.Values{}
+	if err := bs.setShowRef(q, show, refParams{refID: "show_id"}); err != nil {
+		return nil, err
+	}
+	if number != "" {
+		q.Set("number", number)
+	}
+	return bs.episodeGet("search", q, opts.Subtitles, &opts.RequestOptions)
 }
 
-// EpisodeDownloaded marks the episode with the given id as downloaded.
-func (bs *BetaSeries) EpisodeDownloaded(bsID, theTvdbID int) (*Episode, error) {
-	return bs.episodeUpdate("POST", "downloaded", bsID, theTvdbID)
+// EpisodeDownloaded marks the episode as downloaded.
+func (bs *BetaSeries) EpisodeDownloaded(episode EpisodeRef) (*Episode, error) {
+	return bs.episodeUpdate("POST", "downloaded", episode)
 }
 
-// EpisodeNotDownloaded marks the episode with the given id as not downloaded.
-func (bs *BetaSeries) EpisodeNotDownloaded(bsID, theTvdbID int) (*Episode, error) {
-	return bs.episodeUpdate("DELETE", "downloaded", bsID, theTvdbID)
+// EpisodeNotDownloaded marks the episode as not downloaded.
+func (bs *BetaSeries) EpisodeNotDownloaded(episode EpisodeRef) (*Episode, error) {
+	return bs.episodeUpdate("DELETE", "downloaded", episode)
 }
 
-// EpisodeWatched marks the episode with the given id as watched.
-// 'note' is optional (unset if equal to 0)
-// If bulk is true, all previous episodes are marked as watched.
-// If delete is true, latest episodes are not marked as watched.
-func (bs *BetaSeries) EpisodeWatched(bsID, theTvdbID, note int, bulk, delete bool) (*Episode, error) {
-	return bs.episodeUpdateEpisode("watched", bsID, theTvdbID, note, bulk, delete)
+// EpisodeWatchedOptions holds the optional parameters of EpisodeWatched.
+type EpisodeWatchedOptions struct {
+	RequestOptions
+	// Note rates the episode from 1 to 5.
+	Note *int
+	// Bulk marks all previous episodes as watched if true.
+	// Only the given episode is marked if unset.
+	Bulk *bool
+	// Delete unmarks the episodes watched after this one.
+	Delete bool
 }
 
-// EpisodeNotWatched marks the episode with the given id as not watched.
-func (bs *BetaSeries) EpisodeNotWatched(bsID, theTvdbID int) (*Episode, error) {
-	return bs.episodeUpdate("DELETE", "watched", bsID, theTvdbID)
+func (o *EpisodeWatchedOptions) validate() error {
+	if o.Note != nil && (*o.Note < 1 || *o.Note > 5) {
+		return errInvalidNote
+	}
+	return nil
 }
 
-// EpisodeNote sets the note (rating) for the given episode.
-func (bs *BetaSeries) EpisodeNote(bsID, theTvdbID, note int) (*Episode, error) {
-	if note < 1 || note > 5 {
-		return nil, errInvalidNote
+// EpisodeWatched marks the episode as watched.
+func (bs *BetaSeries) EpisodeWatched(episode EpisodeRef, opts *EpisodeWatchedOptions) (*Episode, error) {
+	if opts == nil {
+		opts = &EpisodeWatchedOptions{}
 	}
-	return bs.episodeUpdateEpisode("note", bsID, theTvdbID, note, false, false)
+	return bs.episodeUpdateEpisode("watched", episode, opts)
+}
+
+// EpisodeNotWatched marks the episode as not watched.
+func (bs *BetaSeries) EpisodeNotWatched(episode EpisodeRef) (*Episode, error) {
+	return bs.episodeUpdate("DELETE", "watched", episode)
+}
+
+// EpisodeNote sets the note (rating) for the given episode.
+func (bs *BetaSeries) EpisodeNote(episode EpisodeRef, note int) (*Episode, error) {
+	return bs.episodeUpdateEpisode("note", episode, &EpisodeWatchedOptions{Note: Int(note)})
 }
 
 // EpisodeNoteRemove deletes the current note for the given episode.
-func (bs *BetaSeries) EpisodeNoteRemove(bsID, theTvdbID int) (*Episode, error) {
-	return bs.episodeUpdate("DELETE", "note", bsID, theTvdbID)
+func (bs *BetaSeries) EpisodeNoteRemove(episode EpisodeRef) (*Episode, error) {
+	return bs.episodeUpdate("DELETE", "note", episode)
 }
@@ -13,21 +13,21 @@ var (
 
 func (s *MySuite) TestEpisodesList(c *C) {
 	bs, key, id := makeClientAndAddShow(c)
-	shows, err := bs.EpisodesList(id, -1)
+	shows, err := bs.EpisodesList(&EpisodesListOptions{Show: ShowID(id)})
 	c.Assert(err, IsNil)
 	c.Assert(shows, HasLen, 1)
 
-	show, err := bs.ShowRemove(id, 0)
+	show, err := bs.ShowRemove(ShowID(id))
 	c.Assert(err, IsNil)
 	c.Assert(show.InAccount, Equals, false)
 
-	_, err = bs.EpisodesList(-1, -1)
+	_, err = bs.EpisodesList(&EpisodesListOptions{Show: ShowID(-1)})
 	c.Assert(err, NotNil)
-	c.Assert(err, Equals, errNoShowsFound)
+	c.Assert(err, Equals, errIDMustBeStrictlyPositive)
 
 	bs, err = NewBetaseriesClient(key, "", "")
 	c.Assert(err, IsNil)
-	_, err = bs.EpisodesList(0, 0)
+	_, err = bs.EpisodesList(nil)
 	c.Assert(err, NotNil)
 	c.Assert(err, DeepEquals, &errAPI{
 		[]errorsAPI{err2001},
@@ -36,41 +36,41 @@ func (s *MySuite) TestEpisodesList(c *C) {
 
 func (s *MySuite) TestEpisodesDownloaded(c *C) {
 	bs, _, id := makeClientAndAddShow(c)
-	shows, err := bs.EpisodesList(id, -1)
+	shows, err := bs.EpisodesList(&EpisodesListOptions{Show: ShowID(id)})
 	c.Assert(err, IsNil)
 	c.Assert(shows, HasLen, 1)
 	c.Assert(shows[0].Unseen, HasLen, 62)
 
-	episode, err := bs.EpisodeDownloaded(shows[0].Unseen[0].ID)
+	episode, err := bs.EpisodeDownloaded(EpisodeID(shows[0].Unseen[0].ID))
 	c.Assert(err, IsNil)
 	c.Assert(episode.User.Downloaded, Equals, true)
 
-	episode, err = bs.EpisodeNotDownloaded(shows[0].Unseen[0].ID)
+	episode, err = bs.EpisodeNotDownloaded(EpisodeID(shows[0].Unseen[0].ID))
 	c.Assert(err, IsNil)
 	c.Assert(episode.User.Downloaded, Equals, false)
 
-	show, err := bs.ShowRemove(id, 0)
+	show, err := bs.ShowRemove(ShowID(id))
 	c.Assert(err, IsNil)
 	c.Assert(show.InAccount, Equals, false)
 }
 
 func (s *MySuite) TestEpisodesWatched(c *C) {
 	bs, _, id := makeClientAndAddShow(c)
-	shows, err := bs.EpisodesList(id, -1)
+	shows, err := bs.EpisodesList(&EpisodesListOptions{Show: ShowID(id)})
 	println("unseen:", len(shows[0].Unseen))
 	c.Assert(err, IsNil)
 	c.Assert(shows, HasLen, 1)
 	c.Assert(shows[0].Unseen, HasLen, 62)
 
-	episode, err := bs.EpisodeWatched(shows[0].Unseen[0].ID)
+	episode, err := bs.EpisodeWatched(shows[0].Unseen[0].Ref(), nil)
 	c.Assert(err, IsNil)
 	c.Assert(episode.User.Seen, Equals, true)
 
-	episode, err = bs.EpisodeNotWatched(shows[0].Unseen[0].ID)
+	episode, err = bs.EpisodeNotWatched(EpisodeID(shows[0].Unseen[0].ID))
 	c.Assert(err, IsNil)
 	c.Assert(episode.User.Seen, Equals, false)
 
-	show, err := bs.ShowRemove(id, 0)
+	show, err := bs.ShowRemove(ShowID(id))
 	c.Assert(err, IsNil)
 	c.Assert(show.InAccount, Equals, false)
 }
@@ -30,21 +30,43 @@ func (bs *BetaSeries) friendUpdate(method, endpoint string, id int) (*Member, er
 	return friend.Member, nil
 }
 
+// FriendsListOptions holds the optional parameters of FriendsList.
+type FriendsListOptions struct {
+	RequestOptions
+	// ID lists the friends of the given member instead of the authenticated one.
+	ID *int
+	// Blocked returns the list of blocked users. It can't be used along with ID.
+	Blocked bool
+}
+
+func (o *FriendsListOptions) validate() error {
+	if o.Blocked && o.ID != nil {
+		return invalidOption("'blocked' can't be used with 'id'")
+	}
+	return checkPositive("id", o.ID)
+}
+
 // FriendsList lists a member's friends
-// If 'blocked' is true, return the list of blocked users (only if id not set)
-func (bs *BetaSeries) FriendsList(id int, blocked bool) ([]Member, error) {
+func (bs *BetaSeries) FriendsList(opts *FriendsListOptions) ([]Member, error) {
+	if opts == nil {
+		opts = &FriendsListOptions{}
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
 	usedAPI := "/friends/list"
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
 		return nil, errURLParsing
 	}
 	q := u.Query()
-	if id > 0 {
-		q.Set("id", strconv.Itoa(id))
+	if opts.ID != nil {
+		q.Set("id", strconv.Itoa(*opts.ID))
 	}
-	if blocked {
+	if opts.Blocked {
 		q.Set("blocked", "true")
 	}
+	opts.applyExtra(q)
 	u.RawQuery = q.Encode()
 
 	return bs.doGetUsers(u, usedAPI)
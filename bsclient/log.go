@@ -0,0 +1,74 @@
+package bsclient
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// redacted replaces the secrets in the logs.
+const redacted = "REDACTED"
+
+var (
+	// secretParams are the query parameters holding secrets.
+	secretParams = []string{"key", "token", "password"}
+	// secretHeaders are the headers holding secrets.
+	secretHeaders = []string{"X-BetaSeries-Key", "X-BetaSeries-Token", "Authorization"}
+)
+
+// WithLogger logs every request at debug level with the given logger,
+// the API key, the token and the password being redacted.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(bs *BetaSeries) {
+		bs.logger = logger
+	}
+}
+
+// redactURL returns a URL whose secret query parameters are redacted.
+func redactURL(u *url.URL) string {
+	q := u.Query()
+	found := false
+	for _, param := range secretParams {
+		if q.Has(param) {
+			q.Set(param, redacted)
+			found = true
+		}
+	}
+	if !found {
+		return u.String()
+	}
+	out := *u
+	out.RawQuery = q.Encode()
+	return out.String()
+}
+
+// redactHeader returns a copy of headers whose secrets are redacted.
+func redactHeader(header http.Header) http.Header {
+	out := header.Clone()
+	for _, name := range secretHeaders {
+		if out.Get(name) != "" {
+			out.Set(name, redacted)
+		}
+	}
+	return out
+}
+
+// log logs a request at debug level, if the client has a logger.
+func (bs *BetaSeries) log(req *http.Request, o Observation) {
+	if bs.logger == nil || !bs.logger.Enabled(req.Context(), slog.LevelDebug) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", o.Method),
+		slog.String("endpoint", o.Endpoint),
+		slog.String("url", redactURL(req.URL)),
+		slog.Any("headers", redactHeader(req.Header)),
+		slog.Int("status", o.Status),
+		slog.Duration("duration", o.Duration),
+	}
+	if o.Err != nil {
+		attrs = append(attrs, slog.String("error", strings.TrimSpace(o.Err.Error())))
+	}
+	bs.logger.LogAttrs(req.Context(), slog.LevelDebug, "betaseries request", attrs...)
+}
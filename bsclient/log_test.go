@@ -0,0 +1,71 @@
+package bsclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestRedact(c *C) {
+	u, err := url.Parse("https://api.betaseries.com/members/auth?login=me&password=5f4dcc3b&key=secret")
+	c.Assert(err, IsNil)
+	c.Assert(redactURL(u), Equals, "https://api.betaseries.com/members/auth?key=REDACTED&login=me&password=REDACTED")
+	c.Assert(u.RawQuery, Equals, "login=me&password=5f4dcc3b&key=secret")
+
+	u, err = url.Parse("https://api.betaseries.com/shows/display?id=1")
+	c.Assert(err, IsNil)
+	c.Assert(redactURL(u), Equals, "https://api.betaseries.com/shows/display?id=1")
+
+	header := http.Header{}
+	header.Set("X-BetaSeries-Key", "secret")
+	header.Set("X-BetaSeries-Version", "2.4")
+	c.Assert(redactHeader(header), DeepEquals, http.Header{
+		"X-Betaseries-Key":     {"REDACTED"},
+		"X-Betaseries-Version": {"2.4"},
+	})
+	c.Assert(header.Get("X-BetaSeries-Key"), Equals, "secret")
+}
+
+func (s *MySuite) TestLogger(c *C) {
+	bs, api := fakeAPI(map[string]string{
+		"/members/auth": `{"token": "secret-token"}`,
+		"/shows/list":   `{"shows": [{"id": 1}]}`,
+	})
+	defer api.Close()
+	out := &bytes.Buffer{}
+	bs.key = "secret-key"
+	WithLogger(slog.New(slog.NewJSONHandler(out, &slog.HandlerOptions{Level: slog.LevelDebug})))(bs)
+
+	c.Assert(bs.retrieveToken("me", "password"), IsNil)
+	_, err := bs.ShowsList(nil)
+	c.Assert(err, IsNil)
+	_, err = bs.ShowsRandom(nil)
+	c.Assert(err, NotNil)
+
+	logs := out.String()
+	c.Assert(strings.Contains(logs, "secret"), Equals, false)
+	c.Assert(strings.Contains(logs, "5f4dcc3b"), Equals, false) // md5 of the password
+	lines := strings.Split(strings.TrimSpace(logs), "\n")
+	c.Assert(lines, HasLen, 3)
+	entry := map[string]interface{}{}
+	c.Assert(json.Unmarshal([]byte(lines[1]), &entry), IsNil)
+	c.Assert(entry["level"], Equals, "DEBUG")
+	c.Assert(entry["endpoint"], Equals, "/shows/list")
+	c.Assert(entry["status"], Equals, 200.0)
+	c.Assert(entry["duration"], NotNil)
+	c.Assert(json.Unmarshal([]byte(lines[2]), &entry), IsNil)
+	c.Assert(entry["status"], Equals, 404.0)
+	c.Assert(entry["error"], NotNil)
+
+	// nothing is logged above the debug level
+	out.Reset()
+	WithLogger(slog.New(slog.NewJSONHandler(out, nil)))(bs)
+	_, err = bs.ShowsList(nil)
+	c.Assert(err, IsNil)
+	c.Assert(out.Len(), Equals, 0)
+}
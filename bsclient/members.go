@@ -112,8 +112,21 @@ func (bs *BetaSeries) doGetMembers(u *url.URL, usedAPI string) ([]Member, error)
 }
 */
 
+// MembersSearchOptions holds the optional parameters of MembersSearch.
+type MembersSearchOptions struct {
+	RequestOptions
+	// Limit is the maximum size of the returned slice.
+	Limit *int
+}
+
 // MembersSearch search for members. 'login' can contain the wildcard '%'
-func (bs *BetaSeries) MembersSearch(login string, limit int) ([]Member, error) {
+func (bs *BetaSeries) MembersSearch(login string, opts *MembersSearchOptions) ([]Member, error) {
+	if opts == nil {
+		opts = &MembersSearchOptions{}
+	}
+	if err := checkPositive("limit", opts.Limit); err != nil {
+		return nil, err
+	}
 	usedAPI := "/members/search"
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
@@ -121,34 +134,62 @@ func (bs *BetaSeries) MembersSearch(login string, limit int) ([]Member, error) {
 	}
 	q := u.Query()
 	q.Set("login", login)
-	if limit > 0 {
-		q.Set("limit", strconv.Itoa(limit))
+	if opts.Limit != nil {
+		q.Set("limit", strconv.Itoa(*opts.Limit))
 	}
+	opts.applyExtra(q)
 	u.RawQuery = q.Encode()
 
 	return bs.doGetUsers(u, usedAPI)
 }
 
+// MembersInfosOptions holds the optional parameters of MembersInfos.
+type MembersInfosOptions struct {
+	RequestOptions
+	// ID is the member to describe, instead of the authenticated one.
+	ID *int
+	// Summary returns no data about movies and shows.
+	Summary bool
+	// Only restricts the returned data to 'movies' or 'shows'.
+	// It can't be used along with Summary.
+	Only string
+}
+
+func (o *MembersInfosOptions) validate() error {
+	if o.Summary && o.Only != "" {
+		return invalidOption("'only' can't be used with 'summary'")
+	}
+	return firstError(
+		checkPositive("id", o.ID),
+		checkEnum("only", o.Only, "movies", "shows"),
+	)
+}
+
 // MembersInfos returns member information about the given user (or the
 // authenticated user if id is not set).
-// If summary is true, no data about movies and shows is returns.
-// If summary is false, only can optionally be set to 'movies' or 'shows'.
-func (bs *BetaSeries) MembersInfos(id int, summary bool, only string) (*Member, error) {
+func (bs *BetaSeries) MembersInfos(opts *MembersInfosOptions) (*Member, error) {
+	if opts == nil {
+		opts = &MembersInfosOptions{}
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
 	usedAPI := "/members/infos"
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
 		return nil, errURLParsing
 	}
 	q := u.Query()
-	if id > 0 {
-		q.Set("id", strconv.Itoa(id))
+	if opts.ID != nil {
+		q.Set("id", strconv.Itoa(*opts.ID))
 	}
-
-	if summary {
+	if opts.Summary {
 		q.Set("summary", "true")
-	} else if only != "" {
-		q.Set("only", only)
 	}
+	if opts.Only != "" {
+		q.Set("only", opts.Only)
+	}
+	opts.applyExtra(q)
 	u.RawQuery = q.Encode()
 
 	resp, err := bs.do("GET", u)
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 var (
@@ -12,11 +13,11 @@ var (
 
 // News represents a news of a particular tv show
 type News struct {
-	ID         string `json:"id"`
-	Title      string `json:"title"`
-	URL        string `json:"url"`
-	PictureURL string `json:"picture_url"`
-	Date       string `json:"date"`
+	ID         string    `json:"id"`
+	Title      string    `json:"title"`
+	URL        string    `json:"url"`
+	PictureURL string    `json:"picture_url"`
+	Date       time.Time `json:"date"`
 }
 
 type news struct {
@@ -24,18 +25,34 @@ type news struct {
 	Errors []interface{} `json:"errors"`
 }
 
+// NewsLastOptions holds the optional parameters of NewsLast.
+type NewsLastOptions struct {
+	RequestOptions
+	// Number is the maximum number of news (10 by default).
+	Number *int
+	// Tailored returns tv show news of the identified member.
+	Tailored bool
+}
+
 // NewsLast returns a slice of news of tv shows
-// If 'number' is strictly negative, it returns a default of 10 news maximum.
-// The 'tailored' parameter returns tv show news of the identified member.
-func (bs *BetaSeries) NewsLast(number int, tailored bool) ([]News, error) {
+func (bs *BetaSeries) NewsLast(opts *NewsLastOptions) ([]News, error) {
+	if opts == nil {
+		opts = &NewsLastOptions{}
+	}
+	if err := checkPositive("number", opts.Number); err != nil {
+		return nil, err
+	}
 	usedAPI := "/news/last"
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
 		return nil, errURLParsing
 	}
 	q := u.Query()
-	q.Set("number", strconv.Itoa(number))
-	q.Set("tailored", strconv.FormatBool(tailored))
+	if opts.Number != nil {
+		q.Set("number", strconv.Itoa(*opts.Number))
+	}
+	q.Set("tailored", strconv.FormatBool(opts.Tailored))
+	opts.applyExtra(q)
 	u.RawQuery = q.Encode()
 
 	resp, err := bs.do("GET", u)
@@ -11,11 +11,11 @@ func (s *MySuite) TestNewsLast(c *C) {
 	key := os.Getenv("BS_API_KEY")
 	bs, err := NewBetaseriesClient(key, "", "")
 	c.Assert(err, IsNil)
-	news, err := bs.NewsLast(1, false)
+	news, err := bs.NewsLast(&NewsLastOptions{Number: Int(1)})
 	if len(news) > 0 {
 		c.Assert(err, IsNil)
 		c.Assert(len(news), Equals, 1)
-		c.Assert(len(news[0].Date), Equals, 19)
+		c.Assert(news[0].Date.IsZero(), Equals, false)
 		c.Assert(strings.Contains(news[0].URL, "http"), Equals, true)
 		c.Assert(strings.Contains(news[0].PictureURL, "http"), Equals, true)
 	} else {
@@ -0,0 +1,84 @@
+package bsclient
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+var (
+	errInvalidOption = errors.New("invalid option")
+
+	reOptionDate  = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	reOptionMonth = regexp.MustCompile(`^\d{4}-\d{2}$`)
+)
+
+// RequestOptions holds the options shared by every request option struct.
+type RequestOptions struct {
+	// Extra holds raw query parameters added to the request, for API
+	// parameters not supported by the client. They override the other options.
+	Extra url.Values
+}
+
+func (o *RequestOptions) applyExtra(q url.Values) {
+	if o == nil {
+		return
+	}
+	for key, values := range o.Extra {
+		q[key] = values
+	}
+}
+
+// Int returns a pointer to the given int, to set optional int fields.
+func Int(v int) *int {
+	return &v
+}
+
+// Bool returns a pointer to the given bool, to set optional bool fields.
+func Bool(v bool) *bool {
+	return &v
+}
+
+func invalidOption(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", errInvalidOption, fmt.Sprintf(format, args...))
+}
+
+// checkEnum returns an error if 'value' is set and not one of the allowed values.
+func checkEnum(name, value string, allowed ...string) error {
+	if value == "" {
+		return nil
+	}
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	return invalidOption("'%s' must be one of %v", name, allowed)
+}
+
+// checkPositive returns an error if 'value' is set and not strictly positive.
+func checkPositive(name string, value *int) error {
+	if value != nil && *value <= 0 {
+		return invalidOption("'%s' must be strictly positive", name)
+	}
+	return nil
+}
+
+// checkNotNegative returns an error if 'value' is set and negative.
+func checkNotNegative(name string, value *int) error {
+	if value != nil && *value < 0 {
+		return invalidOption("'%s' must not be negative", name)
+	}
+	return nil
+}
+
+// firstError returns the first non nil error.
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
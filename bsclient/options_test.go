@@ -0,0 +1,228 @@
+package bsclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	. "gopkg.in/check.v1"
+)
+
+// lastQuery returns the query of the last recorded request.
+func lastQuery(c *C, requests []string) url.Values {
+	c.Assert(requests, Not(HasLen), 0)
+	_, request, _ := strings.Cut(requests[len(requests)-1], " ")
+	u, err := url.Parse(request)
+	c.Assert(err, IsNil)
+	return u.Query()
+}
+
+func (s *MySuite) TestRequestOptionsQuery(c *C) {
+	bs, api := fakeAPI(map[string]string{
+		"/shows/list":        `{"shows": [{"id": 1}]}`,
+		"/shows/random":      `{"shows": [{"id": 1}]}`,
+		"/shows/favorites":   `{"shows": [{"id": 1}]}`,
+		"/shows/show":        `{"show": {"id": 1}}`,
+		"/subtitles/episode": `{"subtitles": [{"id": 1, "language": "VF"}, {"id": 2, "language": "VO"}, {"id": 3, "language": "VF"}]}`,
+		"/episodes/watched":  `{"episode": {"id": 1}}`,
+	})
+	defer api.Close()
+
+	_, err := bs.ShowsList(nil)
+	c.Assert(err, IsNil)
+	c.Assert(lastQuery(c, api.Requests()), DeepEquals, url.Values{"order": {"popularity"}})
+
+	_, err = bs.ShowsList(&ShowsListOptions{
+		Order: "followers",
+		Start: Int(0),
+		Limit: Int(10),
+		RequestOptions: RequestOptions{
+			Extra: url.Values{"fields": {"id,title"}, "limit": {"20"}},
+		},
+	})
+	c.Assert(err, IsNil)
+	query := lastQuery(c, api.Requests())
+	c.Assert(query.Get("order"), Equals, "followers")
+	c.Assert(query.Get("start"), Equals, "0")
+	c.Assert(query.Get("fields"), Equals, "id,title")
+	// extra parameters override the other options
+	c.Assert(query.Get("limit"), Equals, "20")
+
+	_, err = bs.ShowsRandom(&ShowsRandomOptions{Number: Int(3), Summary: true})
+	c.Assert(err, IsNil)
+	query = lastQuery(c, api.Requests())
+	c.Assert(query.Get("nb"), Equals, "3")
+	c.Assert(query.Get("summary"), Equals, "true")
+
+	_, err = bs.ShowsFavorites(nil)
+	c.Assert(err, IsNil)
+	c.Assert(lastQuery(c, api.Requests()), DeepEquals, url.Values{})
+	_, err = bs.ShowsFavorites(&ShowsFavoritesOptions{ID: Int(42)})
+	c.Assert(err, IsNil)
+	c.Assert(lastQuery(c, api.Requests()).Get("id"), Equals, "42")
+
+	_, err = bs.ShowAdd(ShowID(1), &ShowAddOptions{
+		LastEpisodeID:  Int(10),
+		RequestOptions: RequestOptions{Extra: url.Values{"archive": {"true"}}},
+	})
+	c.Assert(err, IsNil)
+	query = lastQuery(c, api.Requests())
+	c.Assert(query.Get("episode_id"), Equals, "10")
+	c.Assert(query.Get("archive"), Equals, "true")
+
+	ranked, err := bs.SubtitlesBest(EpisodeID(1), "Show.S01E01.mkv", &SubtitlesBestOptions{
+		Preferences: &SubtitlePreferences{Languages: []string{"vf"}},
+		Max:         Int(1),
+	})
+	c.Assert(err, IsNil)
+	c.Assert(ranked, HasLen, 1)
+	c.Assert(lastQuery(c, api.Requests()).Get("language"), Equals, "all")
+	ranked, err = bs.SubtitlesBest(EpisodeID(1), "Show.S01E01.mkv", nil)
+	c.Assert(err, IsNil)
+	c.Assert(ranked, HasLen, 3)
+
+	// only the given episode is marked unless bulk is asked for
+	_, err = bs.EpisodeWatched(EpisodeID(1), nil)
+	c.Assert(err, IsNil)
+	c.Assert(lastQuery(c, api.Requests()).Get("bulk"), Equals, "false")
+	_, err = bs.EpisodeWatched(EpisodeID(1), &EpisodeWatchedOptions{Bulk: Bool(true)})
+	c.Assert(err, IsNil)
+	c.Assert(lastQuery(c, api.Requests()).Get("bulk"), Equals, "true")
+}
+
+func (s *MySuite) TestRequestOptionsValidation(c *C) {
+	bs := &BetaSeries{}
+	invalid := []error{}
+	_, err := bs.ShowsList(&ShowsListOptions{Order: "random"})
+	invalid = append(invalid, err)
+	_, err = bs.ShowsSearch("test", &ShowsSearchOptions{Order: "date"})
+	invalid = append(invalid, err)
+	_, err = bs.ShowsEpisodes(ShowID(1), &ShowsEpisodesOptions{Episode: Int(1)})
+	invalid = append(invalid, err)
+	_, err = bs.PicturesShows(ShowID(1), &PicturesOptions{Width: Int(100)})
+	invalid = append(invalid, err)
+	_, err = bs.PlanningGeneral(&PlanningGeneralOptions{Date: "01/01/2016"})
+	invalid = append(invalid, err)
+	_, err = bs.PlanningMember(&PlanningMemberOptions{Month: "2016"})
+	invalid = append(invalid, err)
+	_, err = bs.SubtitlesLast(&SubtitlesLastOptions{Number: Int(101)})
+	invalid = append(invalid, err)
+	_, err = bs.ShowsFavorites(&ShowsFavoritesOptions{ID: Int(0)})
+	invalid = append(invalid, err)
+	_, err = bs.SubtitlesBest(EpisodeID(1), "Show.S01E01.mkv", &SubtitlesBestOptions{Max: Int(0)})
+	invalid = append(invalid, err)
+	for _, err := range invalid {
+		c.Assert(err, NotNil)
+		c.Assert(errors.Is(err, errInvalidOption), Equals, true, Commentf("%v", err))
+	}
+
+	_, err = bs.EpisodeWatched(EpisodeID(1), &EpisodeWatchedOptions{Note: Int(6)})
+	c.Assert(err, Equals, errInvalidNote)
+}
+
+func (s *MySuite) TestErrorHelpers(c *C) {
+	_, err := (&BetaSeries{}).ShowsList(&ShowsListOptions{Order: "wrong"})
+	c.Assert(IsInvalid(err), Equals, true)
+	c.Assert(IsInvalid(errIDNotProperlySet), Equals, true)
+	c.Assert(IsInvalid(errNoShowsFound), Equals, false)
+
+	apiErr := &errAPI{Errors: []errorsAPI{{Code: 4001, Text: "not found"}}}
+	c.Assert(ErrorCode(apiErr), Equals, 4001)
+	c.Assert(ErrorCode(fmt.Errorf("wrapped: %w", apiErr)), Equals, 4001)
+	c.Assert(ErrorCode(errNoShowsFound), Equals, 0)
+}
+
+func (s *MySuite) TestObserver(c *C) {
+	bs, api := fakeAPI(map[string]string{
+		"/shows/list": `{"shows": [{"id": 1}]}`,
+	})
+	defer api.Close()
+	observations := []Observation{}
+	WithObserver(func(o Observation) {
+		observations = append(observations, o)
+	})(bs)
+
+	_, err := bs.ShowsList(nil)
+	c.Assert(err, IsNil)
+	_, err = bs.ShowsRandom(nil)
+	c.Assert(err, NotNil)
+	c.Assert(observations, HasLen, 2)
+	c.Assert(observations[0].Method, Equals, "GET")
+	c.Assert(observations[0].Endpoint, Equals, "/shows/list")
+	c.Assert(observations[0].Status, Equals, 200)
+	c.Assert(observations[0].Err, IsNil)
+	c.Assert(observations[1].Endpoint, Equals, "/shows/random")
+	c.Assert(observations[1].Status, Equals, 404)
+	c.Assert(observations[1].Err, NotNil)
+}
+
+func (s *MySuite) TestInterceptor(c *C) {
+	bs, api := fakeAPI(map[string]string{
+		"/shows/display": `{"show": {"id": 1}}`,
+	})
+	defer api.Close()
+	type key struct{}
+	endpoints := []string{}
+	WithInterceptor(func(req *http.Request, endpoint string) (context.Context, Observer) {
+		req.Header.Set("traceparent", "00-1-2-01")
+		endpoints = append(endpoints, endpoint)
+		return context.WithValue(req.Context(), key{}, endpoint), func(o Observation) {
+			endpoints = append(endpoints, o.Endpoint+" done")
+		}
+	})(bs)
+	bs.httpClient.Transport = roundTripper(func(req *http.Request) (*http.Response, error) {
+		c.Check(req.Header.Get("traceparent"), Equals, "00-1-2-01")
+		c.Check(req.Context().Value(key{}), Equals, "/shows/display")
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	_, err := bs.ShowDisplay(ShowID(1))
+	c.Assert(err, IsNil)
+	c.Assert(endpoints, DeepEquals, []string{"/shows/display", "/shows/display done"})
+}
+
+// roundTripper sends the requests with a function.
+type roundTripper func(req *http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func (s *MySuite) TestLoginConcurrency(c *C) {
+	bs, api := fakeAPI(map[string]string{
+		"/members/auth": `{"user": {"id": 7, "login": "walter"}, "token": "a1b2c3"}`,
+		"/shows/list":   `{"shows": [{"id": 1}]}`,
+	})
+	defer api.Close()
+	c.Assert(bs.Login("", "password"), Equals, errNoCredentials)
+	id, login := bs.Identity()
+	c.Assert(id, Equals, 0)
+	c.Assert(login, Equals, "")
+
+	// copies share the session of the client
+	copy := bs.WithContext(context.Background())
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			c.Check(copy.Login("walter", "password"), IsNil)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := bs.ShowsList(nil)
+			c.Check(err, IsNil)
+		}()
+	}
+	wg.Wait()
+	id, login = bs.Identity()
+	c.Assert(id, Equals, 7)
+	c.Assert(login, Equals, "walter")
+	token, err := bs.getToken()
+	c.Assert(err, IsNil)
+	c.Assert(token, Equals, "a1b2c3")
+}
@@ -0,0 +1,151 @@
+package bsclient
+
+import (
+	"context"
+	"iter"
+)
+
+const (
+	// DefaultPageSize is the number of items requested per page by the iterators
+	// when the given page size is 0 or negative.
+	DefaultPageSize = 100
+
+	maxShowsSearchPageSize = 100
+	maxSubtitlesLast       = 100
+)
+
+// pageFetcher returns the items of the page beginning at 'start'.
+type pageFetcher[T any] func(bs *BetaSeries, start, size int) ([]T, error)
+
+// paginate lazily requests the pages returned by 'fetch' until a page is not full.
+// It stops on the first error, including the cancellation of the context.
+func paginate[T any](ctx context.Context, bs *BetaSeries, size int, fetch pageFetcher[T]) iter.Seq2[T, error] {
+	if size <= 0 {
+		size = DefaultPageSize
+	}
+	return func(yield func(T, error) bool) {
+		var zero T
+		client := bs.WithContext(ctx)
+		for start := 0; ; start += size {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			items, err := fetch(client, start, size)
+			if IsNotFound(err) {
+				return
+			} else if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if len(items) < size {
+				return
+			}
+		}
+	}
+}
+
+// growingPage skips the items of the previous pages, emulating pagination on
+// endpoints only accepting a maximum number of items.
+func growingPage[T any](items []T, start int) []T {
+	if len(items) <= start {
+		return nil
+	}
+	return items[start:]
+}
+
+// ShowsListAll iterates over every show returned by ShowsList,
+// requesting pages of 'pageSize' shows only when needed.
+// The Start and Limit options are ignored.
+func (bs *BetaSeries) ShowsListAll(ctx context.Context, opts *ShowsListOptions, pageSize int) iter.Seq2[Show, error] {
+	return paginate(ctx, bs, pageSize, func(client *BetaSeries, start, size int) ([]Show, error) {
+		// each page has its own options, the sequence may be ranged concurrently
+		page := ShowsListOptions{}
+		if opts != nil {
+			page = *opts
+		}
+		page.Start, page.Limit = Int(start), Int(size)
+		return client.ShowsList(&page)
+	})
+}
+
+// ShowsSearchAll iterates over every show found with the given query,
+// going beyond the 100 results of ShowsSearch.
+// The page size can't be higher than 100 with current API.
+func (bs *BetaSeries) ShowsSearchAll(ctx context.Context, query string, opts *ShowsSearchOptions, pageSize int) iter.Seq2[Show, error] {
+	if pageSize <= 0 || pageSize > maxShowsSearchPageSize {
+		pageSize = maxShowsSearchPageSize
+	}
+	return paginate(ctx, bs, pageSize, func(client *BetaSeries, start, size int) ([]Show, error) {
+		return client.showsSearch(query, opts, start/size+1, size)
+	})
+}
+
+// MembersSearchAll iterates over every member found with the given login.
+// The API has no offset for this endpoint: each page requests the previous
+// ones again, so prefer a large page size.
+func (bs *BetaSeries) MembersSearchAll(ctx context.Context, login string, pageSize int) iter.Seq2[Member, error] {
+	return paginate(ctx, bs, pageSize, func(client *BetaSeries, start, size int) ([]Member, error) {
+		members, err := client.MembersSearch(login, &MembersSearchOptions{Limit: Int(start + size)})
+		return growingPage(members, start), err
+	})
+}
+
+// SubtitlesLastAll iterates over the last BetaSeries subtitles.
+// The API has no offset for this endpoint and returns at most 100 subtitles:
+// each page requests the previous ones again, so prefer a large page size.
+func (bs *BetaSeries) SubtitlesLastAll(ctx context.Context, opts *SubtitlesOptions, pageSize int) iter.Seq2[Subtitle, error] {
+	return paginate(ctx, bs, pageSize, func(client *BetaSeries, start, size int) ([]Subtitle, error) {
+		if start >= maxSubtitlesLast {
+			return nil, nil
+		}
+		number := start + size
+		if number > maxSubtitlesLast {
+			number = maxSubtitlesLast
+		}
+		page := SubtitlesLastOptions{}
+		if opts != nil {
+			page.SubtitlesOptions = *opts
+		}
+		page.Number = Int(number)
+		subtitles, err := client.SubtitlesLast(&page)
+		return growingPage(subtitles, start), err
+	})
+}
+
+// Channel runs the given iterator in a goroutine and sends its items on
+// the returned channel, which is closed at the end of the iteration.
+// The first error, if any, is then sent on the error channel.
+// Cancelling the context or calling the returned stop function ends the
+// iteration before the next item, and so before the next page is requested.
+// One of them must be used if the items are not all read, otherwise the
+// goroutine blocks forever.
+func Channel[T any](ctx context.Context, seq iter.Seq2[T, error]) (<-chan T, <-chan error, func()) {
+	ctx, stop := context.WithCancel(ctx)
+	items := make(chan T)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(items)
+		for item, err := range seq {
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case items <- item:
+			case <-ctx.Done():
+			}
+			if err := ctx.Err(); err != nil {
+				errs <- err
+				return
+			}
+		}
+	}()
+	return items, errs, stop
+}
@@ -0,0 +1,124 @@
+package bsclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	. "gopkg.in/check.v1"
+)
+
+func fakePages(total int, failAt int) pageFetcher[int] {
+	return func(bs *BetaSeries, start, size int) ([]int, error) {
+		if failAt >= 0 && start >= failAt {
+			return nil, errors.New("page error")
+		}
+		if start >= total {
+			return nil, errNoShowsFound
+		}
+		items := []int{}
+		for i := start; i < start+size && i < total; i++ {
+			items = append(items, i)
+		}
+		return items, nil
+	}
+}
+
+func (s *MySuite) TestPaginate(c *C) {
+	bs := &BetaSeries{}
+	items := []int{}
+	for item, err := range paginate(context.Background(), bs, 3, fakePages(10, -1)) {
+		c.Assert(err, IsNil)
+		items = append(items, item)
+	}
+	c.Assert(items, DeepEquals, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+
+	// a full last page is followed by an empty one
+	items = []int{}
+	for item, err := range paginate(context.Background(), bs, 5, fakePages(10, -1)) {
+		c.Assert(err, IsNil)
+		items = append(items, item)
+	}
+	c.Assert(items, HasLen, 10)
+
+	var last error
+	items = []int{}
+	for item, err := range paginate(context.Background(), bs, 3, fakePages(10, 6)) {
+		if err != nil {
+			last = err
+			continue
+		}
+		items = append(items, item)
+	}
+	c.Assert(items, HasLen, 6)
+	c.Assert(last, ErrorMatches, "page error")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	items = []int{}
+	for item, err := range paginate(ctx, bs, 3, fakePages(10, -1)) {
+		if err != nil {
+			last = err
+			continue
+		}
+		items = append(items, item)
+		cancel()
+	}
+	c.Assert(items, HasLen, 3)
+	c.Assert(last, Equals, context.Canceled)
+}
+
+func (s *MySuite) TestChannel(c *C) {
+	bs := &BetaSeries{}
+	items, errs, stop := Channel(context.Background(), paginate(context.Background(), bs, 4, fakePages(10, 8)))
+	defer stop()
+	count := 0
+	for range items {
+		count++
+	}
+	c.Assert(count, Equals, 8)
+	c.Assert(<-errs, ErrorMatches, "page error")
+
+	// stopping does not request the next page
+	fetched := 0
+	pages := fakePages(10, -1)
+	items, errs, stop = Channel(context.Background(), paginate(context.Background(), bs, 4,
+		func(bs *BetaSeries, start, size int) ([]int, error) {
+			fetched++
+			return pages(bs, start, size)
+		}))
+	for i := 0; i < 3; i++ {
+		<-items
+	}
+	stop()
+	for range items {
+	}
+	c.Assert(<-errs, Equals, context.Canceled)
+	c.Assert(fetched, Equals, 1)
+}
+
+func (s *MySuite) TestShowsListAllConcurrent(c *C) {
+	bs, api := fakeAPI(map[string]string{
+		"/shows/list": `{"shows": [{"id": 1, "title": "Dexter"}]}`,
+	})
+	defer api.Close()
+	shows := bs.ShowsListAll(context.Background(), &ShowsListOptions{Order: "alphabetical"}, 2)
+	counts := make([]int, 4)
+	wg := sync.WaitGroup{}
+	for i := range counts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, err := range shows {
+				c.Check(err, IsNil)
+				counts[i]++
+			}
+		}()
+	}
+	wg.Wait()
+	c.Assert(counts, DeepEquals, []int{1, 1, 1, 1})
+	c.Assert(api.Requests(), HasLen, 4)
+	for _, request := range api.Requests() {
+		c.Assert(request, Equals, "GET /shows/list?limit=2&order=alphabetical&start=0")
+	}
+}
@@ -12,25 +12,46 @@ var (
 	errIDMustBeStrictlyPositive = errors.New("id must be strictly positive")
 )
 
-// PicturesShows returns a picture of the tv show identified by 'id'.
-// If 'id' is negative, a default betaseries picture will be returned with an error code.
-// The optional 'width' and 'height' parameters must be both strictly
-// positive in order to be used.
-func (bs *BetaSeries) PicturesShows(id, width, height int) (string, error) {
+// PicturesOptions holds the optional parameters of PicturesShows.
+type PicturesOptions struct {
+	RequestOptions
+	// Width and Height must be set together.
+	Width  *int
+	Height *int
+}
+
+func (o *PicturesOptions) validate() error {
+	if (o.Width == nil) != (o.Height == nil) {
+		return invalidOption("'width' and 'height' must be set together")
+	}
+	return firstError(
+		checkPositive("width", o.Width),
+		checkPositive("height", o.Height),
+	)
+}
+
+// PicturesShows returns a picture of the tv show.
+func (bs *BetaSeries) PicturesShows(show ShowRef, opts *PicturesOptions) (string, error) {
+	if opts == nil {
+		opts = &PicturesOptions{}
+	}
+	if err := opts.validate(); err != nil {
+		return "", err
+	}
 	usedAPI := "/pictures/shows"
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
 		return "", errURLParsing
 	}
 	q := u.Query()
-	if id <= 0 {
-		return "", errIDMustBeStrictlyPositive
+	if err := bs.setShowRef(q, show, showIDParams); err != nil {
+		return "", err
 	}
-	q.Set("id", strconv.Itoa(id))
-	if width > 0 && height > 0 {
-		q.Set("width", strconv.Itoa(width))
-		q.Set("height", strconv.Itoa(height))
+	if opts.Width != nil {
+		q.Set("width", strconv.Itoa(*opts.Width))
+		q.Set("height", strconv.Itoa(*opts.Height))
 	}
+	opts.applyExtra(q)
 	u.RawQuery = q.Encode()
 	resp, err := bs.do("GET", u)
 	if err != nil {
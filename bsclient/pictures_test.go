@@ -10,17 +10,17 @@ func (s *MySuite) TestPicturesShows(c *C) {
 	key := os.Getenv("BS_API_KEY")
 	bs, err := NewBetaseriesClient(key, "Dev050", "developer")
 	c.Assert(err, IsNil)
-	picture, err := bs.PicturesShows(1, -1, -1)
+	picture, err := bs.PicturesShows(ShowID(1), nil)
 	c.Assert(err, IsNil)
 	// can't equals to a specific value since
 	// a different image can be retrieve somethimes
 	c.Assert(len(picture) > 0, Equals, true)
 
-	picture, err = bs.PicturesShows(1, 100, 100)
+	picture, err = bs.PicturesShows(ShowID(1), &PicturesOptions{Width: Int(100), Height: Int(100)})
 	c.Assert(err, IsNil)
 	c.Assert(len(picture) > 0, Equals, true)
 
-	picture, err = bs.PicturesShows(0, 100, 100)
+	picture, err = bs.PicturesShows(ShowID(-1), &PicturesOptions{Width: Int(100), Height: Int(100)})
 	c.Assert(err, NotNil)
 	c.Assert(err, Equals, errIDMustBeStrictlyPositive)
 }
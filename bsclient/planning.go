@@ -5,22 +5,57 @@ import (
 	"strconv"
 )
 
+// PlanningGeneralOptions holds the optional parameters of PlanningGeneral.
+type PlanningGeneralOptions struct {
+	RequestOptions
+	// Date is the center of the timeline, in YYYY-MM-DD format or 'now' (default).
+	Date string
+	// Type of the episodes, 'premiere' or 'all'.
+	Type string
+	// Before and After are the number of days of the timeline around the date.
+	Before *int
+	After  *int
+}
+
+func (o *PlanningGeneralOptions) validate() error {
+	if o.Date != "" && o.Date != "now" && !reOptionDate.MatchString(o.Date) {
+		return invalidOption("'date' must be YYYY-MM-DD or 'now'")
+	}
+	return firstError(
+		checkEnum("type", o.Type, "premiere", "all"),
+		checkNotNegative("before", o.Before),
+		checkNotNegative("after", o.After),
+	)
+}
+
 // PlanningGeneral returns a slice of episodes found in [date-before, date+after] timeline.
-// Note: the 'date' input must be in YYYY-MM-JJ format or 'now'
-// 'eType', the episode type, can be 'premiere' or 'all', or empty.
-func (bs *BetaSeries) PlanningGeneral(date, eType string, before, after int) ([]Episode, error) {
+func (bs *BetaSeries) PlanningGeneral(opts *PlanningGeneralOptions) ([]Episode, error) {
+	if opts == nil {
+		opts = &PlanningGeneralOptions{}
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
 	usedAPI := "/planning/general"
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
 		return nil, errURLParsing
 	}
 	q := u.Query()
-	q.Set("date", date)
-	q.Set("before", strconv.Itoa(before))
-	q.Set("after", strconv.Itoa(after))
-	if eType != "" {
-		q.Set("type", eType)
+	q.Set("date", "now")
+	if opts.Date != "" {
+		q.Set("date", opts.Date)
+	}
+	if opts.Before != nil {
+		q.Set("before", strconv.Itoa(*opts.Before))
+	}
+	if opts.After != nil {
+		q.Set("after", strconv.Itoa(*opts.After))
 	}
+	if opts.Type != "" {
+		q.Set("type", opts.Type)
+	}
+	opts.applyExtra(q)
 	u.RawQuery = q.Encode()
 	return bs.doGetEpisodes(u, usedAPI)
 }
@@ -36,28 +71,48 @@ func (bs *BetaSeries) PlanningIncoming() ([]Episode, error) {
 	return bs.doGetEpisodes(u, usedAPI)
 }
 
-// PlanningMember returns a slice of episodes of the member 'id'.
-// If 'id' is 0 or negative, the returned episodes are the ones of the
-// identified member doing the request.
-// The parameter 'unseen' filters not seen episodes.
-// The parameter 'month' filters episodes of the given month with the format YYYY-MM.
-// Note: the 'month' value can be the string "now".
-func (bs *BetaSeries) PlanningMember(id int, unseen bool, month string) ([]Episode, error) {
+// PlanningMemberOptions holds the optional parameters of PlanningMember.
+type PlanningMemberOptions struct {
+	RequestOptions
+	// ID returns the planning of the given member instead of the identified one.
+	ID *int
+	// Unseen filters not seen episodes.
+	Unseen bool
+	// Month filters episodes of the given month, in YYYY-MM format or 'now'.
+	Month string
+}
+
+func (o *PlanningMemberOptions) validate() error {
+	if o.Month != "" && o.Month != "now" && !reOptionMonth.MatchString(o.Month) {
+		return invalidOption("'month' must be YYYY-MM or 'now'")
+	}
+	return checkPositive("id", o.ID)
+}
+
+// PlanningMember returns a slice of episodes of a member.
+func (bs *BetaSeries) PlanningMember(opts *PlanningMemberOptions) ([]Episode, error) {
+	if opts == nil {
+		opts = &PlanningMemberOptions{}
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
 	usedAPI := "/planning/member"
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
 		return nil, errURLParsing
 	}
 	q := u.Query()
-	if id > 0 {
-		q.Set("id", strconv.Itoa(id))
+	if opts.ID != nil {
+		q.Set("id", strconv.Itoa(*opts.ID))
 	}
-	if unseen {
+	if opts.Unseen {
 		q.Set("unseen", "true")
 	}
-	if month != "" {
-		q.Set("month", month)
+	if opts.Month != "" {
+		q.Set("month", opts.Month)
 	}
+	opts.applyExtra(q)
 	u.RawQuery = q.Encode()
 	return bs.doGetEpisodes(u, usedAPI)
 }
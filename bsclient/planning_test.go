@@ -1,6 +1,7 @@
 package bsclient
 
 import (
+	"errors"
 	"os"
 	"strings"
 
@@ -16,7 +17,7 @@ var (
 
 func checkEpisode(c *C, err error, episode *Episode) {
 	c.Assert(err, IsNil)
-	c.Assert(len(episode.Date), Equals, 10)
+	c.Assert(episode.Date.IsZero(), Equals, false)
 	c.Assert(strings.Contains(episode.Code, "S"), Equals, true)
 	c.Assert(strings.Contains(episode.Code, "E"), Equals, true)
 }
@@ -25,14 +26,14 @@ func (s *MySuite) TestPlanningGeneral(c *C) {
 	key := os.Getenv("BS_API_KEY")
 	bs, err := NewBetaseriesClient(key, "", "")
 	c.Assert(err, IsNil)
-	episodes, err := bs.PlanningGeneral("now", 1, 1)
+	episodes, err := bs.PlanningGeneral(&PlanningGeneralOptions{Before: Int(1), After: Int(1)})
 	if len(episodes) > 0 {
 		checkEpisode(c, err, &episodes[0])
 	} else {
 		c.Assert(err, NotNil)
 	}
 
-	episodes, err = bs.PlanningGeneral("1000-01-01", 1, 1)
+	episodes, err = bs.PlanningGeneral(&PlanningGeneralOptions{Date: "1000-01-01", Before: Int(1), After: Int(1)})
 	c.Assert(err, NotNil)
 	c.Assert(err, Equals, errNoEpisodesFound)
 }
@@ -54,7 +55,7 @@ func (s *MySuite) TestPlanningMember(c *C) {
 	key := os.Getenv("BS_API_KEY")
 	bs, err := NewBetaseriesClient(key, "", "")
 	c.Assert(err, IsNil)
-	episodes, err := bs.PlanningMember(0, false, "")
+	episodes, err := bs.PlanningMember(nil)
 	if len(episodes) > 0 {
 		checkEpisode(c, err, &episodes[0])
 	} else {
@@ -64,34 +65,26 @@ func (s *MySuite) TestPlanningMember(c *C) {
 		})
 	}
 
-	episodes, err = bs.PlanningMember(-1, false, "")
-	if len(episodes) > 0 {
-		checkEpisode(c, err, &episodes[0])
-	} else {
-		c.Assert(err, NotNil)
-		c.Assert(err, DeepEquals, &errAPI{
-			[]errorsAPI{err0},
-		})
-	}
+	episodes, err = bs.PlanningMember(&PlanningMemberOptions{ID: Int(-1)})
+	c.Assert(err, NotNil)
+	c.Assert(errors.Is(err, errInvalidOption), Equals, true)
 
-	episodes, err = bs.PlanningMember(-1, false, "1000-01")
+	episodes, err = bs.PlanningMember(&PlanningMemberOptions{Month: "1000-01"})
 	c.Assert(err, NotNil)
 	c.Assert(err, DeepEquals, &errAPI{
 		[]errorsAPI{err0},
 	})
 
-	episodes, err = bs.PlanningMember(-1, false, "Wrong format")
+	episodes, err = bs.PlanningMember(&PlanningMemberOptions{Month: "Wrong format"})
 	c.Assert(err, NotNil)
-	c.Assert(err, DeepEquals, &errAPI{
-		[]errorsAPI{err0},
-	})
+	c.Assert(errors.Is(err, errInvalidOption), Equals, true)
 }
 
 func (s *MySuite) TestPlanningMemberWithCredentials(c *C) {
 	key := os.Getenv("BS_API_KEY")
 	bs, err := NewBetaseriesClient(key, "Dev050", "developer")
 	c.Assert(err, IsNil)
-	episodes, err := bs.PlanningMember(0, false, "")
+	episodes, err := bs.PlanningMember(nil)
 	if len(episodes) > 0 {
 		checkEpisode(c, err, &episodes[0])
 	} else {
@@ -99,23 +92,19 @@ func (s *MySuite) TestPlanningMemberWithCredentials(c *C) {
 		c.Assert(err, Equals, errNoEpisodesFound)
 	}
 
-	episodes, err = bs.PlanningMember(-1, false, "")
-	if len(episodes) > 0 {
-		checkEpisode(c, err, &episodes[0])
-	} else {
-		c.Assert(err, NotNil)
-		c.Assert(err, Equals, errNoEpisodesFound)
-	}
+	episodes, err = bs.PlanningMember(&PlanningMemberOptions{ID: Int(-1)})
+	c.Assert(err, NotNil)
+	c.Assert(errors.Is(err, errInvalidOption), Equals, true)
 
-	episodes, err = bs.PlanningMember(-1, false, "1000-01")
+	episodes, err = bs.PlanningMember(&PlanningMemberOptions{Month: "1000-01"})
 	c.Assert(err, NotNil)
 	c.Assert(err, Equals, errNoEpisodesFound)
 
-	episodes, err = bs.PlanningMember(-1, false, "Wrong format")
+	episodes, err = bs.PlanningMember(&PlanningMemberOptions{Month: "Wrong format"})
 	c.Assert(err, NotNil)
-	c.Assert(err.Error(), Equals, "DateTime::__construct(): Failed to parse time string (Wrong format) at position 0 (W): The timezone could not be found in the database\n")
+	c.Assert(errors.Is(err, errInvalidOption), Equals, true)
 
-	episodes, err = bs.PlanningMember(-1, false, "now")
+	episodes, err = bs.PlanningMember(&PlanningMemberOptions{Month: "now"})
 	c.Assert(err, NotNil)
 	c.Assert(err, Equals, errNoEpisodesFound)
 }
@@ -0,0 +1,243 @@
+package bsclient
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ShowRef references a show by exactly one of its identifiers.
+// Endpoints which do not accept the given identifier resolve it
+// to a betaseries id first, at the cost of an extra request.
+type ShowRef struct {
+	ID    int    // betaseries id
+	TVDB  int    // thetvdb.com id
+	IMDB  string // imdb.com id, like 'tt0903747'
+	TMDB  int    // themoviedb.org id
+	Title string // exact title, looked up with ShowsSearch
+}
+
+// ShowID references a show by its betaseries id.
+func ShowID(id int) ShowRef {
+	return ShowRef{ID: id}
+}
+
+// ShowTVDB references a show by its thetvdb.com id.
+func ShowTVDB(id int) ShowRef {
+	return ShowRef{TVDB: id}
+}
+
+// ShowIMDB references a show by its imdb.com id.
+func ShowIMDB(id string) ShowRef {
+	return ShowRef{IMDB: id}
+}
+
+// ShowTMDB references a show by its themoviedb.org id.
+func ShowTMDB(id int) ShowRef {
+	return ShowRef{TMDB: id}
+}
+
+// ShowTitle references a show by its title.
+func ShowTitle(title string) ShowRef {
+	return ShowRef{Title: title}
+}
+
+// Ref returns a reference to the show.
+func (s *Show) Ref() ShowRef {
+	return ShowRef{ID: s.ID}
+}
+
+// IsZero returns true if no identifier is set.
+func (r ShowRef) IsZero() bool {
+	return r == ShowRef{}
+}
+
+// EpisodeRef references an episode by its betaseries id, its thetvdb.com id,
+// or by its show and code (S01E01).
+type EpisodeRef struct {
+	ID   int
+	TVDB int
+	Show ShowRef
+	Code string
+}
+
+// EpisodeID references an episode by its betaseries id.
+func EpisodeID(id int) EpisodeRef {
+	return EpisodeRef{ID: id}
+}
+
+// EpisodeTVDB references an episode by its thetvdb.com id.
+func EpisodeTVDB(id int) EpisodeRef {
+	return EpisodeRef{TVDB: id}
+}
+
+// EpisodeCode references an episode by its show and code (S01E01).
+func EpisodeCode(show ShowRef, code string) EpisodeRef {
+	return EpisodeRef{Show: show, Code: code}
+}
+
+// Ref returns a reference to the episode.
+func (e *Episode) Ref() EpisodeRef {
+	return EpisodeRef{ID: e.ID}
+}
+
+// Reference kinds, named after the usual API query parameters.
+const (
+	refID    = "id"
+	refTVDB  = "thetvdb_id"
+	refIMDB  = "imdb_id"
+	refTMDB  = "tmdb_id"
+	refTitle = "title"
+	refCode  = "code"
+)
+
+// refParams maps the reference kinds accepted by an endpoint to its query
+// parameters. Every endpoint accepts at least a betaseries id.
+type refParams map[string]string
+
+var (
+	showParams         = refParams{refID: "id", refTVDB: "thetvdb_id", refIMDB: "imdb_id"}
+	showDisplayParams  = refParams{refID: "id", refTVDB: "thetvdb_id", refIMDB: "imdb_id", refTMDB: "tmdb_id"}
+	showTVDBParams     = refParams{refID: "id", refTVDB: "thetvdb_id"}
+	showIDParams       = refParams{refID: "id"}
+	episodesListParams = refParams{refID: "showId", refTVDB: "showTheTVDBId", refIMDB: "showIMDBId"}
+	episodeParams      = refParams{refID: "id", refTVDB: "thetvdb_id"}
+	episodeIDParams    = refParams{refID: "id"}
+)
+
+// param returns the kind and the value of the reference.
+func (r ShowRef) param() (string, string, error) {
+	if r.ID < 0 || r.TVDB < 0 || r.TMDB < 0 {
+		return "", "", errIDMustBeStrictlyPositive
+	}
+	set := 0
+	kind, value := "", ""
+	if r.ID > 0 {
+		set++
+		kind, value = refID, strconv.Itoa(r.ID)
+	}
+	if r.TVDB > 0 {
+		set++
+		kind, value = refTVDB, strconv.Itoa(r.TVDB)
+	}
+	if r.IMDB != "" {
+		set++
+		kind, value = refIMDB, r.IMDB
+	}
+	if r.TMDB > 0 {
+		set++
+		kind, value = refTMDB, strconv.Itoa(r.TMDB)
+	}
+	if r.Title != "" {
+		set++
+		kind, value = refTitle, r.Title
+	}
+	switch {
+	case set == 0:
+		return "", "", errIDNotProperlySet
+	case set > 1:
+		return "", "", errNoSingleIDUsed
+	}
+	return kind, value, nil
+}
+
+// param returns the kind and the value of the reference.
+func (r EpisodeRef) param() (string, string, error) {
+	if r.ID < 0 || r.TVDB < 0 {
+		return "", "", errIDMustBeStrictlyPositive
+	}
+	set := 0
+	kind, value := "", ""
+	if r.ID > 0 {
+		set++
+		kind, value = refID, strconv.Itoa(r.ID)
+	}
+	if r.TVDB > 0 {
+		set++
+		kind, value = refTVDB, strconv.Itoa(r.TVDB)
+	}
+	if r.Code != "" || !r.Show.IsZero() {
+		if r.Code == "" || r.Show.IsZero() {
+			return "", "", errIDNotProperlySet
+		}
+		set++
+		kind, value = refCode, r.Code
+	}
+	switch {
+	case set == 0:
+		return "", "", errIDNotProperlySet
+	case set > 1:
+		return "", "", errNoSingleIDUsed
+	}
+	return kind, value, nil
+}
+
+// setShowRef sets the query parameter referencing a show, resolving the
+// reference to a betaseries id if the endpoint does not accept it.
+func (bs *BetaSeries) setShowRef(q url.Values, ref ShowRef, params refParams) error {
+	kind, value, err := ref.param()
+	if err != nil {
+		return err
+	}
+	if key, ok := params[kind]; ok {
+		q.Set(key, value)
+		return nil
+	}
+	id, err := bs.resolveShow(ref, kind)
+	if err != nil {
+		return err
+	}
+	q.Set(params[refID], strconv.Itoa(id))
+	return nil
+}
+
+// resolveShow returns the betaseries id of a show reference.
+func (bs *BetaSeries) resolveShow(ref ShowRef, kind string) (int, error) {
+	if kind != refTitle {
+		show, err := bs.ShowDisplay(ref)
+		if err != nil {
+			return 0, err
+		}
+		if show == nil {
+			return 0, errNoShowsFound
+		}
+		return show.ID, nil
+	}
+	shows, err := bs.ShowsSearch(ref.Title, &ShowsSearchOptions{Summary: true})
+	if err != nil {
+		return 0, err
+	}
+	for _, show := range shows {
+		if strings.EqualFold(show.Title, ref.Title) {
+			return show.ID, nil
+		}
+	}
+	return 0, errNoShowsFound
+}
+
+// setEpisodeRef sets the query parameter referencing an episode, resolving
+// the reference to a betaseries id if the endpoint does not accept it.
+func (bs *BetaSeries) setEpisodeRef(q url.Values, ref EpisodeRef, params refParams) error {
+	kind, value, err := ref.param()
+	if err != nil {
+		return err
+	}
+	if key, ok := params[kind]; ok {
+		q.Set(key, value)
+		return nil
+	}
+	var episode *Episode
+	if kind == refCode {
+		episode, err = bs.EpisodeSearch(ref.Show, ref.Code, nil)
+	} else {
+		episode, err = bs.EpisodeDisplay(ref, nil)
+	}
+	if err != nil {
+		return err
+	}
+	if episode == nil {
+		return errNoEpisodesFound
+	}
+	q.Set(params[refID], strconv.Itoa(episode.ID))
+	return nil
+}
@@ -0,0 +1,86 @@
+package bsclient
+
+import (
+	"github.com/dns-gh/bs-client/internal/apitest"
+	. "gopkg.in/check.v1"
+)
+
+// fakeAPI returns a client of a fake API answering with the given bodies.
+func fakeAPI(bodies map[string]string) (*BetaSeries, *apitest.Server) {
+	api := apitest.NewServer(bodies)
+	bs := &BetaSeries{
+		baseURL:    api.URL,
+		version:    bsVersion,
+		session:    &session{},
+		httpClient: api.Client(),
+	}
+	return bs, api
+}
+
+func (s *MySuite) TestRefParam(c *C) {
+	kind, value, err := ShowIMDB("tt0903747").param()
+	c.Assert(err, IsNil)
+	c.Assert(kind, Equals, refIMDB)
+	c.Assert(value, Equals, "tt0903747")
+
+	_, _, err = ShowRef{}.param()
+	c.Assert(err, Equals, errIDNotProperlySet)
+	_, _, err = ShowRef{ID: 1, TVDB: 2}.param()
+	c.Assert(err, Equals, errNoSingleIDUsed)
+	_, _, err = ShowID(-1).param()
+	c.Assert(err, Equals, errIDMustBeStrictlyPositive)
+
+	kind, value, err = EpisodeCode(ShowID(1), "S01E02").param()
+	c.Assert(err, IsNil)
+	c.Assert(kind, Equals, refCode)
+	c.Assert(value, Equals, "S01E02")
+
+	_, _, err = EpisodeRef{Code: "S01E02"}.param()
+	c.Assert(err, Equals, errIDNotProperlySet)
+	_, _, err = EpisodeRef{ID: 1, Show: ShowID(1), Code: "S01E02"}.param()
+	c.Assert(err, Equals, errNoSingleIDUsed)
+}
+
+func (s *MySuite) TestRefQuery(c *C) {
+	bs, api := fakeAPI(map[string]string{
+		"/shows/search":      `{"shows": [{"id": 2, "title": "Breaking Bad 2"}, {"id": 1, "title": "Breaking Bad"}]}`,
+		"/shows/display":     `{"show": {"id": 3, "title": "Lost"}}`,
+		"/shows/videos":      `{"videos": [{"id": 1}]}`,
+		"/shows/episodes":    `{"episodes": [{"id": 10}]}`,
+		"/episodes/search":   `{"episode": {"id": 20}}`,
+		"/subtitles/episode": `{"subtitles": [{"id": 30}]}`,
+	})
+	defer api.Close()
+
+	_, err := bs.ShowsVideos(ShowTVDB(81189))
+	c.Assert(err, IsNil)
+	c.Assert(api.Requests(), DeepEquals, []string{"GET /shows/videos?thetvdb_id=81189"})
+
+	// titles are resolved with a search
+	api.Reset()
+	_, err = bs.ShowsEpisodes(ShowTitle("breaking bad"), nil)
+	c.Assert(err, IsNil)
+	c.Assert(api.Requests(), HasLen, 2)
+	c.Assert(api.Requests()[1], Equals, "GET /shows/episodes?id=1")
+
+	// ids not accepted by the endpoint are resolved with shows/display
+	api.Reset()
+	_, err = bs.ShowsEpisodes(ShowTMDB(4607), nil)
+	c.Assert(err, IsNil)
+	c.Assert(api.Requests(), DeepEquals, []string{
+		"GET /shows/display?tmdb_id=4607",
+		"GET /shows/episodes?id=3",
+	})
+
+	api.Reset()
+	_, err = bs.ShowsEpisodes(ShowTitle("Unknown"), nil)
+	c.Assert(err, Equals, errNoShowsFound)
+
+	api.Reset()
+	_, err = bs.SubtitlesEpisode(EpisodeCode(ShowID(1), "S01E02"), nil)
+	c.Assert(err, IsNil)
+	c.Assert(api.Requests(), DeepEquals, []string{
+		"GET /episodes/search?number=S01E02&show_id=1",
+		"GET /subtitles/episode?id=20",
+	})
+}
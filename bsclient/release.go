@@ -0,0 +1,392 @@
+package bsclient
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	errReleaseNotParsed = errors.New("release name could not be parsed")
+	errNoReleaseTitle   = errors.New("release has no title")
+)
+
+// Release represents the information extracted locally from a video release name
+// such as 'Show.Name.2016.S01E01E02.720p.HDTV.x264-GROUP.mkv'.
+type Release struct {
+	Title string
+	Year  int
+	// Season and Episodes are set for 'S01E01', 'S01E01E02', 'S01E01-E03' and '1x02' names.
+	Season   int
+	Episodes []int
+	// Date is set for date based names like 'Show.2016.01.31.mkv'.
+	Date time.Time
+	// Absolute is set for absolute numbering, mostly used by anime
+	// releases like '[Group] Show - 012 [720p].mkv'.
+	Absolute   int
+	Resolution string
+	Source     string
+	Codec      string
+	Group      string
+}
+
+var (
+	reSeasonEpisode = regexp.MustCompile(`(?i)\bS(\d{1,2})[ .]?E(\d{1,3})((?:-?E\d{1,3}|-\d{1,3})*)\b`)
+	reMultiEpisode  = regexp.MustCompile(`\d{1,3}`)
+	reEpisodeRange  = regexp.MustCompile(`(?i)^E?\d{1,3}$`)
+	reCrossEpisode  = regexp.MustCompile(`(?i)\b(\d{1,2})x(\d{2,3})((?:[x-]\d{2,3})*)\b`)
+	reDate          = regexp.MustCompile(`\b((?:19|20)\d{2})[ .-](\d{2})[ .-](\d{2})\b`)
+	reAbsolute      = regexp.MustCompile(`(?:^| )-? ?(\d{2,4})(?:v\d)?(?: |$)`)
+	reYear          = regexp.MustCompile(`\b((?:19|20)\d{2})\b`)
+	reResolution    = regexp.MustCompile(`(?i)\b(480p|576p|720p|1080[pi]|2160p|4k)\b`)
+	reSource        = regexp.MustCompile(`(?i)\b(hdtv|pdtv|web[ .-]?dl|webrip|web|blu[ .-]?ray|bdrip|brrip|dvdrip|hdrip)\b`)
+	reCodec         = regexp.MustCompile(`(?i)\b([xh][ .]?26[45]|hevc|avc|xvid|divx)\b`)
+	reLeadingGroup  = regexp.MustCompile(`^\[([^\]]+)\]\s*`)
+	reBrackets      = regexp.MustCompile(`\[[^\]]*\]|\([^)]*\)`)
+)
+
+var sourceNames = map[string]string{
+	"hdtv":   "HDTV",
+	"pdtv":   "PDTV",
+	"webdl":  "WEB-DL",
+	"webrip": "WEBRip",
+	"web":    "WEB",
+	"bluray": "BluRay",
+	"bdrip":  "BDRip",
+	"brrip":  "BRRip",
+	"dvdrip": "DVDRip",
+	"hdrip":  "HDRip",
+}
+
+var codecNames = map[string]string{
+	"x264": "x264",
+	"h264": "H264",
+	"x265": "x265",
+	"h265": "H265",
+	"hevc": "HEVC",
+	"avc":  "H264",
+	"xvid": "XviD",
+	"divx": "DivX",
+}
+
+func compactToken(token string) string {
+	return strings.NewReplacer(" ", "", ".", "", "-", "").Replace(strings.ToLower(token))
+}
+
+// stripExtension removes the video or subtitle extension of a release name.
+func stripExtension(name string) string {
+	ext := path.Ext(name)
+	if len(ext) < 2 || len(ext) > 5 || strings.ContainsAny(ext, " -") {
+		return name
+	}
+	if _, err := strconv.Atoi(ext[1:]); err == nil {
+		return name
+	}
+	return strings.TrimSuffix(name, ext)
+}
+
+// trailingGroup extracts the release group of a scene name ending with '-GROUP',
+// ignoring a short language suffix as found in subtitle names ('-GROUP.fr').
+func trailingGroup(name string) (string, string) {
+	tokens := strings.Split(name, ".")
+	last := len(tokens) - 1
+	if last > 0 && len(tokens[last]) <= 3 && !strings.Contains(tokens[last], "-") {
+		last--
+	}
+	token := tokens[last]
+	idx := strings.LastIndex(token, "-")
+	if idx <= 0 || idx == len(token)-1 || strings.Contains(token[idx+1:], " ") {
+		return name, ""
+	}
+	group := token[idx+1:]
+	if _, known := sourceNames[compactToken(token[idx-min(idx, 3):])]; known {
+		// WEB-DL ending the release name
+		return name, ""
+	}
+	if reEpisodeRange.MatchString(group) {
+		// S01E01-E03 ending the release name
+		return name, ""
+	}
+	tokens[last] = token[:idx]
+	return strings.Join(tokens[:last+1], "."), group
+}
+
+func parseEpisodeList(s string) []int {
+	list := []int{}
+	for _, number := range reMultiEpisode.FindAllString(s, -1) {
+		n, _ := strconv.Atoi(number)
+		list = append(list, n)
+	}
+	return list
+}
+
+// expandEpisodes turns 'E01-E03' ranges into the full list of episodes.
+func expandEpisodes(first int, rest string) []int {
+	episodes := []int{first}
+	others := parseEpisodeList(rest)
+	if len(others) == 1 && strings.HasPrefix(rest, "-") && others[0] > first {
+		for i := first + 1; i <= others[0]; i++ {
+			episodes = append(episodes, i)
+		}
+		return episodes
+	}
+	return append(episodes, others...)
+}
+
+func cleanTitle(title string) string {
+	title = strings.NewReplacer(".", " ", "_", " ").Replace(title)
+	title = strings.Trim(title, " -")
+	return strings.Join(strings.Fields(title), " ")
+}
+
+// parseReleaseTags extracts the release group, resolution, source and codec
+// of a release name and returns the name without its extension and group.
+func parseReleaseTags(name string) (*Release, string) {
+	r := &Release{}
+	name = stripExtension(path.Base(strings.Replace(name, "\\", "/", -1)))
+	if m := reLeadingGroup.FindStringSubmatch(name); m != nil {
+		r.Group = m[1]
+		name = name[len(m[0]):]
+	} else {
+		name, r.Group = trailingGroup(name)
+	}
+
+	if m := reResolution.FindString(name); m != "" {
+		r.Resolution = strings.ToLower(m)
+		if r.Resolution == "4k" {
+			r.Resolution = "2160p"
+		}
+	}
+	if m := reSource.FindString(name); m != "" {
+		r.Source = sourceNames[compactToken(m)]
+	}
+	if m := reCodec.FindString(name); m != "" {
+		r.Codec = codecNames[compactToken(m)]
+	}
+	return r, name
+}
+
+// tags returns the lowercased resolution, source and codec of the release.
+func (r *Release) tags() []string {
+	tags := []string{}
+	for _, tag := range []string{r.Resolution, r.Source, r.Codec} {
+		if tag != "" {
+			tags = append(tags, strings.ToLower(tag))
+		}
+	}
+	return tags
+}
+
+// ParseRelease extracts locally, without any API call, the show title, season,
+// episodes, date or absolute number, year, resolution, source, codec and release
+// group of a video release name.
+// An error is returned if no episode information can be found.
+func ParseRelease(name string) (*Release, error) {
+	r, name := parseReleaseTags(name)
+
+	// the title is everything before the first episode marker
+	markerIdx := -1
+	if m := reSeasonEpisode.FindStringSubmatchIndex(name); m != nil {
+		markerIdx = m[0]
+		r.Season, _ = strconv.Atoi(name[m[2]:m[3]])
+		first, _ := strconv.Atoi(name[m[4]:m[5]])
+		r.Episodes = expandEpisodes(first, name[m[6]:m[7]])
+	} else if m := reCrossEpisode.FindStringSubmatchIndex(name); m != nil {
+		markerIdx = m[0]
+		r.Season, _ = strconv.Atoi(name[m[2]:m[3]])
+		first, _ := strconv.Atoi(name[m[4]:m[5]])
+		r.Episodes = expandEpisodes(first, name[m[6]:m[7]])
+	} else if m := reDate.FindStringSubmatchIndex(name); m != nil {
+		date, err := time.Parse("2006-01-02",
+			fmt.Sprintf("%s-%s-%s", name[m[2]:m[3]], name[m[4]:m[5]], name[m[6]:m[7]]))
+		if err == nil {
+			markerIdx = m[0]
+			r.Date = date
+		}
+	}
+	if markerIdx < 0 {
+		// anime releases: '[Group] Show Title - 012 [720p]'
+		clean := strings.TrimSpace(reBrackets.ReplaceAllString(cleanTitle(name), " "))
+		if m := reAbsolute.FindAllStringSubmatchIndex(clean, -1); m != nil {
+			last := m[len(m)-1]
+			if number, _ := strconv.Atoi(clean[last[2]:last[3]]); last[0] > 0 && !reYear.MatchString(clean[last[2]:last[3]]) {
+				r.Absolute = number
+				r.Title = cleanTitle(clean[:last[0]])
+				return r, nil
+			}
+		}
+		return nil, errReleaseNotParsed
+	}
+
+	title := reBrackets.ReplaceAllString(name[:markerIdx], " ")
+	if m := reYear.FindAllStringSubmatchIndex(title, -1); m != nil {
+		last := m[len(m)-1]
+		// a year alone is a title, not a year: '1983.S01E01'
+		if last[0] > 0 {
+			r.Year, _ = strconv.Atoi(title[last[2]:last[3]])
+			title = title[:last[0]]
+		}
+	}
+	r.Title = cleanTitle(title)
+	return r, nil
+}
+
+// Code returns the BetaSeries episode code (S01E02) of the first episode of the release,
+// or an empty string if the release is not numbered by season.
+func (r *Release) Code() string {
+	if r.Season == 0 && len(r.Episodes) == 0 {
+		return ""
+	}
+	episode := 0
+	if len(r.Episodes) > 0 {
+		episode = r.Episodes[0]
+	}
+	return fmt.Sprintf("S%02dE%02d", r.Season, episode)
+}
+
+// IsNotParsed returns true if the error means that a release name
+// could not be parsed into a show and its episodes.
+func IsNotParsed(err error) bool {
+	return err == errReleaseNotParsed || err == errNoReleaseTitle
+}
+
+// ReleaseResolver resolves parsed releases to BetaSeries shows and episodes.
+// Show titles, including the ones matching no show, and show episodes are
+// cached so that the API is only called once per show, whatever the number
+// of resolved releases.
+// It is safe for concurrent use.
+type ReleaseResolver struct {
+	bs       *BetaSeries
+	mutex    sync.Mutex
+	shows    map[string]int
+	episodes map[int][]Episode
+}
+
+// NewReleaseResolver creates a release resolver using the given client.
+func NewReleaseResolver(bs *BetaSeries) *ReleaseResolver {
+	return &ReleaseResolver{
+		bs:       bs,
+		shows:    map[string]int{},
+		episodes: map[int][]Episode{},
+	}
+}
+
+// NormalizeTitle returns a show title reduced to its lowercase letters and
+// digits, so that 'Marvel's Agents of S.H.I.E.L.D.' and
+// 'marvels.agents.of.shield' compare equal.
+func NormalizeTitle(title string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > 127 {
+			return r
+		}
+		return -1
+	}, strings.ToLower(title))
+}
+
+// showKey returns the cache key of a release title and year,
+// so that 'Doctor Who 2005' and 'Doctor Who' are resolved apart.
+func showKey(title string, year int) string {
+	return NormalizeTitle(title) + "|" + strconv.Itoa(year)
+}
+
+// SetShow registers the show 'id' for the given title and year, 0 for
+// releases without year, avoiding any API call when resolving such releases.
+func (r *ReleaseResolver) SetShow(title string, year int, id int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.shows[showKey(title, year)] = id
+}
+
+// ShowID returns the BetaSeries show id matching the title, and the year if
+// any, of the release. No show is returned if none of the search results matches.
+func (r *ReleaseResolver) ShowID(release *Release) (int, error) {
+	if release.Title == "" {
+		return 0, errNoReleaseTitle
+	}
+	r.mutex.Lock()
+	id, ok := r.shows[showKey(release.Title, release.Year)]
+	r.mutex.Unlock()
+	if ok {
+		if id == 0 {
+			return 0, errNoShowsFound
+		}
+		return id, nil
+	}
+
+	shows, err := r.bs.ShowsSearch(release.Title, nil)
+	if err != nil {
+		return 0, err
+	}
+	title := NormalizeTitle(release.Title)
+	for _, show := range shows {
+		if NormalizeTitle(show.Title) == title &&
+			(release.Year == 0 || show.Creation == release.Year) {
+			r.SetShow(release.Title, release.Year, show.ID)
+			return show.ID, nil
+		}
+	}
+	// remember unknown titles so that their other releases do not search again
+	r.SetShow(release.Title, release.Year, 0)
+	return 0, errNoShowsFound
+}
+
+func (r *ReleaseResolver) showEpisodes(id int) ([]Episode, error) {
+	r.mutex.Lock()
+	episodes, ok := r.episodes[id]
+	r.mutex.Unlock()
+	if ok {
+		return episodes, nil
+	}
+	episodes, err := r.bs.ShowsEpisodes(ShowID(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	r.mutex.Lock()
+	r.episodes[id] = episodes
+	r.mutex.Unlock()
+	return episodes, nil
+}
+
+// Episodes returns the BetaSeries episodes matching the release, one for each
+// episode of a multi-episode release.
+func (r *ReleaseResolver) Episodes(release *Release) ([]Episode, error) {
+	id, err := r.ShowID(release)
+	if err != nil {
+		return nil, err
+	}
+	episodes, err := r.showEpisodes(id)
+	if err != nil {
+		return nil, err
+	}
+	found := []Episode{}
+	for _, episode := range episodes {
+		switch {
+		case len(release.Episodes) > 0:
+			if episode.Season != release.Season {
+				continue
+			}
+			for _, number := range release.Episodes {
+				if episode.Episode == number {
+					found = append(found, episode)
+				}
+			}
+		case !release.Date.IsZero():
+			if episode.Date.Format("2006-01-02") == release.Date.Format("2006-01-02") {
+				found = append(found, episode)
+			}
+		case release.Absolute > 0:
+			if episode.Global == release.Absolute {
+				found = append(found, episode)
+			}
+		}
+	}
+	if len(found) < 1 {
+		return nil, errNoEpisodesFound
+	}
+	return found, nil
+}
@@ -0,0 +1,109 @@
+package bsclient
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestParseRelease(c *C) {
+	r, err := ParseRelease("/videos/Breaking.Bad.S05E14.720p.HDTV.x264-IMMERSE.mkv")
+	c.Assert(err, IsNil)
+	c.Assert(r, DeepEquals, &Release{
+		Title:      "Breaking Bad",
+		Season:     5,
+		Episodes:   []int{14},
+		Resolution: "720p",
+		Source:     "HDTV",
+		Codec:      "x264",
+		Group:      "IMMERSE",
+	})
+	c.Assert(r.Code(), Equals, "S05E14")
+
+	r, err = ParseRelease("Doctor.Who.2005.S08E01E02.1080p.WEB-DL.DD5.1.H264-NTb.mkv")
+	c.Assert(err, IsNil)
+	c.Assert(r.Title, Equals, "Doctor Who")
+	c.Assert(r.Year, Equals, 2005)
+	c.Assert(r.Season, Equals, 8)
+	c.Assert(r.Episodes, DeepEquals, []int{1, 2})
+	c.Assert(r.Source, Equals, "WEB-DL")
+	c.Assert(r.Codec, Equals, "H264")
+	c.Assert(r.Group, Equals, "NTb")
+
+	r, err = ParseRelease("Lost.S01E01-E03.avi")
+	c.Assert(err, IsNil)
+	c.Assert(r.Episodes, DeepEquals, []int{1, 2, 3})
+	c.Assert(r.Group, Equals, "")
+
+	r, err = ParseRelease("the office 3x07 - branch wars.mp4")
+	c.Assert(err, IsNil)
+	c.Assert(r.Title, Equals, "the office")
+	c.Assert(r.Season, Equals, 3)
+	c.Assert(r.Episodes, DeepEquals, []int{7})
+
+	r, err = ParseRelease("The.Daily.Show.2016.01.31.HDTV.x264-SORNY.mp4")
+	c.Assert(err, IsNil)
+	c.Assert(r.Title, Equals, "The Daily Show")
+	c.Assert(r.Date, Equals, time.Date(2016, 1, 31, 0, 0, 0, 0, time.UTC))
+	c.Assert(r.Code(), Equals, "")
+
+	r, err = ParseRelease("[HorribleSubs] One Piece - 712 [720p].mkv")
+	c.Assert(err, IsNil)
+	c.Assert(r.Title, Equals, "One Piece")
+	c.Assert(r.Absolute, Equals, 712)
+	c.Assert(r.Resolution, Equals, "720p")
+	c.Assert(r.Group, Equals, "HorribleSubs")
+
+	r, err = ParseRelease("Show.S01E01.720p.HDTV.x264-KILLERS.fr.srt")
+	c.Assert(err, IsNil)
+	c.Assert(r.Group, Equals, "KILLERS")
+
+	_, err = ParseRelease("holidays.2016.mkv")
+	c.Assert(err, Equals, errReleaseNotParsed)
+}
+
+func (s *MySuite) TestReleaseResolverShowID(c *C) {
+	bs, api := fakeAPI(map[string]string{
+		"/shows/search": `{"shows": [
+			{"id": 1, "title": "Doctor Who", "creation": "1963"},
+			{"id": 2, "title": "Doctor Who", "creation": "2005"},
+			{"id": 3, "title": "Doctor Who Confidential", "creation": "2005"}]}`,
+	})
+	defer api.Close()
+	resolver := NewReleaseResolver(bs)
+
+	id, err := resolver.ShowID(&Release{Title: "Doctor.Who", Year: 2005})
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, 2)
+	id, err = resolver.ShowID(&Release{Title: "Doctor Who", Year: 2005})
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, 2)
+	c.Assert(api.Requests(), HasLen, 1)
+
+	// unrelated search results are not picked
+	resolver = NewReleaseResolver(bs)
+	_, err = resolver.ShowID(&Release{Title: "Doctor Who", Year: 1996})
+	c.Assert(err, Equals, errNoShowsFound)
+	_, err = resolver.ShowID(&Release{Title: "Doctor"})
+	c.Assert(err, Equals, errNoShowsFound)
+	c.Assert(IsNotFound(err), Equals, true)
+	c.Assert(api.Requests(), HasLen, 3)
+
+	// titles are cached along with their year, and so are the missing shows
+	_, err = resolver.ShowID(&Release{Title: "doctor", Season: 2})
+	c.Assert(err, Equals, errNoShowsFound)
+	c.Assert(api.Requests(), HasLen, 3)
+	id, err = resolver.ShowID(&Release{Title: "Doctor Who"})
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, 1)
+	id, err = resolver.ShowID(&Release{Title: "Doctor Who", Year: 2005})
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, 2)
+	c.Assert(api.Requests(), HasLen, 5)
+
+	resolver.SetShow("Doctor Who", 2005, 3)
+	id, err = resolver.ShowID(&Release{Title: "Doctor.Who", Year: 2005})
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, 3)
+	c.Assert(api.Requests(), HasLen, 5)
+}
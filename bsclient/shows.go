@@ -5,6 +5,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 var (
@@ -30,16 +31,16 @@ type Show struct {
 	Title     string `json:"title"`
 	// specific to shows/... API endpoints
 	Description    string          `json:"description"`
-	Seasons        string          `json:"seasons"`
+	Seasons        int             `json:"seasons"`
 	SeasonsDetails []seasonDetails `json:"seasons_details"`
-	Episodes       string          `json:"episodes"`
-	Followers      string          `json:"followers"`
-	Comments       string          `json:"comments"`
-	Similars       string          `json:"similars"`
-	Characters     string          `json:"characters"`
-	Creation       string          `json:"creation"`
+	Episodes       int             `json:"episodes"`
+	Followers      int             `json:"followers"`
+	Comments       int             `json:"comments"`
+	Similars       int             `json:"similars"`
+	Characters     int             `json:"characters"`
+	Creation       int             `json:"creation"` // year of the first broadcast
 	Genres         []string        `json:"genres"`
-	Length         string          `json:"length"`
+	Length         time.Duration   `json:"length"` // episode runtime
 	Network        string          `json:"network"`
 	Rating         string          `json:"rating"`
 	Status         string          `json:"status"`
@@ -139,9 +140,32 @@ func (bs *BetaSeries) doGetSimilars(u *url.URL) ([]Similar, error) {
 	return data.Similars, nil
 }
 
+// ShowsSearchOptions holds the optional parameters of ShowsSearch.
+type ShowsSearchOptions struct {
+	RequestOptions
+	// Order sorts the results by 'title', 'popularity' (default) or 'followers'.
+	Order string
+	// Summary only returns summarized info.
+	Summary bool
+}
+
+func (o *ShowsSearchOptions) validate() error {
+	return checkEnum("order", o.Order, "title", "popularity", "followers")
+}
+
 // ShowsSearch returns a slice of shows found with the given query
 // The slice is of size 100 maximum and the results are ordered by popularity by default.
-func (bs *BetaSeries) ShowsSearch(query, order string, summary bool) ([]Show, error) {
+func (bs *BetaSeries) ShowsSearch(query string, opts *ShowsSearchOptions) ([]Show, error) {
+	return bs.showsSearch(query, opts, 1, 100)
+}
+
+func (bs *BetaSeries) showsSearch(query string, opts *ShowsSearchOptions, page, perPage int) ([]Show, error) {
+	if opts == nil {
+		opts = &ShowsSearchOptions{}
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
 	usedAPI := "/shows/search"
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
@@ -149,86 +173,131 @@ func (bs *BetaSeries) ShowsSearch(query, order string, summary bool) ([]Show, er
 	}
 	q := u.Query()
 	q.Set("title", strings.ToLower(query))
-	q.Set("nbpp", "100")
-	switch order {
-	case "title", "popularity", "followers":
-		q.Set("order", order)
-	default:
-		q.Set("order", "popularity")
-	}
-	if summary {
+	q.Set("nbpp", strconv.Itoa(perPage))
+	if page > 1 {
+		q.Set("page", strconv.Itoa(page))
+	}
+	q.Set("order", "popularity")
+	if opts.Order != "" {
+		q.Set("order", opts.Order)
+	}
+	if opts.Summary {
 		q.Set("summary", "true")
 	}
+	opts.applyExtra(q)
 	u.RawQuery = q.Encode()
 
 	return bs.doGetShows(u, usedAPI)
 }
 
-// ShowsRandom returns a slice of random shows. The maximum size of the slice is given
-// by the 'num' parameter. If you want to get only summarized info, use the 'summary parameter.
-func (bs *BetaSeries) ShowsRandom(num int, summary bool) ([]Show, error) {
+// ShowsRandomOptions holds the optional parameters of ShowsRandom.
+type ShowsRandomOptions struct {
+	RequestOptions
+	// Number is the maximum size of the returned slice.
+	Number *int
+	// Summary only returns summarized info.
+	Summary bool
+}
+
+func (o *ShowsRandomOptions) validate() error {
+	return checkNotNegative("number", o.Number)
+}
+
+// ShowsRandom returns a slice of random shows.
+func (bs *BetaSeries) ShowsRandom(opts *ShowsRandomOptions) ([]Show, error) {
+	if opts == nil {
+		opts = &ShowsRandomOptions{}
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
 	usedAPI := "/shows/random"
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
 		return nil, errURLParsing
 	}
 	q := u.Query()
-	if num >= 0 {
-		q.Set("nb", strconv.Itoa(num))
+	if opts.Number != nil {
+		q.Set("nb", strconv.Itoa(*opts.Number))
 	}
-	if summary {
-		q.Set("summary", strconv.FormatBool(summary))
+	if opts.Summary {
+		q.Set("summary", "true")
 	}
+	opts.applyExtra(q)
 	u.RawQuery = q.Encode()
 
 	return bs.doGetShows(u, usedAPI)
 }
 
+// ShowsFavoritesOptions holds the optional parameters of ShowsFavorites.
+type ShowsFavoritesOptions struct {
+	RequestOptions
+	// ID is the member whose favorites are returned, instead of the authenticated one.
+	ID *int
+}
+
+func (o *ShowsFavoritesOptions) validate() error {
+	return checkPositive("id", o.ID)
+}
+
 // ShowsFavorites returns a slice of favorite shows.
-// A user ID can be provided.
-func (bs *BetaSeries) ShowsFavorites(userID int) ([]Show, error) {
+func (bs *BetaSeries) ShowsFavorites(opts *ShowsFavoritesOptions) ([]Show, error) {
+	if opts == nil {
+		opts = &ShowsFavoritesOptions{}
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
 	usedAPI := "/shows/favorites"
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
 		return nil, errURLParsing
 	}
 	q := u.Query()
-	if userID > 0 {
-		q.Set("id", strconv.Itoa(userID))
+	if opts.ID != nil {
+		q.Set("id", strconv.Itoa(*opts.ID))
 	}
+	opts.applyExtra(q)
 	u.RawQuery = q.Encode()
 
 	return bs.doGetShows(u, usedAPI)
 }
 
-// ShowFavorite sets the show 'id' as favorite.
-func (bs *BetaSeries) ShowFavorite(id int) (*Show, error) {
-	return bs.showUpdate("POST", "favorite", id, 0, "", 0)
+// ShowFavorite sets the show as favorite.
+func (bs *BetaSeries) ShowFavorite(show ShowRef) (*Show, error) {
+	return bs.showUpdate("POST", "favorite", show, 0, nil)
+}
+
+// ShowFavoriteRemove remove the show from the favorites.
+func (bs *BetaSeries) ShowFavoriteRemove(show ShowRef) (*Show, error) {
+	return bs.showUpdate("DELETE", "favorite", show, 0, nil)
 }
 
-// ShowFavoriteRemove remove the show 'id' from the favorites.
-func (bs *BetaSeries) ShowFavoriteRemove(id int) (*Show, error) {
-	return bs.showUpdate("DELETE", "favorite", id, 0, "", 0)
+// ShowsSimilarsOptions holds the optional parameters of ShowsSimilars.
+type ShowsSimilarsOptions struct {
+	RequestOptions
+	// Details returns the details of the similar shows.
+	Details bool
 }
 
 // ShowsSimilars returns a slice of shows similar to a given show
-func (bs *BetaSeries) ShowsSimilars(id, theTvdbID int, details bool) ([]Similar, error) {
+func (bs *BetaSeries) ShowsSimilars(show ShowRef, opts *ShowsSimilarsOptions) ([]Similar, error) {
+	if opts == nil {
+		opts = &ShowsSimilarsOptions{}
+	}
 	usedAPI := "/shows/similars"
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
 		return nil, errURLParsing
 	}
 	q := u.Query()
-	if id > 0 {
-		q.Set("id", strconv.Itoa(id))
-	} else if theTvdbID > 0 {
-		q.Set("thetvdb_id", strconv.Itoa(theTvdbID))
-	} else {
-		return nil, errIDNotProperlySet
-	}
-	if details {
+	if err := bs.setShowRef(q, show, showTVDBParams); err != nil {
+		return nil, err
+	}
+	if opts.Details {
 		q.Set("details", "true")
 	}
+	opts.applyExtra(q)
 	u.RawQuery = q.Encode()
 
 	return bs.doGetSimilars(u)
@@ -249,20 +318,16 @@ type characters struct {
 	Characters []Character `json:"characters"`
 }
 
-// ShowsCharacters returns a slice of characters found with the given ID.
-func (bs *BetaSeries) ShowsCharacters(id, theTvdbID int) ([]Character, error) {
+// ShowsCharacters returns a slice of characters of the given show.
+func (bs *BetaSeries) ShowsCharacters(show ShowRef) ([]Character, error) {
 	usedAPI := "/shows/characters"
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
 		return nil, errURLParsing
 	}
 	q := u.Query()
-	if id > 0 {
-		q.Set("id", strconv.Itoa(id))
-	} else if theTvdbID > 0 {
-		q.Set("thetvdb_id", strconv.Itoa(theTvdbID))
-	} else {
-		return nil, errIDNotProperlySet
+	if err := bs.setShowRef(q, show, showTVDBParams); err != nil {
+		return nil, err
 	}
 	u.RawQuery = q.Encode()
 
@@ -285,57 +350,81 @@ func (bs *BetaSeries) ShowsCharacters(id, theTvdbID int) ([]Character, error) {
 	return data.Characters, nil
 }
 
+// ShowsListOptions holds the optional parameters of ShowsList.
+type ShowsListOptions struct {
+	RequestOptions
+	// Since only returns shows updated since the given UNIX timestamp.
+	Since string
+	// Starting only returns shows beginning with the given string.
+	Starting string
+	// Order sorts the results by 'alphabetical', 'popularity' (default) or 'followers'.
+	Order string
+	// Start is the index of the first returned show.
+	Start *int
+	// Limit is the maximum size of the returned slice (default to everything).
+	Limit *int
+}
+
+func (o *ShowsListOptions) validate() error {
+	return firstError(
+		checkEnum("order", o.Order, "alphabetical", "popularity", "followers"),
+		checkNotNegative("start", o.Start),
+		checkPositive("limit", o.Limit),
+	)
+}
+
 // ShowsList returns a slice of shows from an interval. It can return every shows if wanted.
-// 'since' : only displays shows from a specified data (timestamp UNIX - optional)
-// 'starting' : only displays shows beginning with the specified string (optional)
-// 'order': sort order of the result (alphabetical, popularity or followers)
-// 'start' : show id number to begin the listing with (default 0, optional)
-// 'limit' : maximum size of the returned slice (default to everything, optional)
-func (bs *BetaSeries) ShowsList(since, starting, order string, start, limit int) ([]Show, error) {
+func (bs *BetaSeries) ShowsList(opts *ShowsListOptions) ([]Show, error) {
+	if opts == nil {
+		opts = &ShowsListOptions{}
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
 	usedAPI := "/shows/list"
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
 		return nil, errURLParsing
 	}
 	q := u.Query()
-	switch order {
-	case "alphabetical", "popularity", "followers":
-		q.Set("order", order)
-	default:
-		q.Set("order", "popularity")
+	q.Set("order", "popularity")
+	if opts.Order != "" {
+		q.Set("order", opts.Order)
 	}
-	if len(since) != 0 {
-		q.Set("since", since)
+	if opts.Since != "" {
+		q.Set("since", opts.Since)
 	}
-	if len(starting) != 0 {
-		q.Set("starting", starting)
+	if opts.Starting != "" {
+		q.Set("starting", opts.Starting)
 	}
-	if start > 0 {
-		q.Set("start", strconv.Itoa(start))
+	if opts.Start != nil {
+		q.Set("start", strconv.Itoa(*opts.Start))
 	}
-	if limit > 0 {
-		q.Set("limit", strconv.Itoa(limit))
+	if opts.Limit != nil {
+		q.Set("limit", strconv.Itoa(*opts.Limit))
 	}
+	opts.applyExtra(q)
 	u.RawQuery = q.Encode()
 
 	return bs.doGetShows(u, usedAPI)
 }
 
-func (bs *BetaSeries) showUpdate(method, endPoint string, id, theTvdbID int, imdbID string, option int) (*Show, error) {
+func (bs *BetaSeries) showUpdate(method, endPoint string, show ShowRef, option int, extra *RequestOptions) (*Show, error) {
 	usedAPI := "/shows/" + endPoint
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
 		return nil, errURLParsing
 	}
 	q := u.Query()
-	if id > 0 {
-		q.Set("id", strconv.Itoa(id))
-	} else if theTvdbID > 0 {
-		q.Set("thetvdb_id", strconv.Itoa(theTvdbID))
-	} else if imdbID != "" {
-		q.Set("imdb_id", imdbID)
-	} else {
-		return nil, errIDNotProperlySet
+	params := showParams
+	switch endPoint {
+	case "display":
+		params = showDisplayParams
+	case "favorite":
+		params = showIDParams
+	}
+	if err := bs.setShowRef(q, show, params); err != nil {
+		return nil, err
 	}
 	if option > 0 {
 		switch endPoint {
@@ -345,6 +434,7 @@ func (bs *BetaSeries) showUpdate(method, endPoint string, id, theTvdbID int, imd
 			q.Set("episode_id", strconv.Itoa(option))
 		}
 	}
+	extra.applyExtra(q)
 	u.RawQuery = q.Encode()
 
 	resp, err := bs.do(method, u)
@@ -353,40 +443,60 @@ func (bs *BetaSeries) showUpdate(method, endPoint string, id, theTvdbID int, imd
 	}
 	defer resp.Body.Close()
 
-	show := &showItem{}
-	err = bs.decode(show, resp, usedAPI, u.RawQuery)
+	data := &showItem{}
+	err = bs.decode(data, resp, usedAPI, u.RawQuery)
 	if err != nil {
 		return nil, err
 	}
 
-	return show.Show, nil
+	return data.Show, nil
+}
+
+// ShowDisplay returns the show information from the user's account.
+func (bs *BetaSeries) ShowDisplay(show ShowRef) (*Show, error) {
+	return bs.showUpdate("GET", "display", show, 0, nil)
 }
 
-// ShowDisplay returns the show information represented by the given 'id' from the user's account.
-func (bs *BetaSeries) ShowDisplay(id, theTvdbID int, imdbID string) (*Show, error) {
-	return bs.showUpdate("GET", "display", id, theTvdbID, imdbID, 0)
+// ShowAddOptions holds the optional parameters of ShowAdd.
+type ShowAddOptions struct {
+	RequestOptions
+	// LastEpisodeID is the last episode watched; if set, all episodes
+	// until this one are marked as watched.
+	LastEpisodeID *int
 }
 
-// ShowAdd adds the show represented by the given id to the user's account.
-// The last episode watched can be provided; if is it, all episodes until this
-// one should be marked as watched.
-func (bs *BetaSeries) ShowAdd(id, theTvdbID int, imdbID string, lastEpisodeID int) (*Show, error) {
-	return bs.showUpdate("POST", "show", id, theTvdbID, imdbID, lastEpisodeID)
+func (o *ShowAddOptions) validate() error {
+	return checkPositive("last episode id", o.LastEpisodeID)
 }
 
-// ShowRemove removes the show represented by the given id from user's account.
-func (bs *BetaSeries) ShowRemove(id, theTvdbID int, imdbID string) (*Show, error) {
-	return bs.showUpdate("DELETE", "show", id, theTvdbID, imdbID, 0)
+// ShowAdd adds the show to the user's account.
+func (bs *BetaSeries) ShowAdd(show ShowRef, opts *ShowAddOptions) (*Show, error) {
+	if opts == nil {
+		opts = &ShowAddOptions{}
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	lastEpisodeID := 0
+	if opts.LastEpisodeID != nil {
+		lastEpisodeID = *opts.LastEpisodeID
+	}
+	return bs.showUpdate("POST", "show", show, lastEpisodeID, &opts.RequestOptions)
 }
 
-// ShowArchive archives the show represented by the given id from user's account
-func (bs *BetaSeries) ShowArchive(id, theTvdbID int) (*Show, error) {
-	return bs.showUpdate("POST", "archive", id, theTvdbID, "", 0)
+// ShowRemove removes the show from user's account.
+func (bs *BetaSeries) ShowRemove(show ShowRef) (*Show, error) {
+	return bs.showUpdate("DELETE", "show", show, 0, nil)
 }
 
-// ShowNotArchive removes from archives the show represented by the given id from user's account
-func (bs *BetaSeries) ShowNotArchive(id, theTvdbID int) (*Show, error) {
-	return bs.showUpdate("DELETE", "archive", id, theTvdbID, "", 0)
+// ShowArchive archives the show from user's account
+func (bs *BetaSeries) ShowArchive(show ShowRef) (*Show, error) {
+	return bs.showUpdate("POST", "archive", show, 0, nil)
+}
+
+// ShowNotArchive removes from archives the show from user's account
+func (bs *BetaSeries) ShowNotArchive(show ShowRef) (*Show, error) {
+	return bs.showUpdate("DELETE", "archive", show, 0, nil)
 }
 
 // Video represents the video data returned by the betaserie API
@@ -408,23 +518,16 @@ type videos struct {
 }
 
 // ShowsVideos returns a slice of videos added by the betaseries members
-// on a specific show using the show 'id' or 'tvdbID' (strictly positive)
-// Note: do not use both ids, it will return an error
-func (bs *BetaSeries) ShowsVideos(id, tvdbID int) ([]Video, error) {
+// on a specific show
+func (bs *BetaSeries) ShowsVideos(show ShowRef) ([]Video, error) {
 	usedAPI := "/shows/videos"
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
 		return nil, errURLParsing
 	}
 	q := u.Query()
-	if id > 0 && tvdbID > 0 {
-		return nil, errNoSingleIDUsed
-	} else if id > 0 {
-		q.Set("id", strconv.Itoa(id))
-	} else if tvdbID > 0 {
-		q.Set("thetvdb_id ", strconv.Itoa(tvdbID))
-	} else {
-		return nil, errIDNotProperlySet
+	if err := bs.setShowRef(q, show, showTVDBParams); err != nil {
+		return nil, err
 	}
 	u.RawQuery = q.Encode()
 
@@ -447,83 +550,136 @@ func (bs *BetaSeries) ShowsVideos(id, tvdbID int) ([]Video, error) {
 	return data.Videos, nil
 }
 
-// ShowsEpisodes returns a slice of episode for the show represented by the given id.
-// Optional 'season' and 'episode' parameters can be used for precision.
-func (bs *BetaSeries) ShowsEpisodes(id, theTvdbID, season, episode int, subtitles bool) ([]Episode, error) {
+// ShowsEpisodesOptions holds the optional parameters of ShowsEpisodes.
+type ShowsEpisodesOptions struct {
+	RequestOptions
+	// Season only returns the episodes of the given season.
+	Season *int
+	// Episode only returns the given episode of the season. It requires Season.
+	Episode *int
+	// Subtitles returns the subtitles of the episodes.
+	Subtitles bool
+}
+
+func (o *ShowsEpisodesOptions) validate() error {
+	if o.Episode != nil && o.Season == nil {
+		return invalidOption("'episode' requires 'season'")
+	}
+	return firstError(
+		checkNotNegative("season", o.Season),
+		checkPositive("episode", o.Episode),
+	)
+}
+
+// ShowsEpisodes returns a slice of episode for the given show.
+func (bs *BetaSeries) ShowsEpisodes(show ShowRef, opts *ShowsEpisodesOptions) ([]Episode, error) {
+	if opts == nil {
+		opts = &ShowsEpisodesOptions{}
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
 	usedAPI := "/shows/episodes"
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
 		return nil, errURLParsing
 	}
 	q := u.Query()
-	if id > 0 {
-		q.Set("id", strconv.Itoa(id))
-	} else if theTvdbID > 0 {
-		q.Set("thetvdb_id", strconv.Itoa(theTvdbID))
-	} else {
-		return nil, errIDNotProperlySet
-	}
-	if season > 0 {
-		q.Set("season", strconv.Itoa(season))
-		if episode > 0 {
-			q.Set("episode", strconv.Itoa(episode))
-		}
+	if err := bs.setShowRef(q, show, showTVDBParams); err != nil {
+		return nil, err
+	}
+	if opts.Season != nil {
+		q.Set("season", strconv.Itoa(*opts.Season))
 	}
-	if subtitles {
+	if opts.Episode != nil {
+		q.Set("episode", strconv.Itoa(*opts.Episode))
+	}
+	if opts.Subtitles {
 		q.Set("subtitles", "true")
 	}
+	opts.applyExtra(q)
 	u.RawQuery = q.Encode()
 	return bs.doGetEpisodes(u, usedAPI)
 }
 
-// EpisodesList returns a slice of unseen episodes ordered by shows
-func (bs *BetaSeries) EpisodesList(showID, theTvdbID int, imdbID string,
-	userID, limit, released int, subtitles, specials bool) ([]Show, error) {
+// EpisodesListOptions holds the optional parameters of EpisodesList.
+type EpisodesListOptions struct {
+	RequestOptions
+	// Show only returns the episodes of the given show.
+	Show ShowRef
+	// UserID returns the episodes of the given member instead of the identified one.
+	UserID *int
+	// Limit is the maximum number of episodes returned per show.
+	Limit *int
+	// Released only returns released episodes if true, or
+	// unreleased episodes too if false.
+	Released *bool
+	// Subtitles returns the subtitles of the episodes.
+	Subtitles bool
+	// Specials returns the special episodes too.
+	Specials bool
+}
+
+func (o *EpisodesListOptions) validate() error {
+	return firstError(
+		checkPositive("user id", o.UserID),
+		checkPositive("limit", o.Limit),
+	)
+}
 
+// EpisodesList returns a slice of unseen episodes ordered by shows
+func (bs *BetaSeries) EpisodesList(opts *EpisodesListOptions) ([]Show, error) {
+	if opts == nil {
+		opts = &EpisodesListOptions{}
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
 	usedAPI := "/episodes/list"
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
 		return nil, errURLParsing
 	}
 	q := u.Query()
-	if specials {
+	if opts.Specials {
 		q.Set("specials", "true")
 	}
-	if subtitles {
+	if opts.Subtitles {
 		q.Set("subtitles", "true")
 	}
-	if released >= 0 {
-		q.Set("released", strconv.Itoa(released))
-	}
-	if showID > 0 {
-		q.Set("showId", strconv.Itoa(showID))
-	}
-	if theTvdbID > 0 {
-		q.Set("showTheTVDBId", strconv.Itoa(theTvdbID))
+	if opts.Released != nil {
+		released := "0"
+		if *opts.Released {
+			released = "1"
+		}
+		q.Set("released", released)
 	}
-	if imdbID != "" {
-		q.Set("showIMDBId", imdbID)
+	if !opts.Show.IsZero() {
+		if err := bs.setShowRef(q, opts.Show, episodesListParams); err != nil {
+			return nil, err
+		}
 	}
-	if limit > 0 {
-		q.Set("limit", strconv.Itoa(limit))
+	if opts.Limit != nil {
+		q.Set("limit", strconv.Itoa(*opts.Limit))
 	}
-	if userID > 0 {
-		q.Set("userId", strconv.Itoa(userID))
+	if opts.UserID != nil {
+		q.Set("userId", strconv.Itoa(*opts.UserID))
 	}
+	opts.applyExtra(q)
 	u.RawQuery = q.Encode()
 
 	return bs.doGetShows(u, usedAPI)
 }
 
 // ShowNote sets the note (rating) for the given show.
-func (bs *BetaSeries) ShowNote(bsID, theTvdbID, note int) (*Show, error) {
+func (bs *BetaSeries) ShowNote(show ShowRef, note int) (*Show, error) {
 	if note < 1 || note > 5 {
 		return nil, errInvalidNote
 	}
-	return bs.showUpdate("POST", "note", bsID, theTvdbID, "", note)
+	return bs.showUpdate("POST", "note", show, note, nil)
 }
 
 // ShowNoteRemove deletes the current note for the given show.
-func (bs *BetaSeries) ShowNoteRemove(bsID, theTvdbID int) (*Show, error) {
-	return bs.showUpdate("DELETE", "note", bsID, theTvdbID, "", 0)
+func (bs *BetaSeries) ShowNoteRemove(show ShowRef) (*Show, error) {
+	return bs.showUpdate("DELETE", "note", show, 0, nil)
 }
@@ -1,6 +1,7 @@
 package bsclient
 
 import (
+	"errors"
 	"os"
 	"strings"
 
@@ -22,15 +23,15 @@ func (s *MySuite) TestShowsSearch(c *C) {
 	key := os.Getenv("BS_API_KEY")
 	bs, err := NewBetaseriesClient(key, "", "")
 	c.Assert(err, IsNil)
-	shows, err := bs.ShowsSearch(tvShowTest)
+	shows, err := bs.ShowsSearch(tvShowTest, nil)
 	c.Assert(err, IsNil)
 	c.Assert(len(shows), Equals, 1)
 	c.Assert(shows[0].ID, Equals, 481)
 	c.Assert(shows[0].Title, Equals, tvShowTest)
-	c.Assert(shows[0].Seasons, Equals, "5")
-	c.Assert(shows[0].Episodes, Equals, "68")
+	c.Assert(shows[0].Seasons, Equals, 5)
+	c.Assert(shows[0].Episodes, Equals, 68)
 
-	_, err = bs.ShowsSearch("TV Show doesn't exists")
+	_, err = bs.ShowsSearch("TV Show doesn't exists", nil)
 	c.Assert(err, NotNil)
 	c.Assert(err, Equals, errNoShowsFound)
 }
@@ -39,16 +40,16 @@ func (s *MySuite) TestShowsRandom(c *C) {
 	key := os.Getenv("BS_API_KEY")
 	bs, err := NewBetaseriesClient(key, "", "")
 	c.Assert(err, IsNil)
-	shows, err := bs.ShowsRandom(1, false)
+	shows, err := bs.ShowsRandom(&ShowsRandomOptions{Number: Int(1)})
 	c.Assert(err, IsNil)
 	c.Assert(len(shows), Equals, 1)
 	c.Assert(len(shows[0].Language) > 0, Equals, true)
 
-	shows, err = bs.ShowsRandom(0, false)
+	shows, err = bs.ShowsRandom(&ShowsRandomOptions{Number: Int(0)})
 	c.Assert(err, NotNil)
 	c.Assert(err, Equals, errNoShowsFound)
 
-	shows, err = bs.ShowsRandom(1, true)
+	shows, err = bs.ShowsRandom(&ShowsRandomOptions{Number: Int(1), Summary: true})
 	c.Assert(err, IsNil)
 	c.Assert(len(shows), Equals, 1)
 	c.Assert(len(shows[0].Language), Equals, 0)
@@ -58,14 +59,14 @@ func (s *MySuite) TestShowsCharacters(c *C) {
 	key := os.Getenv("BS_API_KEY")
 	bs, err := NewBetaseriesClient(key, "", "")
 	c.Assert(err, IsNil)
-	shows, err := bs.ShowsSearch(tvShowTest)
+	shows, err := bs.ShowsSearch(tvShowTest, nil)
 	c.Assert(err, IsNil)
 	c.Assert(len(shows), Equals, 1)
-	characters, err := bs.ShowsCharacters(shows[0].ID, 0)
+	characters, err := bs.ShowsCharacters(ShowID(shows[0].ID))
 	c.Assert(err, IsNil)
 	c.Assert(len(characters), Equals, 19)
 
-	_, err = bs.ShowsCharacters(123456789, 0)
+	_, err = bs.ShowsCharacters(ShowID(123456789))
 	c.Assert(err, NotNil)
 	c.Assert(err, DeepEquals, &errAPI{
 		[]errorsAPI{err4001},
@@ -76,31 +77,31 @@ func (s *MySuite) TestShowsList(c *C) {
 	key := os.Getenv("BS_API_KEY")
 	bs, err := NewBetaseriesClient(key, "", "")
 	c.Assert(err, IsNil)
-	shows, err := bs.ShowsList("", "", -1, 100)
+	shows, err := bs.ShowsList(&ShowsListOptions{Limit: Int(100)})
 	c.Assert(err, IsNil)
 	c.Assert(len(shows), Equals, 100)
 	c.Assert(shows[0].ID, Equals, 425)
 
-	shows, err = bs.ShowsList("", "", 1, 100)
+	shows, err = bs.ShowsList(&ShowsListOptions{Start: Int(1), Limit: Int(100)})
 	c.Assert(err, IsNil)
 	c.Assert(len(shows), Equals, 100)
 	c.Assert(shows[0].ID, Equals, 481)
 
 	// timestamp to 01-01-3000
-	shows, err = bs.ShowsList("32503680000", "", 1, 100)
+	shows, err = bs.ShowsList(&ShowsListOptions{Since: "32503680000", Start: Int(1), Limit: Int(100)})
 	c.Assert(err, NotNil)
 	c.Assert(err, Equals, errNoShowsFound)
 
 	// timestamp to 01-01-2016
-	shows, err = bs.ShowsList("1451606400", "", 1, 100)
+	shows, err = bs.ShowsList(&ShowsListOptions{Since: "1451606400", Start: Int(1), Limit: Int(100)})
 	c.Assert(err, IsNil)
 	c.Assert(len(shows), Equals, 100)
 
-	shows, err = bs.ShowsList("-wrong-", "", 1, 100)
+	shows, err = bs.ShowsList(&ShowsListOptions{Since: "-wrong-", Start: Int(1), Limit: Int(100)})
 	c.Assert(err, IsNil)
 	c.Assert(len(shows), Equals, 100)
 
-	shows, err = bs.ShowsList("1451606400", "test", -1, 10)
+	shows, err = bs.ShowsList(&ShowsListOptions{Since: "1451606400", Starting: "test", Limit: Int(10)})
 	c.Assert(err, IsNil)
 	c.Assert(len(shows), Equals, 1)
 	c.Assert(shows[0].ID, Equals, 13842)
@@ -109,12 +110,12 @@ func (s *MySuite) TestShowsList(c *C) {
 func (s *MySuite) TestShowsUpdate(c *C) {
 	key := os.Getenv("BS_API_KEY")
 	bs, err := NewBetaseriesClient(key, "Dev050", "developer")
-	show, err := bs.ShowAdd(0, 0)
+	show, err := bs.ShowAdd(ShowID(0), nil)
 	c.Assert(err, NotNil)
 	c.Assert(err, Equals, errIDNotProperlySet)
 
 	bs, err = NewBetaseriesClient(key, "Dev050", "developer")
-	show, err = bs.ShowAdd(1234567890, 0)
+	show, err = bs.ShowAdd(ShowID(1234567890), nil)
 	c.Assert(err, NotNil)
 	c.Assert(err, DeepEquals, &errAPI{
 		[]errorsAPI{err4001},
@@ -122,22 +123,22 @@ func (s *MySuite) TestShowsUpdate(c *C) {
 
 	bs, _, id := makeClientAndAddShow(c)
 
-	show, err = bs.ShowArchive(id, 0)
+	show, err = bs.ShowArchive(ShowID(id))
 	c.Assert(err, IsNil)
 	c.Assert(show.InAccount, Equals, true)
 	c.Assert(show.User.Archived, Equals, true)
 
-	show, err = bs.ShowNotArchive(id, 0)
+	show, err = bs.ShowNotArchive(ShowID(id))
 	c.Assert(err, IsNil)
 	c.Assert(show.InAccount, Equals, true)
 	c.Assert(show.User.Archived, Equals, false)
 
-	show, err = bs.ShowDisplay(id, 0)
+	show, err = bs.ShowDisplay(ShowID(id))
 	c.Assert(err, IsNil)
 	c.Assert(show.InAccount, Equals, true)
 	c.Assert(show.Status, Equals, "Ended")
 
-	show, err = bs.ShowRemove(id, 0)
+	show, err = bs.ShowRemove(ShowID(id))
 	c.Assert(err, IsNil)
 	c.Assert(show.InAccount, Equals, false)
 }
@@ -146,24 +147,24 @@ func (s *MySuite) TestShowsVideos(c *C) {
 	key := os.Getenv("BS_API_KEY")
 	bs, err := NewBetaseriesClient(key, "", "")
 	c.Assert(err, IsNil)
-	videos, err := bs.ShowsVideos(1, 0)
+	videos, err := bs.ShowsVideos(ShowID(1))
 	c.Assert(err, IsNil)
 	c.Assert(len(videos), Equals, 6)
 	c.Assert(strings.Contains(videos[0].YoutubeURL, "http"), Equals, true)
 
-	videos, err = bs.ShowsVideos(0, 1)
+	videos, err = bs.ShowsVideos(ShowTVDB(1))
 	c.Assert(err, NotNil)
 	c.Assert(err, DeepEquals, &errAPI{
 		[]errorsAPI{err4001},
 	})
 	c.Assert(len(videos), Equals, 0)
 
-	videos, err = bs.ShowsVideos(1, 1)
+	videos, err = bs.ShowsVideos(ShowRef{ID: 1, TVDB: 1})
 	c.Assert(err, NotNil)
 	c.Assert(err, Equals, errNoSingleIDUsed)
 	c.Assert(len(videos), Equals, 0)
 
-	videos, err = bs.ShowsVideos(0, 0)
+	videos, err = bs.ShowsVideos(ShowRef{})
 	c.Assert(err, NotNil)
 	c.Assert(err, Equals, errIDNotProperlySet)
 	c.Assert(len(videos), Equals, 0)
@@ -173,23 +174,23 @@ func (s *MySuite) TestShowsEpisodes(c *C) {
 	key := os.Getenv("BS_API_KEY")
 	bs, err := NewBetaseriesClient(key, "", "")
 	c.Assert(err, IsNil)
-	shows, err := bs.ShowsSearch(tvShowTest)
+	shows, err := bs.ShowsSearch(tvShowTest, nil)
 	c.Assert(err, IsNil)
 	c.Assert(shows, HasLen, 1)
 
-	episodes, err := bs.ShowsEpisodes(shows[0].ID, 0, 0, 0, false)
+	episodes, err := bs.ShowsEpisodes(shows[0].Ref(), nil)
 	c.Assert(err, IsNil)
 	c.Assert(episodes, HasLen, 68)
 
-	episodes, err = bs.ShowsEpisodes(shows[0].ID, 0, 1, 0, false)
+	episodes, err = bs.ShowsEpisodes(shows[0].Ref(), &ShowsEpisodesOptions{Season: Int(1)})
 	c.Assert(err, IsNil)
 	c.Assert(episodes, HasLen, 12)
 
-	episodes, err = bs.ShowsEpisodes(shows[0].ID, 0, 1, 1, false)
+	episodes, err = bs.ShowsEpisodes(shows[0].Ref(), &ShowsEpisodesOptions{Season: Int(1), Episode: Int(1)})
 	c.Assert(err, IsNil)
 	c.Assert(episodes, HasLen, 1)
 
-	episodes, err = bs.ShowsEpisodes(shows[0].ID, 0, -1, -1, false)
-	c.Assert(err, IsNil)
-	c.Assert(episodes, HasLen, 68)
+	episodes, err = bs.ShowsEpisodes(shows[0].Ref(), &ShowsEpisodesOptions{Season: Int(-1)})
+	c.Assert(err, NotNil)
+	c.Assert(errors.Is(err, errInvalidOption), Equals, true)
 }
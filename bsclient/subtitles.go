@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 var (
@@ -28,7 +29,7 @@ type Subtitle struct {
 		Season    int `json:"season"`
 		Episode   int `json:"episode"`
 	} `json:"episode"`
-	Date string `json:"date"`
+	Date time.Time `json:"date"`
 }
 
 type subtitles struct {
@@ -56,64 +57,95 @@ func (bs *BetaSeries) doGetSubtitles(u *url.URL, usedAPI string) ([]Subtitle, er
 	return data.Subtitles, nil
 }
 
-// SubtitlesEpisode returns a slice of subtitles for a given episode
-// The language can be provided to filter results (all|vovf|vo|vf).
-func (bs *BetaSeries) SubtitlesEpisode(id int, language string) ([]Subtitle, error) {
-	usedAPI := "/subtitles/episode"
+// SubtitlesOptions holds the optional parameters of SubtitlesEpisode and SubtitlesShow.
+type SubtitlesOptions struct {
+	RequestOptions
+	// Language filters the results: all, vovf, vo or vf.
+	Language string
+}
+
+func (o *SubtitlesOptions) validate() error {
+	return checkEnum("language", o.Language, "all", "vovf", "vo", "vf")
+}
+
+// subtitlesOf returns the subtitles of a show or an episode, 'q' holding its reference.
+func (bs *BetaSeries) subtitlesOf(endPoint string, q url.Values, opts *SubtitlesOptions) ([]Subtitle, error) {
+	if opts == nil {
+		opts = &SubtitlesOptions{}
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	usedAPI := "/subtitles/" + endPoint
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
 		return nil, errURLParsing
 	}
-	q := u.Query()
-	if id <= 0 {
-		return nil, errIDNotProperlySet
-	}
-	q.Set("id", strconv.Itoa(id))
-	if language != "" {
-		q.Set("language", language)
+	if opts.Language != "" {
+		q.Set("language", opts.Language)
 	}
+	opts.applyExtra(q)
 	u.RawQuery = q.Encode()
 
 	return bs.doGetSubtitles(u, usedAPI)
 }
 
-// SubtitlesShow returns a slice of subtitles for a given show
-// The language can be provided to filter results (all|vovf|vo|vf).
-func (bs *BetaSeries) SubtitlesShow(id int, language string) ([]Subtitle, error) {
-	usedAPI := "/subtitles/show"
-	u, err := url.Parse(bs.baseURL + usedAPI)
-	if err != nil {
-		return nil, errURLParsing
-	}
-	q := u.Query()
-	if id <= 0 {
-		return nil, errIDNotProperlySet
+// SubtitlesEpisode returns a slice of subtitles for a given episode
+func (bs *BetaSeries) SubtitlesEpisode(episode EpisodeRef, opts *SubtitlesOptions) ([]Subtitle, error) {
+	q := url.Values{}
+	if err := bs.setEpisodeRef(q, episode, episodeIDParams); err != nil {
+		return nil, err
 	}
-	q.Set("id", strconv.Itoa(id))
-	if language != "" {
-		q.Set("language", language)
+	return bs.subtitlesOf("episode", q, opts)
+}
+
+// SubtitlesShow returns a slice of subtitles for a given show
+func (bs *BetaSeries) SubtitlesShow(show ShowRef, opts *SubtitlesOptions) ([]Subtitle, error) {
+	q := url.Values{}
+	if err := bs.setShowRef(q, show, showIDParams); err != nil {
+		return nil, err
 	}
-	u.RawQuery = q.Encode()
+	return bs.subtitlesOf("show", q, opts)
+}
 
-	return bs.doGetSubtitles(u, usedAPI)
+// SubtitlesLastOptions holds the optional parameters of SubtitlesLast.
+type SubtitlesLastOptions struct {
+	SubtitlesOptions
+	// Number of subtitles, it can't be higher than 100 with current API.
+	Number *int
+}
+
+func (o *SubtitlesLastOptions) validate() error {
+	if o.Number != nil && *o.Number > maxSubtitlesLast {
+		return invalidOption("'number' can't be higher than %d", maxSubtitlesLast)
+	}
+	return firstError(
+		o.SubtitlesOptions.validate(),
+		checkPositive("number", o.Number),
+	)
 }
 
 // SubtitlesLast returns a slice of the last BetaSeries subtitles
-// The number can't be higher than 100 with current API.
-// The language can be provided to filter results (all|vovf|vo|vf).
-func (bs *BetaSeries) SubtitlesLast(number int, language string) ([]Subtitle, error) {
+func (bs *BetaSeries) SubtitlesLast(opts *SubtitlesLastOptions) ([]Subtitle, error) {
+	if opts == nil {
+		opts = &SubtitlesLastOptions{}
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
 	usedAPI := "/subtitles/last"
 	u, err := url.Parse(bs.baseURL + usedAPI)
 	if err != nil {
 		return nil, errURLParsing
 	}
 	q := u.Query()
-	if number > 0 {
-		q.Set("number", strconv.Itoa(number))
+	if opts.Number != nil {
+		q.Set("number", strconv.Itoa(*opts.Number))
 	}
-	if language != "" {
-		q.Set("language", language)
+	if opts.Language != "" {
+		q.Set("language", opts.Language)
 	}
+	opts.applyExtra(q)
 	u.RawQuery = q.Encode()
 
 	return bs.doGetSubtitles(u, usedAPI)
@@ -0,0 +1,175 @@
+package bsclient
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	scoreLanguage = 1000
+	scoreGroup    = 200
+	scoreTag      = 20
+	scoreSource   = 10
+	scoreQuality  = 5
+)
+
+// SubtitlePreferences configures the ranking done by RankSubtitles.
+type SubtitlePreferences struct {
+	// Languages lists the accepted languages (vo, vf, vovf) ordered by preference.
+	// Subtitles in other languages are discarded. Every language is accepted if empty.
+	Languages []string
+	// Sources lists the preferred subtitle sources (addic7ed, tvsubtitles, ...)
+	// ordered by preference. Other sources are accepted but not rewarded.
+	Sources []string
+	// MinQuality discards subtitles with a lower quality.
+	MinQuality int
+}
+
+// RankedSubtitle is a subtitle along with its ranking score
+// and the reasons explaining this score.
+type RankedSubtitle struct {
+	Subtitle
+	Score   int
+	Reasons []string
+}
+
+func (s *Subtitle) fileNames() []string {
+	names := make([]string, 0, len(s.Content)+1)
+	if s.File != "" {
+		names = append(names, s.File)
+	}
+	for _, name := range s.Content {
+		names = append(names, string(name))
+	}
+	return names
+}
+
+func indexOf(list []string, value string) int {
+	for i, v := range list {
+		if strings.EqualFold(v, value) {
+			return i
+		}
+	}
+	return -1
+}
+
+func scoreSubtitle(s *Subtitle, release *Release, prefs *SubtitlePreferences) (*RankedSubtitle, bool) {
+	ranked := &RankedSubtitle{Subtitle: *s}
+	if s.Quality < prefs.MinQuality {
+		return nil, false
+	}
+	if len(prefs.Languages) > 0 {
+		idx := indexOf(prefs.Languages, s.Language)
+		if idx < 0 {
+			return nil, false
+		}
+		ranked.Score += (len(prefs.Languages) - idx) * scoreLanguage
+		ranked.Reasons = append(ranked.Reasons,
+			fmt.Sprintf("language %s is preference #%d", s.Language, idx+1))
+	}
+	if idx := indexOf(prefs.Sources, s.Source); idx >= 0 {
+		ranked.Score += (len(prefs.Sources) - idx) * scoreSource
+		ranked.Reasons = append(ranked.Reasons,
+			fmt.Sprintf("source %s is preference #%d", s.Source, idx+1))
+	}
+	if s.Quality > 0 {
+		ranked.Score += s.Quality * scoreQuality
+		ranked.Reasons = append(ranked.Reasons, fmt.Sprintf("quality %d", s.Quality))
+	}
+
+	bestTags := []string{}
+	groupFile := ""
+	for _, name := range s.fileNames() {
+		info, _ := parseReleaseTags(name)
+		if release.Group != "" && strings.EqualFold(info.Group, release.Group) && groupFile == "" {
+			groupFile = name
+		}
+		tags := []string{}
+		for _, tag := range release.tags() {
+			if indexOf(info.tags(), tag) >= 0 {
+				tags = append(tags, tag)
+			}
+		}
+		if len(tags) > len(bestTags) {
+			bestTags = tags
+		}
+	}
+	if groupFile != "" {
+		ranked.Score += scoreGroup
+		ranked.Reasons = append(ranked.Reasons,
+			fmt.Sprintf("release group %s matches %s", release.Group, groupFile))
+	}
+	if len(bestTags) > 0 {
+		ranked.Score += len(bestTags) * scoreTag
+		ranked.Reasons = append(ranked.Reasons,
+			fmt.Sprintf("release tags match: %s", strings.Join(bestTags, ", ")))
+	}
+	return ranked, true
+}
+
+// RankSubtitles scores the given subtitles against a video release name
+// (e.g. 'Show.S01E01.720p.HDTV.x264-GROUP.mkv') and returns them ordered from the
+// best to the worst candidate. Subtitles rejected by the preferences are dropped.
+// The score favors, in this order: the language preference, a release group found
+// in the subtitle file names, matching encode tags, the source preference and the
+// quality. Ties are broken by the most recent subtitle.
+func RankSubtitles(subtitles []Subtitle, release string, prefs *SubtitlePreferences) []RankedSubtitle {
+	if prefs == nil {
+		prefs = &SubtitlePreferences{}
+	}
+	info, _ := parseReleaseTags(release)
+	ranked := []RankedSubtitle{}
+	for i := range subtitles {
+		if r, ok := scoreSubtitle(&subtitles[i], info, prefs); ok {
+			ranked = append(ranked, *r)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Date.After(ranked[j].Date)
+	})
+	return ranked
+}
+
+// SubtitlesBestOptions holds the optional parameters of SubtitlesBest.
+type SubtitlesBestOptions struct {
+	RequestOptions
+	// Preferences are used to rank the subtitles, see RankSubtitles.
+	Preferences *SubtitlePreferences
+	// Max is the maximum number of returned subtitles.
+	// Every accepted subtitle is returned if not set.
+	Max *int
+}
+
+func (o *SubtitlesBestOptions) validate() error {
+	return checkPositive("max", o.Max)
+}
+
+// SubtitlesBest returns a shortlist of subtitles of the episode
+// ranked against the video 'release' name with RankSubtitles.
+func (bs *BetaSeries) SubtitlesBest(episode EpisodeRef, release string, opts *SubtitlesBestOptions) ([]RankedSubtitle, error) {
+	if opts == nil {
+		opts = &SubtitlesBestOptions{}
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	subtitles, err := bs.SubtitlesEpisode(episode, &SubtitlesOptions{
+		RequestOptions: opts.RequestOptions,
+		Language:       "all",
+	})
+	if err != nil {
+		return nil, err
+	}
+	ranked := RankSubtitles(subtitles, release, opts.Preferences)
+	if len(ranked) < 1 {
+		return nil, errNoSubtitlesFound
+	}
+	if opts.Max != nil && len(ranked) > *opts.Max {
+		ranked = ranked[:*opts.Max]
+	}
+	return ranked, nil
+}
@@ -0,0 +1,52 @@
+package bsclient
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func makeSubtitle(id int, language, source string, quality int, date string, content ...FileName) Subtitle {
+	d, _ := time.Parse("2006-01-02 15:04:05", date)
+	return Subtitle{
+		ID:       id,
+		Language: language,
+		Source:   source,
+		Quality:  quality,
+		Content:  content,
+		Date:     d,
+	}
+}
+
+func (s *MySuite) TestRankSubtitles(c *C) {
+	subtitles := []Subtitle{
+		makeSubtitle(1, "VO", "addic7ed", 3, "2016-01-01 10:00:00", "Show.S01E01.720p.HDTV.x264-KILLERS.en.srt"),
+		makeSubtitle(2, "VF", "addic7ed", 3, "2016-01-01 10:00:00", "Show.S01E01.720p.HDTV.x264-DIMENSION.fr.srt"),
+		makeSubtitle(3, "VF", "tvsubtitles", 3, "2016-01-01 10:00:00", "Show.S01E01.720p.HDTV.x264-KILLERS.fr.srt"),
+		makeSubtitle(4, "VF", "tvsubtitles", 3, "2016-01-02 10:00:00", "Show.S01E01.720p.HDTV.x264-KILLERS.fr.srt"),
+		makeSubtitle(5, "VF", "tvsubtitles", 1, "2016-01-03 10:00:00", "Show.S01E01.720p.HDTV.x264-KILLERS.fr.srt"),
+		makeSubtitle(6, "VOVF", "tvsubtitles", 5, "2016-01-03 10:00:00"),
+	}
+	prefs := &SubtitlePreferences{
+		Languages:  []string{"vf", "vo"},
+		Sources:    []string{"addic7ed"},
+		MinQuality: 2,
+	}
+	ranked := RankSubtitles(subtitles, "Show.S01E01.720p.HDTV.x264-KILLERS.mkv", prefs)
+	c.Assert(ranked, HasLen, 4)
+	// same score, the most recent comes first
+	c.Assert(ranked[0].ID, Equals, 4)
+	c.Assert(ranked[1].ID, Equals, 3)
+	c.Assert(ranked[2].ID, Equals, 2)
+	c.Assert(ranked[3].ID, Equals, 1)
+	c.Assert(ranked[0].Reasons, DeepEquals, []string{
+		"language VF is preference #1",
+		"quality 3",
+		"release group KILLERS matches Show.S01E01.720p.HDTV.x264-KILLERS.fr.srt",
+		"release tags match: 720p, hdtv, x264",
+	})
+
+	ranked = RankSubtitles(subtitles, "Show.S01E01.1080p.WEB-DL.mkv", nil)
+	c.Assert(ranked, HasLen, 6)
+	c.Assert(ranked[0].ID, Equals, 6)
+}
@@ -0,0 +1,260 @@
+package bsclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APILocation is the time zone of the dates returned by the betaseries API.
+// It defaults to Europe/Paris, or UTC if the time zone database is not available.
+var APILocation = loadAPILocation()
+
+func loadAPILocation() *time.Location {
+	location, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+var timeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC3339Nano,
+}
+
+// isEmptyJSON returns true for missing, null, empty string and zero date values,
+// all used by the API to mean 'unset'.
+func isEmptyJSON(raw json.RawMessage) bool {
+	switch string(bytes.TrimSpace(raw)) {
+	case "", "null", `""`, `"0000-00-00"`, `"0000-00-00 00:00:00"`:
+		return true
+	}
+	return false
+}
+
+// unquote returns the content of a JSON string, or the raw value for other types.
+func unquote(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return strings.TrimSpace(s)
+	}
+	return string(bytes.TrimSpace(raw))
+}
+
+// decodeInt decodes an integer sent as a number or as a string.
+func decodeInt(raw json.RawMessage) (int, error) {
+	if isEmptyJSON(raw) {
+		return 0, nil
+	}
+	value := unquote(raw)
+	if i, err := strconv.Atoi(value); err == nil {
+		return i, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer %s", raw)
+	}
+	return int(f), nil
+}
+
+// decodeBool decodes a boolean sent as a boolean, a number or a string.
+func decodeBool(raw json.RawMessage) (bool, error) {
+	if isEmptyJSON(raw) {
+		return false, nil
+	}
+	value := unquote(raw)
+	if b, err := strconv.ParseBool(value); err == nil {
+		return b, nil
+	}
+	i, err := decodeInt(raw)
+	if err != nil {
+		return false, fmt.Errorf("invalid boolean %s", raw)
+	}
+	return i != 0, nil
+}
+
+// decodeTime decodes a date or a date and time in the API time zone.
+func decodeTime(raw json.RawMessage) (time.Time, error) {
+	if isEmptyJSON(raw) {
+		return time.Time{}, nil
+	}
+	value := unquote(raw)
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, value, APILocation); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %s", raw)
+}
+
+// decodeMinutes decodes a duration sent as a number of minutes.
+func decodeMinutes(raw json.RawMessage) (time.Duration, error) {
+	minutes, err := decodeInt(raw)
+	return time.Duration(minutes) * time.Minute, err
+}
+
+// decodeInts decodes several integers, stopping at the first error.
+func decodeInts(fields map[*int]json.RawMessage) error {
+	for dst, raw := range fields {
+		value, err := decodeInt(raw)
+		if err != nil {
+			return err
+		}
+		*dst = value
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes a show, tolerating numbers sent as strings and empty values.
+func (s *Show) UnmarshalJSON(data []byte) error {
+	type plain Show
+	aux := struct {
+		*plain
+		Seasons    json.RawMessage `json:"seasons"`
+		Episodes   json.RawMessage `json:"episodes"`
+		Followers  json.RawMessage `json:"followers"`
+		Comments   json.RawMessage `json:"comments"`
+		Similars   json.RawMessage `json:"similars"`
+		Characters json.RawMessage `json:"characters"`
+		Creation   json.RawMessage `json:"creation"`
+		Length     json.RawMessage `json:"length"`
+	}{plain: (*plain)(s)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	err := decodeInts(map[*int]json.RawMessage{
+		&s.Seasons:    aux.Seasons,
+		&s.Episodes:   aux.Episodes,
+		&s.Followers:  aux.Followers,
+		&s.Comments:   aux.Comments,
+		&s.Similars:   aux.Similars,
+		&s.Characters: aux.Characters,
+		&s.Creation:   aux.Creation,
+	})
+	if err != nil {
+		return err
+	}
+	s.Length, err = decodeMinutes(aux.Length)
+	return err
+}
+
+// MarshalJSON encodes a show like the API does, with a length in minutes.
+func (s Show) MarshalJSON() ([]byte, error) {
+	type plain Show
+	return json.Marshal(struct {
+		plain
+		Length int `json:"length"`
+	}{
+		plain:  plain(s),
+		Length: int(s.Length / time.Minute),
+	})
+}
+
+// similarFields holds the fields of Similar, whose embedded Show
+// would otherwise take over its JSON encoding.
+type similarFields struct {
+	ID        int    `json:"id"`
+	Login     string `json:"login"`
+	LoginID   int    `json:"login_id"`
+	Notes     string `json:"notes"`
+	ShowTitle string `json:"show_title"`
+	ShowID    int    `json:"show_id"`
+	ThetvdbID int    `json:"thetvdb_id"`
+	Show      Show   `json:"show"`
+}
+
+// UnmarshalJSON decodes a similar show.
+func (s *Similar) UnmarshalJSON(data []byte) error {
+	aux := similarFields{}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*s = Similar{
+		ID:        aux.ID,
+		Login:     aux.Login,
+		LoginID:   aux.LoginID,
+		Notes:     aux.Notes,
+		ShowTitle: aux.ShowTitle,
+		ShowID:    aux.ShowID,
+		ThetvdbID: aux.ThetvdbID,
+		Show:      aux.Show,
+	}
+	return nil
+}
+
+// MarshalJSON encodes a similar show.
+func (s Similar) MarshalJSON() ([]byte, error) {
+	return json.Marshal(similarFields{
+		ID:        s.ID,
+		Login:     s.Login,
+		LoginID:   s.LoginID,
+		Notes:     s.Notes,
+		ShowTitle: s.ShowTitle,
+		ShowID:    s.ShowID,
+		ThetvdbID: s.ThetvdbID,
+		Show:      s.Show,
+	})
+}
+
+// UnmarshalJSON decodes an episode, tolerating numbers and booleans
+// sent as strings and empty dates.
+func (e *Episode) UnmarshalJSON(data []byte) error {
+	type plain Episode
+	aux := struct {
+		*plain
+		Special  json.RawMessage `json:"special"`
+		Date     json.RawMessage `json:"date"`
+		Comments json.RawMessage `json:"comments"`
+	}{plain: (*plain)(e)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	if e.Special, err = decodeBool(aux.Special); err != nil {
+		return err
+	}
+	if e.Date, err = decodeTime(aux.Date); err != nil {
+		return err
+	}
+	e.Comments, err = decodeInt(aux.Comments)
+	return err
+}
+
+// UnmarshalJSON decodes a news, tolerating an empty date.
+func (n *News) UnmarshalJSON(data []byte) error {
+	type plain News
+	aux := struct {
+		*plain
+		Date json.RawMessage `json:"date"`
+	}{plain: (*plain)(n)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	n.Date, err = decodeTime(aux.Date)
+	return err
+}
+
+// UnmarshalJSON decodes a subtitle, tolerating a quality sent as a string and an empty date.
+func (s *Subtitle) UnmarshalJSON(data []byte) error {
+	type plain Subtitle
+	aux := struct {
+		*plain
+		Quality json.RawMessage `json:"quality"`
+		Date    json.RawMessage `json:"date"`
+	}{plain: (*plain)(s)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	if s.Quality, err = decodeInt(aux.Quality); err != nil {
+		return err
+	}
+	s.Date, err = decodeTime(aux.Date)
+	return err
+}
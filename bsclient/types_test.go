@@ -0,0 +1,61 @@
+package bsclient
+
+import (
+	"encoding/json"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestDecodeShow(c *C) {
+	show := &Show{}
+	err := json.Unmarshal([]byte(`{"id":481,"title":"Breaking Bad","seasons":"5","episodes":68,
+		"followers":"","comments":null,"creation":"2008","length":"47"}`), show)
+	c.Assert(err, IsNil)
+	c.Assert(show.ID, Equals, 481)
+	c.Assert(show.Title, Equals, "Breaking Bad")
+	c.Assert(show.Seasons, Equals, 5)
+	c.Assert(show.Episodes, Equals, 68)
+	c.Assert(show.Followers, Equals, 0)
+	c.Assert(show.Creation, Equals, 2008)
+	c.Assert(show.Length, Equals, 47*time.Minute)
+
+	data, err := json.Marshal(show)
+	c.Assert(err, IsNil)
+	decoded := &Show{}
+	c.Assert(json.Unmarshal(data, decoded), IsNil)
+	c.Assert(decoded, DeepEquals, show)
+
+	err = json.Unmarshal([]byte(`{"seasons":"five"}`), show)
+	c.Assert(err, ErrorMatches, "invalid integer .*")
+}
+
+func (s *MySuite) TestDecodeSimilar(c *C) {
+	similar := &Similar{}
+	err := json.Unmarshal([]byte(`{"id":1,"show_title":"Lost","show_id":2,"show":{"id":2,"seasons":"6"}}`), similar)
+	c.Assert(err, IsNil)
+	c.Assert(similar.ID, Equals, 1)
+	c.Assert(similar.ShowID, Equals, 2)
+	c.Assert(similar.Show.ID, Equals, 2)
+	c.Assert(similar.Show.Seasons, Equals, 6)
+}
+
+func (s *MySuite) TestDecodeEpisode(c *C) {
+	episode := &Episode{}
+	err := json.Unmarshal([]byte(`{"id":1,"special":"1","date":"2013-09-15","comments":"12"}`), episode)
+	c.Assert(err, IsNil)
+	c.Assert(episode.Special, Equals, true)
+	c.Assert(episode.Date, DeepEquals, time.Date(2013, 9, 15, 0, 0, 0, 0, APILocation))
+	c.Assert(episode.Comments, Equals, 12)
+
+	episode = &Episode{}
+	err = json.Unmarshal([]byte(`{"special":0,"date":"0000-00-00"}`), episode)
+	c.Assert(err, IsNil)
+	c.Assert(episode.Special, Equals, false)
+	c.Assert(episode.Date.IsZero(), Equals, true)
+
+	news := &News{}
+	err = json.Unmarshal([]byte(`{"id":"1","date":"2016-01-31 20:15:00"}`), news)
+	c.Assert(err, IsNil)
+	c.Assert(news.Date, DeepEquals, time.Date(2016, 1, 31, 20, 15, 0, 0, APILocation))
+}
@@ -0,0 +1,103 @@
+package cassette
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dns-gh/bs-client/bsclient"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+func newAPI(c *C) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/members/auth":
+			w.Write([]byte(`{"user": {"id": 1, "login": "walter"}, "token": "a1b2c3d4"}`))
+		case "/shows/display":
+			c.Check(r.Header.Get("X-BetaSeries-Token"), Equals, "a1b2c3d4")
+			w.Write([]byte(`{"show": {"id": 1, "title": "Breaking Bad"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors": [{"code": 4001, "text": "Not found."}]}`))
+		}
+	}))
+}
+
+func (s *MySuite) TestRecordReplay(c *C) {
+	path := filepath.Join(c.MkDir(), "testdata", "shows.json")
+	server := newAPI(c)
+	r, err := New(path, ModeAuto)
+	c.Assert(err, IsNil)
+	c.Assert(r.Mode(), Equals, ModeRecord)
+	bs, err := bsclient.NewBetaseriesClient("secret-key", "walter", "password",
+		bsclient.WithBaseURL(server.URL), r.Option())
+	c.Assert(err, IsNil)
+	show, err := bs.ShowDisplay(bsclient.ShowID(1))
+	c.Assert(err, IsNil)
+	c.Assert(show.Title, Equals, "Breaking Bad")
+	_, err = bs.ShowsCharacters(bsclient.ShowID(1))
+	c.Assert(bsclient.ErrorCode(err), Equals, 4001)
+	c.Assert(r.Stop(), IsNil)
+	server.Close()
+
+	data, err := os.ReadFile(path)
+	c.Assert(err, IsNil)
+	for _, secret := range []string{"secret-key", "walter", "a1b2c3d4", "5f4dcc3b5aa765d61d8327deb882cf99"} {
+		c.Assert(strings.Contains(string(data), secret), Equals, false, Commentf("%s recorded", secret))
+	}
+	cassette, err := Load(path)
+	c.Assert(err, IsNil)
+	c.Assert(cassette.Interactions, HasLen, 3)
+	c.Assert(cassette.Interactions[0].Request.URL, Equals,
+		server.URL+"/members/auth?login=redacted-login&password=redacted-password")
+	c.Assert(http.Header(cassette.Interactions[1].Request.Header).Get("X-BetaSeries-Token"), Equals, "redacted-token")
+
+	// the replay does not need the API, and other credentials work
+	r, err = New(path, ModeAuto)
+	c.Assert(err, IsNil)
+	c.Assert(r.Mode(), Equals, ModeReplay)
+	bs, err = bsclient.NewBetaseriesClient("other-key", "jesse", "other",
+		bsclient.WithBaseURL("http://localhost:1"), r.Option())
+	c.Assert(err, IsNil)
+	show, err = bs.ShowDisplay(bsclient.ShowID(1))
+	c.Assert(err, IsNil)
+	c.Assert(show.Title, Equals, "Breaking Bad")
+	_, err = bs.ShowsCharacters(bsclient.ShowID(1))
+	c.Assert(bsclient.ErrorCode(err), Equals, 4001)
+	c.Assert(r.Unused(), Equals, 0)
+
+	// every interaction is replayed once
+	_, err = bs.ShowDisplay(bsclient.ShowID(1))
+	c.Assert(errors.Is(err, errNoInteraction), Equals, true)
+	c.Assert(r.Stop(), IsNil)
+}
+
+func (s *MySuite) TestMatchers(c *C) {
+	recorded := Request{Method: "GET", URL: "https://api.betaseries.com/shows/display?id=1&key=redacted-key"}
+	req := httptest.NewRequest("GET", "http://localhost/shows/display?key=other&id=1", nil)
+	c.Assert(DefaultMatcher(req, recorded), Equals, true)
+
+	req = httptest.NewRequest("GET", "http://localhost/shows/display?id=2&key=other", nil)
+	c.Assert(MatchQuery(req, recorded), Equals, false)
+	c.Assert(All(MatchMethod, MatchPath)(req, recorded), Equals, true)
+
+	req = httptest.NewRequest("GET", "http://localhost/shows/display?id=1", nil)
+	c.Assert(MatchQuery(req, recorded), Equals, false)
+
+	req = httptest.NewRequest("POST", "http://localhost/shows/display?id=1&key=other", nil)
+	c.Assert(DefaultMatcher(req, recorded), Equals, false)
+
+	_, err := New(filepath.Join(c.MkDir(), "missing.json"), ModeReplay)
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,61 @@
+package cassette
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+)
+
+// Matcher tells whether a recorded request matches a request to replay.
+type Matcher func(r *http.Request, recorded Request) bool
+
+// MatchMethod matches the requests with the same method.
+func MatchMethod(r *http.Request, recorded Request) bool {
+	return r.Method == recorded.Method
+}
+
+// MatchPath matches the requests with the same path, whatever the host.
+func MatchPath(r *http.Request, recorded Request) bool {
+	u, err := url.Parse(recorded.URL)
+	return err == nil && u.Path == r.URL.Path
+}
+
+// MatchQuery matches the requests with the same query parameters, but the
+// values of the scrubbed ones.
+func MatchQuery(r *http.Request, recorded Request) bool {
+	u, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+	q, expected := r.URL.Query(), u.Query()
+	if len(q) != len(expected) {
+		return false
+	}
+	for name, values := range expected {
+		if _, secret := secretParams[name]; secret {
+			if _, ok := q[name]; !ok {
+				return false
+			}
+			continue
+		}
+		if !slices.Equal(q[name], values) {
+			return false
+		}
+	}
+	return true
+}
+
+// All matches the requests matched by every given matcher.
+func All(matchers ...Matcher) Matcher {
+	return func(r *http.Request, recorded Request) bool {
+		for _, match := range matchers {
+			if !match(r, recorded) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// DefaultMatcher matches the requests on their method, path and query.
+var DefaultMatcher = All(MatchMethod, MatchPath, MatchQuery)
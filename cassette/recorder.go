@@ -0,0 +1,181 @@
+package cassette
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+// Mode tells whether a recorder records or replays interactions.
+type Mode int
+
+const (
+	// ModeReplay replays the interactions of the cassette, never sending requests.
+	ModeReplay Mode = iota
+	// ModeRecord sends the requests and records their interactions,
+	// replacing the cassette when stopped.
+	ModeRecord
+	// ModeAuto replays the cassette if it exists, and records it otherwise.
+	ModeAuto
+)
+
+var errNoInteraction = errors.New("no recorded interaction matches the request")
+
+// Recorder is an HTTP transport recording or replaying the interactions of a cassette.
+type Recorder struct {
+	// Transport sends the requests while recording, http.DefaultTransport if nil.
+	Transport http.RoundTripper
+	// Matcher finds the recorded interaction of a request, DefaultMatcher if nil.
+	Matcher Matcher
+
+	path     string
+	mode     Mode
+	mutex    sync.Mutex
+	cassette *Cassette
+	used     []bool
+	scrubber *scrubber
+}
+
+// New returns a recorder of the cassette file 'path', loaded if replayed.
+func New(path string, mode Mode) (*Recorder, error) {
+	if mode == ModeAuto {
+		mode = ModeReplay
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			mode = ModeRecord
+		}
+	}
+	r := &Recorder{
+		path:     path,
+		mode:     mode,
+		cassette: &Cassette{Version: Version},
+		scrubber: newScrubber(),
+	}
+	if mode == ModeReplay {
+		c, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = c
+		r.used = make([]bool, len(c.Interactions))
+	}
+	return r, nil
+}
+
+// Mode returns the mode of the recorder, ModeRecord or ModeReplay.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an HTTP client using the recorder.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Option returns the client option sending the requests of a client through the recorder.
+func (r *Recorder) Option() bsclient.ClientOption {
+	return bsclient.WithHTTPClient(r.Client())
+}
+
+// RoundTrip records or replays a request.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModeRecord {
+		return r.record(req)
+	}
+	return r.replay(req)
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(strings.NewReader(string(body)))
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.scrubber.learn(req, string(body))
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: req.Header.Clone(),
+		},
+		Response: Response{
+			Status: resp.StatusCode,
+			Header: resp.Header.Clone(),
+			Body:   string(body),
+		},
+	})
+	return resp, nil
+}
+
+// replay returns the response of the first unused interaction matching the request.
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	match := r.Matcher
+	if match == nil {
+		match = DefaultMatcher
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !match(req, interaction.Request) {
+			continue
+		}
+		r.used[i] = true
+		resp := interaction.Response
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", resp.Status, http.StatusText(resp.Status)),
+			StatusCode:    resp.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header(resp.Header).Clone(),
+			Body:          io.NopCloser(strings.NewReader(resp.Body)),
+			ContentLength: int64(len(resp.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", errNoInteraction, req.Method, req.URL)
+}
+
+// Stop saves the scrubbed interactions when recording.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	c := &Cassette{Version: Version, Interactions: make([]Interaction, len(r.cassette.Interactions))}
+	for i, interaction := range r.cassette.Interactions {
+		c.Interactions[i] = r.scrubber.interaction(interaction)
+	}
+	return c.Save(r.path)
+}
+
+// Unused returns the number of interactions not replayed yet.
+func (r *Recorder) Unused() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	unused := 0
+	for _, used := range r.used {
+		if !used {
+			unused++
+		}
+	}
+	return unused
+}
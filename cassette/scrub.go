@@ -0,0 +1,116 @@
+package cassette
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var (
+	// secretParams are the query parameters holding secrets, and their placeholders.
+	secretParams = map[string]string{
+		"key":      "redacted-key",
+		"token":    "redacted-token",
+		"login":    "redacted-login",
+		"password": "redacted-password",
+	}
+	// secretHeaders are the headers holding secrets, and their placeholders.
+	secretHeaders = map[string]string{
+		"X-Betaseries-Key":   "redacted-key",
+		"X-Betaseries-Token": "redacted-token",
+		"Authorization":      "redacted-authorization",
+	}
+)
+
+// scrubber replaces the secrets seen in the interactions by placeholders.
+type scrubber struct {
+	secrets map[string]string
+}
+
+func newScrubber() *scrubber {
+	return &scrubber{secrets: map[string]string{}}
+}
+
+func (s *scrubber) add(secret, placeholder string) {
+	if secret != "" && secret != placeholder {
+		s.secrets[secret] = placeholder
+	}
+}
+
+// learn records the secrets of a request and of the body of its response,
+// like the token returned by members/auth.
+func (s *scrubber) learn(r *http.Request, body string) {
+	for name, placeholder := range secretHeaders {
+		s.add(r.Header.Get(name), placeholder)
+	}
+	q := r.URL.Query()
+	for name, placeholder := range secretParams {
+		s.add(q.Get(name), placeholder)
+	}
+	auth := struct {
+		Token string `json:"token"`
+		User  struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	}{}
+	if json.Unmarshal([]byte(body), &auth) == nil {
+		s.add(auth.Token, secretParams["token"])
+		s.add(auth.User.Login, secretParams["login"])
+	}
+}
+
+// url returns a URL whose secret query parameters are replaced.
+func (s *scrubber) url(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	q := u.Query()
+	for name, placeholder := range secretParams {
+		if q.Has(name) {
+			q.Set(name, placeholder)
+		}
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// header returns headers whose secrets are replaced.
+func (s *scrubber) header(header map[string][]string) map[string][]string {
+	out := http.Header(header).Clone()
+	for name, placeholder := range secretHeaders {
+		if out.Get(name) != "" {
+			out.Set(name, placeholder)
+		}
+	}
+	for name, values := range out {
+		for i, value := range values {
+			if placeholder, ok := s.secrets[value]; ok {
+				out[name][i] = placeholder
+			}
+		}
+	}
+	return out
+}
+
+// body returns a JSON body whose string values equal to a secret are replaced.
+func (s *scrubber) body(body string) string {
+	for secret, placeholder := range s.secrets {
+		quoted, err := json.Marshal(secret)
+		if err != nil {
+			continue
+		}
+		body = strings.ReplaceAll(body, string(quoted), `"`+placeholder+`"`)
+	}
+	return body
+}
+
+// interaction returns a scrubbed copy of an interaction.
+func (s *scrubber) interaction(i Interaction) Interaction {
+	i.Request.URL = s.url(i.Request.URL)
+	i.Request.Header = s.header(i.Request.Header)
+	i.Response.Header = s.header(i.Response.Header)
+	i.Response.Body = s.body(i.Response.Body)
+	return i
+}
@@ -0,0 +1,68 @@
+// Command bs-graphql serves a GraphQL facade of the betaseries API on /graphql,
+// and the metrics of its API requests on /metrics, for the account given by the BS_API_KEY, BS_LOGIN and BS_PASSWORD
+// environment variables.
+//
+// Usage:
+//
+//	bs-graphql [-listen :8081] [-api https://api.betaseries.com]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/dns-gh/bs-client/bsclient"
+	"github.com/dns-gh/bs-client/graphql"
+	"github.com/dns-gh/bs-client/metrics"
+)
+
+func main() {
+	listen := flag.String("listen", ":8081", "address to listen on")
+	api := flag.String("api", "", "base URL of the betaseries API, for instance a bs-proxy")
+	flag.Parse()
+
+	key := os.Getenv("BS_API_KEY")
+	if key == "" {
+		log.Fatalln("BS_API_KEY is not set")
+	}
+	m := metrics.New()
+	options := []bsclient.ClientOption{m.Option()}
+	if *api != "" {
+		options = append(options, bsclient.WithBaseURL(*api))
+	}
+	bs, err := bsclient.NewBetaseriesClient(key, os.Getenv("BS_LOGIN"), os.Getenv("BS_PASSWORD"), options...)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	handler, err := graphql.NewHandler(bs)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/graphql", handler)
+	mux.Handle("/metrics", m.Handler())
+
+	server := &http.Server{
+		Addr:              *listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		server.Shutdown(shutdown)
+	}()
+	log.Printf("serving GraphQL on %s/graphql", *listen)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalln(err)
+	}
+}
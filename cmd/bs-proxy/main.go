@@ -0,0 +1,59 @@
+// Command bs-proxy runs a caching reverse proxy of the betaseries API,
+// sharing the API key of the BS_API_KEY environment variable.
+//
+// Usage:
+//
+//	bs-proxy [-listen :8080] [-ttl 5m] [-entries 10000] [-quota n -period 1h]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/dns-gh/bs-client/proxy"
+)
+
+func main() {
+	listen := flag.String("listen", ":8080", "address to listen on")
+	upstream := flag.String("upstream", proxy.DefaultUpstream, "URL of the betaseries API")
+	ttl := flag.Duration("ttl", proxy.DefaultTTL, "duration GET responses are cached")
+	entries := flag.Int("entries", proxy.DefaultMaxEntries, "maximum number of cached responses")
+	quota := flag.Int("quota", 0, "maximum number of upstream requests per period, 0 for no limit")
+	period := flag.Duration("period", time.Hour, "period of the quota")
+	flag.Parse()
+
+	key := os.Getenv("BS_API_KEY")
+	if key == "" {
+		log.Fatalln("BS_API_KEY is not set")
+	}
+	p := proxy.New(key)
+	p.Upstream = *upstream
+	p.TTL = *ttl
+	p.MaxEntries = *entries
+	p.Quota = *quota
+	p.QuotaPeriod = *period
+
+	server := &http.Server{
+		Addr:              *listen,
+		Handler:           p,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		server.Shutdown(shutdown)
+	}()
+	log.Printf("forwarding %s to %s", *listen, p.Upstream)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalln(err)
+	}
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dns-gh/bs-client/bsclient"
+	"github.com/dns-gh/bs-client/tui"
+)
+
+var (
+	reIMDB = regexp.MustCompile(`^tt\d+$`)
+	reCode = regexp.MustCompile(`^[sS]\d+[eE]\d+$`)
+)
+
+// parseFlags parses the flags of a command.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return err
+		}
+		return usagef("%v", err)
+	}
+	return nil
+}
+
+// parseShow returns the reference of the show given by the arguments.
+func parseShow(args []string) (bsclient.ShowRef, error) {
+	show := strings.TrimSpace(strings.Join(args, " "))
+	if show == "" {
+		return bsclient.ShowRef{}, usagef("missing show")
+	}
+	if id, err := strconv.Atoi(show); err == nil {
+		return bsclient.ShowID(id), nil
+	}
+	if reIMDB.MatchString(show) {
+		return bsclient.ShowIMDB(show), nil
+	}
+	for prefix, ref := range map[string]func(int) bsclient.ShowRef{
+		"tvdb:": bsclient.ShowTVDB,
+		"tmdb:": bsclient.ShowTMDB,
+	} {
+		if strings.HasPrefix(show, prefix) {
+			id, err := strconv.Atoi(strings.TrimPrefix(show, prefix))
+			if err != nil {
+				return bsclient.ShowRef{}, usagef("invalid show %q", show)
+			}
+			return ref(id), nil
+		}
+	}
+	return bsclient.ShowTitle(show), nil
+}
+
+// parseEpisode returns the reference of the episode given by a code and a show.
+func parseEpisode(args []string) (bsclient.EpisodeRef, error) {
+	if len(args) == 0 || !reCode.MatchString(args[0]) {
+		return bsclient.EpisodeRef{}, usagef("missing episode code, like S01E03")
+	}
+	show, err := parseShow(args[1:])
+	if err != nil {
+		return bsclient.EpisodeRef{}, err
+	}
+	return bsclient.EpisodeCode(show, strings.ToUpper(args[0])), nil
+}
+
+func date(episode *bsclient.Episode) string {
+	if episode.Date.IsZero() {
+		return ""
+	}
+	return episode.Date.Format("2006-01-02")
+}
+
+var showHeaders = []string{"ID", "TITLE", "YEAR", "SEASONS", "EPISODES", "NETWORK", "STATUS"}
+
+func showRow(show *bsclient.Show) []string {
+	return []string{
+		strconv.Itoa(show.ID),
+		show.Title,
+		strconv.Itoa(show.Creation),
+		strconv.Itoa(show.Seasons),
+		strconv.Itoa(show.Episodes),
+		show.Network,
+		show.Status,
+	}
+}
+
+var episodeHeaders = []string{"ID", "SHOW", "CODE", "TITLE", "DATE", "SEEN"}
+
+func episodeRow(episode *bsclient.Episode) []string {
+	return []string{
+		strconv.Itoa(episode.ID),
+		episode.Show.Title,
+		episode.Code,
+		episode.Title,
+		date(episode),
+		strconv.FormatBool(episode.User.Seen),
+	}
+}
+
+func (e *env) printShows(value interface{}, shows ...bsclient.Show) error {
+	rows := [][]string{}
+	for i := range shows {
+		rows = append(rows, showRow(&shows[i]))
+	}
+	return e.out.print(value, showHeaders, rows)
+}
+
+func (e *env) printEpisodes(value interface{}, episodes ...bsclient.Episode) error {
+	rows := [][]string{}
+	for i := range episodes {
+		rows = append(rows, episodeRow(&episodes[i]))
+	}
+	return e.out.print(value, episodeHeaders, rows)
+}
+
+func runSearch(e *env, args []string) error {
+	query := strings.TrimSpace(strings.Join(args, " "))
+	if query == "" {
+		return usagef("missing query")
+	}
+	shows, err := e.bs.ShowsSearch(query, nil)
+	if err != nil {
+		return err
+	}
+	return e.printShows(shows, shows...)
+}
+
+func runShow(e *env, args []string) error {
+	ref, err := parseShow(args)
+	if err != nil {
+		return err
+	}
+	show, err := e.bs.ShowDisplay(ref)
+	if err != nil || show == nil {
+		return err
+	}
+	return e.printShows(show, *show)
+}
+
+func showAdd(bs *bsclient.BetaSeries, show bsclient.ShowRef) (*bsclient.Show, error) {
+	return bs.ShowAdd(show, nil)
+}
+
+// runShowUpdate returns a command applying 'update' to a show.
+func runShowUpdate(update func(*bsclient.BetaSeries, bsclient.ShowRef) (*bsclient.Show, error)) func(*env, []string) error {
+	return func(e *env, args []string) error {
+		ref, err := parseShow(args)
+		if err != nil {
+			return err
+		}
+		show, err := update(e.bs, ref)
+		if err != nil {
+			return err
+		}
+		if show == nil {
+			return nil
+		}
+		return e.printShows(show, *show)
+	}
+}
+
+func runWatched(e *env, args []string) error {
+	fs := flag.NewFlagSet("watched", flag.ContinueOnError)
+	note := fs.Int("note", 0, "note of the episode, from 1 to 5")
+	bulk := fs.Bool("bulk", false, "also mark the previous episodes as watched")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	ref, err := parseEpisode(fs.Args())
+	if err != nil {
+		return err
+	}
+	opts := &bsclient.EpisodeWatchedOptions{Bulk: bulk}
+	if *note != 0 {
+		opts.Note = note
+	}
+	episode, err := e.bs.EpisodeWatched(ref, opts)
+	if err != nil {
+		return err
+	}
+	if episode == nil {
+		return nil
+	}
+	return e.printEpisodes(episode, *episode)
+}
+
+func runNext(e *env, args []string) error {
+	if len(args) > 0 {
+		ref, err := parseShow(args)
+		if err != nil {
+			return err
+		}
+		episode, err := e.bs.EpisodeNext(ref)
+		if err != nil || episode == nil {
+			return err
+		}
+		return e.printEpisodes(episode, *episode)
+	}
+	shows, err := e.bs.EpisodesList(&bsclient.EpisodesListOptions{Limit: bsclient.Int(1)})
+	if err != nil {
+		return err
+	}
+	episodes := []bsclient.Episode{}
+	for _, show := range shows {
+		episodes = append(episodes, show.Unseen...)
+	}
+	return e.printEpisodes(episodes, episodes...)
+}
+
+// monthFlag is a month which defaults to the current one when given without value.
+type monthFlag string
+
+func (m *monthFlag) String() string {
+	return string(*m)
+}
+
+func (m *monthFlag) Set(value string) error {
+	if value == "true" {
+		value = "now"
+	}
+	*m = monthFlag(value)
+	return nil
+}
+
+func (m *monthFlag) IsBoolFlag() bool {
+	return true
+}
+
+func runPlanning(e *env, args []string) error {
+	fs := flag.NewFlagSet("planning", flag.ContinueOnError)
+	month := monthFlag("")
+	fs.Var(&month, "month", "only the episodes of the month, the current one or YYYY-MM")
+	unseen := fs.Bool("unseen", false, "only the unseen episodes")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	episodes, err := e.bs.PlanningMember(&bsclient.PlanningMemberOptions{
+		Unseen: *unseen,
+		Month:  string(month),
+	})
+	if err != nil {
+		return err
+	}
+	return e.printEpisodes(episodes, episodes...)
+}
+
+func runSubtitles(e *env, args []string) error {
+	fs := flag.NewFlagSet("subtitles", flag.ContinueOnError)
+	language := fs.String("language", "", "language of the subtitles: all, vovf, vo or vf")
+	number := fs.Int("number", 0, "number of the last subtitles")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	opts := bsclient.SubtitlesOptions{Language: *language}
+	var subtitles []bsclient.Subtitle
+	var err error
+	switch {
+	case fs.NArg() == 0:
+		last := &bsclient.SubtitlesLastOptions{SubtitlesOptions: opts}
+		if *number != 0 {
+			last.Number = number
+		}
+		subtitles, err = e.bs.SubtitlesLast(last)
+	case reCode.MatchString(fs.Arg(0)):
+		var ref bsclient.EpisodeRef
+		if ref, err = parseEpisode(fs.Args()); err != nil {
+			return err
+		}
+		subtitles, err = e.bs.SubtitlesEpisode(ref, &opts)
+	default:
+		var ref bsclient.ShowRef
+		if ref, err = parseShow(fs.Args()); err != nil {
+			return err
+		}
+		subtitles, err = e.bs.SubtitlesShow(ref, &opts)
+	}
+	if err != nil {
+		return err
+	}
+	rows := [][]string{}
+	for _, subtitle := range subtitles {
+		rows = append(rows, []string{
+			strconv.Itoa(subtitle.ID),
+			subtitle.Language,
+			subtitle.Source,
+			strconv.Itoa(subtitle.Quality),
+			fmt.Sprintf("S%02dE%02d", subtitle.Episode.Season, subtitle.Episode.Episode),
+			subtitle.File,
+			subtitle.URL,
+		})
+	}
+	return e.out.print(subtitles, []string{"ID", "LANGUAGE", "SOURCE", "QUALITY", "CODE", "FILE", "URL"}, rows)
+}
+
+func runFriends(e *env, args []string) error {
+	fs := flag.NewFlagSet("friends", flag.ContinueOnError)
+	blocked := fs.Bool("blocked", false, "list the blocked members instead")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	friends, err := e.bs.FriendsList(&bsclient.FriendsListOptions{Blocked: *blocked})
+	if err != nil {
+		return err
+	}
+	rows := [][]string{}
+	for _, friend := range friends {
+		rows = append(rows, []string{strconv.Itoa(friend.ID), friend.Login, strconv.Itoa(friend.XP)})
+	}
+	return e.out.print(friends, []string{"ID", "LOGIN", "XP"}, rows)
+}
+
+func runTUI(e *env, args []string) error {
+	if len(args) > 0 {
+		return usagef("tui takes no arguments")
+	}
+	_, err := tui.NewProgram(e.ctx, e.bs).Run()
+	return err
+}
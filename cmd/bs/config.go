@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// config holds the API key and the member credentials.
+type config struct {
+	Key      string `json:"key"`
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
+
+// defaultConfigPath returns the path of the config file used if none is given.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "bs", "config.json")
+}
+
+// loadConfig reads the config file, which is optional if 'path' is empty,
+// then applies the environment variables.
+func loadConfig(path string) (*config, error) {
+	cfg := &config{}
+	explicit := path != ""
+	if !explicit {
+		path = defaultConfigPath()
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil && (explicit || !os.IsNotExist(err)) {
+			return nil, err
+		}
+		if err == nil {
+			if err := json.Unmarshal(data, cfg); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for name, field := range map[string]*string{
+		"BS_API_KEY":  &cfg.Key,
+		"BS_LOGIN":    &cfg.Login,
+		"BS_PASSWORD": &cfg.Password,
+	} {
+		if value := os.Getenv(name); value != "" {
+			*field = value
+		}
+	}
+	return cfg, nil
+}
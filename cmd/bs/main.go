@@ -0,0 +1,207 @@
+// Command bs is a command-line client for the betaseries API.
+//
+// Usage:
+//
+//	bs [-o table|json|tsv] [-config file] [-debug] <command> [arguments]
+//
+// The API key and the member credentials are read from the BS_API_KEY,
+// BS_LOGIN and BS_PASSWORD environment variables, which override the
+// "key", "login" and "password" fields of the JSON config file,
+// $XDG_CONFIG_HOME/bs/config.json by default.
+//
+// Shows are given by betaseries id, IMDB id (tt0903747), "tvdb:81189",
+// "tmdb:1396" or title. Episodes are given by code (S01E03) and show.
+//
+// Exit codes:
+//
+//	0  success
+//	1  other errors
+//	2  invalid usage or parameters, including API errors 3xxx
+//	3  nothing found
+//	4  invalid API key or credentials, API errors 1xxx and 2xxx
+//	5  other API errors
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sort"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+// Exit codes.
+const (
+	exitOK       = 0
+	exitError    = 1
+	exitUsage    = 2
+	exitNotFound = 3
+	exitAuth     = 4
+	exitAPI      = 5
+)
+
+var (
+	errNoKey         = errors.New("no API key: set BS_API_KEY or the config file")
+	errNoCredentials = errors.New("this command needs BS_LOGIN and BS_PASSWORD or the config file")
+)
+
+// usageError represents an invalid command line.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// env holds what commands need to run.
+type env struct {
+	ctx context.Context
+	bs  *bsclient.BetaSeries
+	out *printer
+}
+
+// command represents a sub-command of bs.
+type command struct {
+	args  string
+	help  string
+	login bool // the member must be identified
+	run   func(e *env, args []string) error
+}
+
+var commands = map[string]*command{
+	"search":    {"<query>", "search shows", false, runSearch},
+	"show":      {"<show>", "display a show", false, runShow},
+	"add":       {"<show>", "add a show to the account", true, runShowUpdate(showAdd)},
+	"remove":    {"<show>", "remove a show from the account", true, runShowUpdate((*bsclient.BetaSeries).ShowRemove)},
+	"archive":   {"<show>", "archive a show", true, runShowUpdate((*bsclient.BetaSeries).ShowArchive)},
+	"unarchive": {"<show>", "unarchive a show", true, runShowUpdate((*bsclient.BetaSeries).ShowNotArchive)},
+	"watched":   {"[-note n] [-bulk] <code> <show>", "mark an episode as watched", true, runWatched},
+	"next":      {"[show]", "next episodes to watch", true, runNext},
+	"planning":  {"[-month[=YYYY-MM]] [-unseen]", "planning of the member", true, runPlanning},
+	"subtitles": {"[-language l] [-number n] [[code] show]", "subtitles of an episode, a show or the last ones", false, runSubtitles},
+	"friends":   {"[-blocked]", "friends of the member", true, runFriends},
+	"tui":       {"", "interactive terminal interface", true, runTUI},
+}
+
+func usage(w io.Writer, global *flag.FlagSet) {
+	fmt.Fprintln(w, "usage: bs [options] <command> [arguments]")
+	fmt.Fprintln(w, "\noptions:")
+	global.SetOutput(w)
+	global.PrintDefaults()
+	fmt.Fprintln(w, "\ncommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s %s\n    \t%s\n", name, commands[name].args, commands[name].help)
+	}
+}
+
+// exitCode returns the exit code of an error.
+func exitCode(err error) int {
+	usage := &usageError{}
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &usage), bsclient.IsInvalid(err):
+		return exitUsage
+	case bsclient.IsNotFound(err):
+		return exitNotFound
+	case errors.Is(err, errNoKey), errors.Is(err, errNoCredentials):
+		return exitAuth
+	}
+	return apiExitCode(bsclient.ErrorCode(err))
+}
+
+// apiExitCode returns the exit code of an API error code, 0 if there is none.
+func apiExitCode(code int) int {
+	switch {
+	case code == 0:
+		return exitError
+	case code < 3000:
+		return exitAuth
+	case code < 4000:
+		return exitUsage
+	}
+	return exitAPI
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	global := flag.NewFlagSet("bs", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	format := global.String("o", formatTable, "output format: table, json or tsv")
+	configPath := global.String("config", "", "config file (default $XDG_CONFIG_HOME/bs/config.json)")
+	debug := global.Bool("debug", false, "log the API requests on the standard error")
+	global.Usage = func() { usage(stderr, global) }
+	if err := global.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if global.NArg() == 0 {
+		usage(stderr, global)
+		return exitUsage
+	}
+	cmd, ok := commands[global.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "bs: unknown command %q\n", global.Arg(0))
+		usage(stderr, global)
+		return exitUsage
+	}
+	options := []bsclient.ClientOption{}
+	if *debug {
+		handler := slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: slog.LevelDebug})
+		options = append(options, bsclient.WithLogger(slog.New(handler)))
+	}
+	out, err := newPrinter(*format, stdout)
+	if err == nil {
+		err = execute(ctx, cmd, *configPath, out, global.Args()[1:], options...)
+	}
+	if err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		fmt.Fprintf(stderr, "bs %s: %v\n", global.Arg(0), err)
+	}
+	return exitCode(err)
+}
+
+func execute(ctx context.Context, cmd *command, configPath string, out *printer, args []string,
+	options ...bsclient.ClientOption) error {
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	if cfg.Key == "" {
+		return errNoKey
+	}
+	if cmd.login && (cfg.Login == "" || cfg.Password == "") {
+		return errNoCredentials
+	}
+	bs, err := bsclient.NewBetaseriesClient(cfg.Key, cfg.Login, cfg.Password, options...)
+	if err != nil {
+		return err
+	}
+	return cmd.run(&env{ctx: ctx, bs: bs.WithContext(ctx), out: out}, args)
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dns-gh/bs-client/bsclient"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+func (s *MySuite) TestParseShow(c *C) {
+	for args, expected := range map[string]bsclient.ShowRef{
+		"481":        bsclient.ShowID(481),
+		"tt0903747":  bsclient.ShowIMDB("tt0903747"),
+		"tvdb:81189": bsclient.ShowTVDB(81189),
+		"tmdb:1396":  bsclient.ShowTMDB(1396),
+		"The Wire":   bsclient.ShowTitle("The Wire"),
+	} {
+		ref, err := parseShow([]string{args})
+		c.Assert(err, IsNil)
+		c.Assert(ref, DeepEquals, expected)
+	}
+	ref, err := parseShow([]string{"Breaking", "Bad"})
+	c.Assert(err, IsNil)
+	c.Assert(ref, DeepEquals, bsclient.ShowTitle("Breaking Bad"))
+	_, err = parseShow(nil)
+	c.Assert(exitCode(err), Equals, exitUsage)
+	_, err = parseShow([]string{"tvdb:x"})
+	c.Assert(err, ErrorMatches, `invalid show "tvdb:x"`)
+
+	episode, err := parseEpisode([]string{"s01e03", "481"})
+	c.Assert(err, IsNil)
+	c.Assert(episode, DeepEquals, bsclient.EpisodeCode(bsclient.ShowID(481), "S01E03"))
+	_, err = parseEpisode([]string{"481"})
+	c.Assert(exitCode(err), Equals, exitUsage)
+}
+
+func (s *MySuite) TestPrinter(c *C) {
+	rows := [][]string{{"1", "Breaking Bad"}, {"481", "The\tWire"}}
+	for format, expected := range map[string]string{
+		formatTable: "ID   TITLE\n1    Breaking Bad\n481  The Wire\n",
+		formatTSV:   "ID\tTITLE\n1\tBreaking Bad\n481\tThe Wire\n",
+		formatJSON:  "[\n  1,\n  481\n]\n",
+	} {
+		b := &bytes.Buffer{}
+		p, err := newPrinter(format, b)
+		c.Assert(err, IsNil)
+		c.Assert(p.print([]int{1, 481}, []string{"ID", "TITLE"}, rows), IsNil)
+		c.Assert(b.String(), Equals, expected, Commentf(format))
+	}
+	_, err := newPrinter("xml", nil)
+	c.Assert(err, ErrorMatches, `unknown output format "xml"`)
+}
+
+func (s *MySuite) TestMonthFlag(c *C) {
+	for args, expected := range map[string]string{
+		"":                 "",
+		"-month":           "now",
+		"--month=2016-03":  "2016-03",
+		"-month -unseen":   "now",
+		"-unseen -month=x": "x",
+	} {
+		fs := flag.NewFlagSet("planning", flag.ContinueOnError)
+		month := monthFlag("")
+		fs.Var(&month, "month", "")
+		fs.Bool("unseen", false, "")
+		c.Assert(parseFlags(fs, splitArgs(args)), IsNil)
+		c.Assert(string(month), Equals, expected, Commentf(args))
+	}
+}
+
+func splitArgs(args string) []string {
+	fields := []string{}
+	for _, field := range bytes.Fields([]byte(args)) {
+		fields = append(fields, string(field))
+	}
+	return fields
+}
+
+func (s *MySuite) TestConfig(c *C) {
+	path := filepath.Join(c.MkDir(), "config.json")
+	c.Assert(os.WriteFile(path, []byte(`{"key": "file", "login": "member", "password": "secret"}`), 0600), IsNil)
+	os.Setenv("BS_API_KEY", "env")
+	defer os.Unsetenv("BS_API_KEY")
+	cfg, err := loadConfig(path)
+	c.Assert(err, IsNil)
+	c.Assert(*cfg, DeepEquals, config{Key: "env", Login: "member", Password: "secret"})
+
+	_, err = loadConfig(filepath.Join(c.MkDir(), "missing.json"))
+	c.Assert(os.IsNotExist(err), Equals, true)
+}
+
+func (s *MySuite) TestExitCodes(c *C) {
+	c.Assert(exitCode(nil), Equals, exitOK)
+	c.Assert(exitCode(fmt.Errorf("network")), Equals, exitError)
+	c.Assert(exitCode(errNoKey), Equals, exitAuth)
+	_, err := (&bsclient.BetaSeries{}).ShowsList(&bsclient.ShowsListOptions{Order: "wrong"})
+	c.Assert(exitCode(err), Equals, exitUsage)
+	c.Assert(exitCode(fmt.Errorf("wrapped: %w", &usageError{})), Equals, exitUsage)
+
+	c.Assert(apiExitCode(0), Equals, exitError)
+	c.Assert(apiExitCode(1001), Equals, exitAuth)
+	c.Assert(apiExitCode(2001), Equals, exitAuth)
+	c.Assert(apiExitCode(3001), Equals, exitUsage)
+	c.Assert(apiExitCode(4001), Equals, exitAPI)
+}
+
+func (s *MySuite) TestRun(c *C) {
+	os.Setenv("BS_API_KEY", "")
+	os.Setenv("XDG_CONFIG_HOME", c.MkDir())
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	c.Assert(run(context.Background(), nil, stdout, stderr), Equals, exitUsage)
+	c.Assert(stderr.String(), Matches, "(?s)usage: bs.*search <query>.*")
+	c.Assert(run(context.Background(), []string{"unknown"}, stdout, stderr), Equals, exitUsage)
+	c.Assert(run(context.Background(), []string{"-o", "xml", "search", "x"}, stdout, stderr), Equals, exitUsage)
+	c.Assert(run(context.Background(), []string{"search", "x"}, stdout, stderr), Equals, exitAuth)
+	os.Setenv("BS_API_KEY", "key")
+	defer os.Unsetenv("BS_API_KEY")
+	c.Assert(run(context.Background(), []string{"planning"}, stdout, stderr), Equals, exitAuth)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Output formats.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatTSV   = "tsv"
+)
+
+// printer writes the results of commands in the chosen format.
+type printer struct {
+	format string
+	w      io.Writer
+}
+
+func newPrinter(format string, w io.Writer) (*printer, error) {
+	switch format {
+	case formatTable, formatJSON, formatTSV:
+		return &printer{format: format, w: w}, nil
+	}
+	return nil, usagef("unknown output format %q", format)
+}
+
+// print writes 'value' as JSON, or the rows as a table or as TSV.
+func (p *printer) print(value interface{}, headers []string, rows [][]string) error {
+	if p.format == formatJSON {
+		encoder := json.NewEncoder(p.w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+	w := p.w
+	var table *tabwriter.Writer
+	if p.format == formatTable {
+		table = tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+		w = table
+	}
+	for _, line := range append([][]string{headers}, rows...) {
+		fields := make([]string, len(line))
+		for i, field := range line {
+			// tabs and new lines would break the columns
+			fields[i] = strings.Join(strings.Fields(field), " ")
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+			return err
+		}
+	}
+	if table != nil {
+		return table.Flush()
+	}
+	return nil
+}
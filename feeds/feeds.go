@@ -0,0 +1,255 @@
+// Package feeds generates RSS 2.0 and Atom feeds of the betaseries news and subtitles
+package feeds
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+const (
+	betaseriesURL = "https://www.betaseries.com"
+	// idPrefix is the prefix of the tag URIs identifying feeds and items.
+	idPrefix = "tag:betaseries.com,2005:"
+)
+
+// Feed is a format independent feed.
+type Feed struct {
+	ID          string
+	Title       string
+	Link        string
+	Description string
+	// Updated defaults to the date of the most recent item.
+	Updated time.Time
+	Items   []Item
+}
+
+// Item is an entry of a feed.
+type Item struct {
+	// ID is a stable unique identifier, used as RSS guid and Atom id.
+	ID          string
+	Title       string
+	Link        string
+	Description string
+	Date        time.Time
+	Enclosure   *Enclosure
+}
+
+// Enclosure is a media attached to an item.
+type Enclosure struct {
+	URL    string
+	Type   string
+	Length int64
+}
+
+// imageType returns the MIME type of a picture from its URL.
+func imageType(pictureURL string) string {
+	ext := path.Ext(strings.SplitN(pictureURL, "?", 2)[0])
+	if t := mime.TypeByExtension(ext); strings.HasPrefix(t, "image/") {
+		return t
+	}
+	return "image/jpeg"
+}
+
+// NewsFeed returns the feed of the given news, with their picture as enclosure.
+func NewsFeed(news []bsclient.News) *Feed {
+	feed := &Feed{
+		ID:          idPrefix + "news",
+		Title:       "BetaSeries - News",
+		Link:        betaseriesURL + "/news",
+		Description: "Latest news about tv shows",
+	}
+	for _, n := range news {
+		item := Item{
+			ID:    idPrefix + "news/" + n.ID,
+			Title: n.Title,
+			Link:  n.URL,
+			Date:  n.Date,
+		}
+		if n.PictureURL != "" {
+			item.Enclosure = &Enclosure{URL: n.PictureURL, Type: imageType(n.PictureURL)}
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	return feed
+}
+
+// SubtitlesFeed returns the feed of the given subtitles, linking to their download URL.
+func SubtitlesFeed(title string, subtitles []bsclient.Subtitle) *Feed {
+	feed := &Feed{
+		ID:          idPrefix + "subtitles",
+		Title:       "BetaSeries - " + title,
+		Link:        betaseriesURL + "/sous-titres",
+		Description: "Latest subtitles",
+	}
+	for _, s := range subtitles {
+		code := fmt.Sprintf("S%02dE%02d", s.Episode.Season, s.Episode.Episode)
+		description := fmt.Sprintf("%s subtitles from %s, quality %d/5.", strings.ToUpper(s.Language), s.Source, s.Quality)
+		if len(s.Content) > 0 {
+			files := make([]string, 0, len(s.Content))
+			for _, f := range s.Content {
+				files = append(files, string(f))
+			}
+			description += " Files: " + strings.Join(files, ", ")
+		}
+		feed.Items = append(feed.Items, Item{
+			ID:          fmt.Sprintf("%ssubtitles/%d", idPrefix, s.ID),
+			Title:       fmt.Sprintf("%s - %s (%s)", code, s.File, strings.ToUpper(s.Language)),
+			Link:        s.URL,
+			Description: description,
+			Date:        s.Date,
+		})
+	}
+	return feed
+}
+
+// updated returns the date of the feed, or of its most recent item.
+func (f *Feed) updated() time.Time {
+	updated := f.Updated
+	for _, item := range f.Items {
+		if item.Date.After(updated) {
+			updated = item.Date
+		}
+	}
+	return updated
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length int64  `xml:"length,attr"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link,omitempty"`
+	Description string        `xml:"description,omitempty"`
+	GUID        rssGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate,omitempty"`
+	Enclosure   *rssEnclosure `xml:"enclosure"`
+}
+
+type rss struct {
+	XMLName       xml.Name  `xml:"rss"`
+	Version       string    `xml:"version,attr"`
+	Title         string    `xml:"channel>title"`
+	Link          string    `xml:"channel>link"`
+	Description   string    `xml:"channel>description"`
+	LastBuildDate string    `xml:"channel>lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"channel>item"`
+}
+
+func rssDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC1123Z)
+}
+
+// WriteRSS writes the feed as a RSS 2.0 document.
+func (f *Feed) WriteRSS(w io.Writer) error {
+	doc := rss{
+		Version:       "2.0",
+		Title:         f.Title,
+		Link:          f.Link,
+		Description:   f.Description,
+		LastBuildDate: rssDate(f.updated()),
+	}
+	for _, item := range f.Items {
+		ri := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Description,
+			GUID:        rssGUID{Value: item.ID},
+			PubDate:     rssDate(item.Date),
+		}
+		if item.Enclosure != nil {
+			ri.Enclosure = &rssEnclosure{URL: item.Enclosure.URL, Type: item.Enclosure.Type, Length: item.Enclosure.Length}
+		}
+		doc.Items = append(doc.Items, ri)
+	}
+	return writeXML(w, doc)
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID      string     `xml:"id"`
+	Title   string     `xml:"title"`
+	Updated string     `xml:"updated"`
+	Links   []atomLink `xml:"link"`
+	Summary string     `xml:"summary,omitempty"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  string      `xml:"author>name"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+func atomDate(t time.Time) string {
+	if t.IsZero() {
+		// updated is mandatory
+		t = time.Unix(0, 0)
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// WriteAtom writes the feed as an Atom document.
+func (f *Feed) WriteAtom(w io.Writer) error {
+	doc := atomFeed{
+		ID:      f.ID,
+		Title:   f.Title,
+		Updated: atomDate(f.updated()),
+		Author:  "BetaSeries",
+		Links:   []atomLink{{Href: f.Link, Rel: "alternate"}},
+	}
+	for _, item := range f.Items {
+		entry := atomEntry{
+			ID:      item.ID,
+			Title:   item.Title,
+			Updated: atomDate(item.Date),
+			Summary: item.Description,
+		}
+		if item.Link != "" {
+			entry.Links = append(entry.Links, atomLink{Href: item.Link, Rel: "alternate"})
+		}
+		if item.Enclosure != nil {
+			entry.Links = append(entry.Links, atomLink{Href: item.Enclosure.URL, Rel: "enclosure", Type: item.Enclosure.Type})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return writeXML(w, doc)
+}
+
+func writeXML(w io.Writer, doc interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,91 @@
+package feeds
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dns-gh/bs-client/bsclient"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+func makeNews(c *C) []bsclient.News {
+	news := []bsclient.News{}
+	c.Assert(json.Unmarshal([]byte(`[
+		{"id": "1", "title": "Season 2 & more", "url": "https://www.betaseries.com/news/1",
+		 "picture_url": "https://img.betaseries.com/news/1.png", "date": "2016-01-02 10:00:00"},
+		{"id": "2", "title": "No picture", "url": "https://www.betaseries.com/news/2",
+		 "date": "2016-01-01 10:00:00"}
+	]`), &news), IsNil)
+	return news
+}
+
+func (s *MySuite) TestNewsRSS(c *C) {
+	news := makeNews(c)
+	buf := &bytes.Buffer{}
+	c.Assert(NewsFeed(news).WriteRSS(buf), IsNil)
+	c.Assert(strings.HasPrefix(buf.String(), xml.Header), Equals, true)
+
+	doc := rss{}
+	c.Assert(xml.Unmarshal(buf.Bytes(), &doc), IsNil)
+	c.Assert(doc.Version, Equals, "2.0")
+	c.Assert(doc.LastBuildDate, Equals, news[0].Date.Format(time.RFC1123Z))
+	c.Assert(doc.Items, HasLen, 2)
+	c.Assert(doc.Items[0].Title, Equals, "Season 2 & more")
+	c.Assert(doc.Items[0].GUID, DeepEquals, rssGUID{Value: "tag:betaseries.com,2005:news/1"})
+	c.Assert(doc.Items[0].Enclosure, DeepEquals, &rssEnclosure{
+		URL:  "https://img.betaseries.com/news/1.png",
+		Type: "image/png",
+	})
+	c.Assert(doc.Items[1].Enclosure, IsNil)
+}
+
+func (s *MySuite) TestSubtitlesAtom(c *C) {
+	subtitles := []bsclient.Subtitle{}
+	c.Assert(json.Unmarshal([]byte(`[{"id": 12, "language": "vf", "source": "addic7ed", "quality": 4,
+		"file": "Show.S01E02.srt", "url": "https://www.betaseries.com/srt/12",
+		"episode": {"show_id": 1, "episode_id": 2, "season": 1, "episode": 2},
+		"date": "2016-01-02 10:00:00"}]`), &subtitles), IsNil)
+
+	buf := &bytes.Buffer{}
+	c.Assert(SubtitlesFeed("Latest subtitles", subtitles).WriteAtom(buf), IsNil)
+	doc := atomFeed{}
+	c.Assert(xml.Unmarshal(buf.Bytes(), &doc), IsNil)
+	c.Assert(doc.XMLName.Space, Equals, "http://www.w3.org/2005/Atom")
+	c.Assert(doc.Updated, Equals, subtitles[0].Date.UTC().Format(time.RFC3339))
+	c.Assert(doc.Entries, HasLen, 1)
+	c.Assert(doc.Entries[0].ID, Equals, "tag:betaseries.com,2005:subtitles/12")
+	c.Assert(doc.Entries[0].Title, Equals, "S01E02 - Show.S01E02.srt (VF)")
+	c.Assert(doc.Entries[0].Links, DeepEquals, []atomLink{{Href: "https://www.betaseries.com/srt/12", Rel: "alternate"}})
+}
+
+func (s *MySuite) TestHandlerErrors(c *C) {
+	h := NewHandler(&bsclient.BetaSeries{})
+	for _, path := range []string{"/feeds/news.json", "/feeds/unknown.rss"} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		c.Assert(w.Code, Equals, http.StatusNotFound, Commentf(path))
+	}
+
+	for _, path := range []string{"/feeds/subtitles.rss?show=abc", "/feeds/subtitles.rss?language=xx",
+		"/feeds/subtitles.atom?show=1&language=xx"} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		c.Assert(w.Code, Equals, http.StatusBadRequest, Commentf(path))
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/feeds/news.rss", nil))
+	c.Assert(w.Code, Equals, http.StatusMethodNotAllowed)
+}
@@ -0,0 +1,139 @@
+package feeds
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+// DefaultMaxAge is the duration feeds may be cached by clients when not set.
+const DefaultMaxAge = 15 * time.Minute
+
+var (
+	errUnknownFeed   = errors.New("unknown feed")
+	errUnknownFormat = errors.New("unknown format, use .rss or .atom")
+	errInvalidShow   = errors.New("invalid show id")
+)
+
+// Handler serves the news and subtitles feeds. The last element of the
+// path selects the feed and its format: 'news.rss', 'news.atom',
+// 'subtitles.rss' or 'subtitles.atom'. The subtitles feed accepts the
+// 'show' (betaseries show id) and 'language' (vo, vf, vovf, all) parameters.
+type Handler struct {
+	Client *bsclient.BetaSeries
+	// Number is the number of items of the feeds (API default if 0).
+	Number int
+	// MaxAge is the duration feeds may be cached, DefaultMaxAge if 0.
+	MaxAge time.Duration
+}
+
+// NewHandler creates a feeds handler.
+func NewHandler(bs *bsclient.BetaSeries) *Handler {
+	return &Handler{Client: bs}
+}
+
+func (h *Handler) feed(r *http.Request, name string) (*Feed, error) {
+	client := h.Client.WithContext(r.Context())
+	var number *int
+	if h.Number > 0 {
+		number = bsclient.Int(h.Number)
+	}
+	switch name {
+	case "news":
+		news, err := client.NewsLast(&bsclient.NewsLastOptions{Number: number})
+		if err != nil && !bsclient.IsNotFound(err) {
+			return nil, err
+		}
+		return NewsFeed(news), nil
+	case "subtitles":
+		opts := bsclient.SubtitlesOptions{Language: r.URL.Query().Get("language")}
+		show := r.URL.Query().Get("show")
+		if show == "" {
+			subtitles, err := client.SubtitlesLast(&bsclient.SubtitlesLastOptions{SubtitlesOptions: opts, Number: number})
+			if err != nil && !bsclient.IsNotFound(err) {
+				return nil, err
+			}
+			return SubtitlesFeed("Latest subtitles", subtitles), nil
+		}
+		id, err := strconv.Atoi(show)
+		if err != nil || id <= 0 {
+			return nil, errInvalidShow
+		}
+		subtitles, err := client.SubtitlesShow(bsclient.ShowID(id), &opts)
+		if err != nil && !bsclient.IsNotFound(err) {
+			return nil, err
+		}
+		feed := SubtitlesFeed("Subtitles of show "+show, subtitles)
+		feed.ID += "/show/" + show
+		return feed, nil
+	}
+	return nil, errUnknownFeed
+}
+
+// ServeHTTP serves a feed with caching headers, answering conditional
+// requests with 304 Not Modified.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	base := path.Base(r.URL.Path)
+	ext := path.Ext(base)
+	var contentType string
+	switch ext {
+	case ".rss":
+		contentType = "application/rss+xml; charset=utf-8"
+	case ".atom":
+		contentType = "application/atom+xml; charset=utf-8"
+	default:
+		http.Error(w, errUnknownFormat.Error(), http.StatusNotFound)
+		return
+	}
+	feed, err := h.feed(r, strings.TrimSuffix(base, ext))
+	switch {
+	case err == nil:
+	case err == errUnknownFeed:
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case err == errInvalidShow || bsclient.IsInvalid(err):
+		// invalid query parameters, like an unknown subtitle language
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	default:
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	if ext == ".rss" {
+		err = feed.WriteRSS(buf)
+	} else {
+		err = feed.WriteAtom(buf)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	maxAge := h.MaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+	sum := sha1.Sum(buf.Bytes())
+	header := w.Header()
+	header.Set("Content-Type", contentType)
+	header.Set("Cache-Control", "public, max-age="+strconv.Itoa(int(maxAge/time.Second)))
+	header.Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+	header.Set("Expires", time.Now().Add(maxAge).UTC().Format(http.TimeFormat))
+	// ServeContent handles If-None-Match, If-Modified-Since and HEAD requests
+	http.ServeContent(w, r, base, feed.updated(), bytes.NewReader(buf.Bytes()))
+}
@@ -0,0 +1,113 @@
+// Package graphql serves a GraphQL schema over the betaseries client, so that
+// a show, its characters, similar shows, next episode and subtitles can be
+// fetched in one round-trip. The API calls of a request are cached and the
+// concurrent calls for the same value are shared. They are not batched into
+// requests for several ids: each distinct value costs one API call.
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/dns-gh/bs-client/bsclient"
+	gographql "github.com/graph-gophers/graphql-go"
+)
+
+var (
+	errNoShowArgument    = errors.New("one of id, tvdb, imdb, tmdb or title is required")
+	errNoEpisodeArgument = errors.New("one of id or tvdb is required")
+	errNoQuery           = errors.New("missing query")
+	errNotJSON           = errors.New("content type must be application/json")
+	errLoadPanicked      = errors.New("load interrupted by a panic")
+)
+
+// MaxBodySize is the maximum size of the body of a POST request.
+const MaxBodySize = 1 << 20
+
+// Handler serves GraphQL requests, sent as GET parameters or as a JSON POST body.
+// Mutations are only accepted in POST requests: a GET request, which any
+// cross-site link can send, only runs queries.
+type Handler struct {
+	bs       *bsclient.BetaSeries
+	schema   *gographql.Schema
+	readOnly *gographql.Schema
+}
+
+// NewHandler returns a handler whose resolvers use the given client.
+func NewHandler(bs *bsclient.BetaSeries) (*Handler, error) {
+	schema, err := gographql.ParseSchema(Schema, &resolver{bs: bs},
+		gographql.MaxParallelism(10))
+	if err != nil {
+		return nil, err
+	}
+	readOnly, err := gographql.ParseSchema(strings.Replace(Schema, "mutation: Mutation", "", 1),
+		&resolver{bs: bs}, gographql.MaxParallelism(10))
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{bs: bs, schema: schema, readOnly: readOnly}, nil
+}
+
+// Request represents a GraphQL request.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// parseRequest reads a request from the query parameters or the body.
+func parseRequest(r *http.Request) (*Request, error) {
+	req := &Request{}
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		req.Query = q.Get("query")
+		req.OperationName = q.Get("operationName")
+		if variables := q.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				return nil, err
+			}
+		}
+	case http.MethodPost:
+		// a JSON body can't be sent cross-site without a preflight request
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || mediaType != "application/json" {
+			return nil, errNotJSON
+		}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			return nil, err
+		}
+	}
+	if req.Query == "" {
+		return nil, errNoQuery
+	}
+	return req, nil
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, MaxBodySize)
+	req, err := parseRequest(r)
+	if err == errNotJSON {
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	schema := h.schema
+	if r.Method == http.MethodGet {
+		schema = h.readOnly
+	}
+	ctx := withLoaders(r.Context(), h.bs)
+	resp := schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,213 @@
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/dns-gh/bs-client/bsclient"
+	"github.com/dns-gh/bs-client/internal/apitest"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+// fakeResponses answers a few endpoints of the API.
+var fakeResponses = map[string]string{
+	"GET /shows/display":     `{"show": {"id": 1, "title": "Breaking Bad", "genres": ["Drama"], "notes": {"mean": 4.5}}}`,
+	"GET /shows/characters":  `{"characters": [{"id": 7, "name": "Walter White", "actor": "Bryan Cranston"}]}`,
+	"GET /shows/similars":    `{"similars": [{"id": 3, "show_id": 2, "show_title": "Ozark"}]}`,
+	"GET /shows/videos":      `{"videos": []}`,
+	"GET /episodes/display":  `{"episode": {"id": 10, "code": "S01E02", "show": {"id": 1}}}`,
+	"GET /episodes/next":     `{"episode": {"id": 10, "code": "S01E02", "date": "2008-01-27", "show": {"id": 1}}}`,
+	"GET /subtitles/show":    `{"subtitles": [{"id": 5, "language": "VF", "episode": {"episode_id": 10}}]}`,
+	"POST /episodes/watched": `{"episode": {"id": 10, "code": "S01E02", "show": {"id": 1}, "user": {"seen": true}}}`,
+}
+
+// newFakeAPI starts a fake API knowing the shows 1 and 2.
+func newFakeAPI() *apitest.Server {
+	api := apitest.NewServer(fakeResponses)
+	api.Handle("GET /shows/display", func(w http.ResponseWriter, r *http.Request) {
+		if id := r.URL.Query().Get("id"); id != "1" && id != "2" {
+			apitest.WriteError(w, http.StatusNotFound, 4001, "Show not found.")
+			return
+		}
+		w.Write([]byte(fakeResponses["GET /shows/display"]))
+	})
+	return api
+}
+
+// query sends a query to the handler and returns the data and the errors.
+func query(c *C, h http.Handler, q string) (map[string]interface{}, []interface{}) {
+	body, err := json.Marshal(Request{Query: q})
+	c.Assert(err, IsNil)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json; charset=utf-8")
+	h.ServeHTTP(w, r)
+	c.Assert(w.Code, Equals, http.StatusOK)
+	resp := struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []interface{}          `json:"errors"`
+	}{}
+	c.Assert(json.Unmarshal(w.Body.Bytes(), &resp), IsNil)
+	return resp.Data, resp.Errors
+}
+
+func newTestHandler(c *C) (*apitest.Server, *Handler, func()) {
+	api := newFakeAPI()
+	bs, err := bsclient.NewBetaseriesClient("key", "", "", bsclient.WithBaseURL(api.URL))
+	c.Assert(err, IsNil)
+	h, err := NewHandler(bs)
+	c.Assert(err, IsNil)
+	return api, h, func() {
+		c.Check(api.Unexpected(), HasLen, 0)
+		api.Close()
+	}
+}
+
+func (s *MySuite) TestShowQuery(c *C) {
+	api, h, stop := newTestHandler(c)
+	defer stop()
+	data, errs := query(c, h, `{
+		a: show(id: 1) {
+			title genres note
+			characters { name actor }
+			similars { id show { title } }
+			videos { id }
+			nextEpisode { code date show { title } }
+			subtitles { language episode { code } }
+		}
+		b: show(id: 1) { characters { name } subtitles { episode { code } } }
+	}`)
+	c.Assert(errs, HasLen, 0)
+	a := data["a"].(map[string]interface{})
+	c.Assert(a["title"], Equals, "Breaking Bad")
+	c.Assert(a["genres"], DeepEquals, []interface{}{"Drama"})
+	c.Assert(a["note"], Equals, 4.5)
+	c.Assert(a["characters"], DeepEquals, []interface{}{
+		map[string]interface{}{"name": "Walter White", "actor": "Bryan Cranston"},
+	})
+	c.Assert(a["similars"], DeepEquals, []interface{}{
+		map[string]interface{}{"id": 3.0, "show": map[string]interface{}{"title": "Breaking Bad"}},
+	})
+	c.Assert(a["videos"], DeepEquals, []interface{}{})
+	c.Assert(a["nextEpisode"], DeepEquals, map[string]interface{}{
+		"code": "S01E02", "date": "2008-01-27", "show": map[string]interface{}{"title": "Breaking Bad"},
+	})
+	c.Assert(a["subtitles"], DeepEquals, []interface{}{
+		map[string]interface{}{"language": "VF", "episode": map[string]interface{}{"code": "S01E02"}},
+	})
+
+	// every value is requested once, whatever the number of fields using it
+	c.Assert(api.Count("GET /shows/display"), Equals, 2) // shows 1 and 2
+	c.Assert(api.Count("GET /shows/characters"), Equals, 1)
+	c.Assert(api.Count("GET /episodes/next"), Equals, 1)
+	c.Assert(api.Count("GET /episodes/display"), Equals, 1)
+	c.Assert(api.Count("GET /subtitles/show"), Equals, 1)
+
+	// the cache only lasts for a request
+	_, errs = query(c, h, `{ show(id: 1) { title } }`)
+	c.Assert(errs, HasLen, 0)
+	c.Assert(api.Count("GET /shows/display"), Equals, 3)
+}
+
+func (s *MySuite) TestShowErrors(c *C) {
+	_, h, stop := newTestHandler(c)
+	defer stop()
+	data, errs := query(c, h, `{ show { title } }`)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(data["show"], IsNil)
+
+	data, errs = query(c, h, `{ show(id: 9) { title } }`)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(data["show"], IsNil)
+}
+
+func (s *MySuite) TestMutation(c *C) {
+	api, h, stop := newTestHandler(c)
+	defer stop()
+	data, errs := query(c, h, `mutation {
+		markWatched(episode: 10, note: 4) { code seen show { title } }
+	}`)
+	c.Assert(errs, HasLen, 0)
+	c.Assert(data["markWatched"], DeepEquals, map[string]interface{}{
+		"code": "S01E02", "seen": true, "show": map[string]interface{}{"title": "Breaking Bad"},
+	})
+	c.Assert(api.Count("POST /episodes/watched"), Equals, 1)
+	// only the evicted episode changes
+	c.Assert(api.Requests(), DeepEquals, []string{
+		"POST /episodes/watched?bulk=false&id=10&note=4",
+		"GET /shows/display?id=1",
+	})
+
+	_, errs = query(c, h, `mutation { markWatched(episode: 10, note: 6) { seen } }`)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(api.Count("POST /episodes/watched"), Equals, 1)
+}
+
+func (s *MySuite) TestHandler(c *C) {
+	_, h, stop := newTestHandler(c)
+	defer stop()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape(`{ show(id: 1) { title } }`), nil))
+	c.Assert(w.Code, Equals, http.StatusOK)
+	c.Assert(w.Body.String(), Equals, `{"data":{"show":{"title":"Breaking Bad"}}}`+"\n")
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql", nil))
+	c.Assert(w.Code, Equals, http.StatusBadRequest)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/graphql", nil))
+	c.Assert(w.Code, Equals, http.StatusMethodNotAllowed)
+}
+
+func (s *MySuite) TestHandlerMutationMethods(c *C) {
+	api, h, stop := newTestHandler(c)
+	defer stop()
+	mutation := `mutation { markWatched(episode: 10) { seen } }`
+
+	// a link can't change the account
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape(mutation), nil))
+	c.Assert(w.Code, Equals, http.StatusOK)
+	c.Assert(w.Body.String(), Equals, `{"errors":[{"message":"no mutations are offered by the schema"}]}`+"\n")
+
+	// neither can a form
+	body, err := json.Marshal(Request{Query: mutation})
+	c.Assert(err, IsNil)
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "text/plain")
+	h.ServeHTTP(w, r)
+	c.Assert(w.Code, Equals, http.StatusUnsupportedMediaType)
+	c.Assert(api.Count("POST /episodes/watched"), Equals, 0)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query": "`+strings.Repeat(" ", MaxBodySize)+`{ show(id: 1) { title } }"}`))
+	r.Header.Set("Content-Type", "application/json")
+	h.ServeHTTP(w, r)
+	c.Assert(w.Code, Equals, http.StatusBadRequest)
+	c.Assert(api.Count("GET /shows/display"), Equals, 0)
+}
+
+func (s *MySuite) TestLoaderPanic(c *C) {
+	l := newLoader(func(key int) (int, error) {
+		panic("fetch")
+	})
+	func() {
+		defer func() { c.Assert(recover(), Equals, "fetch") }()
+		l.load(1)
+	}()
+	// later loads of the key do not wait forever
+	_, err := l.load(1)
+	c.Assert(err, Equals, errLoadPanicked)
+}
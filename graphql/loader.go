@@ -0,0 +1,163 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+// result is the result of a load, shared by the resolvers asking for the same key.
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// loader loads values once per key: concurrent loads of a key wait for the
+// first one, and later loads return its result. Not-found errors give zero values.
+type loader[K comparable, V any] struct {
+	mutex   sync.Mutex
+	fetch   func(key K) (V, error)
+	results map[K]*result[V]
+}
+
+func newLoader[K comparable, V any](fetch func(key K) (V, error)) *loader[K, V] {
+	return &loader[K, V]{fetch: fetch, results: map[K]*result[V]{}}
+}
+
+func (l *loader[K, V]) load(key K) (V, error) {
+	l.mutex.Lock()
+	r, ok := l.results[key]
+	if !ok {
+		r = &result[V]{done: make(chan struct{})}
+		l.results[key] = r
+	}
+	l.mutex.Unlock()
+	if ok {
+		<-r.done
+		return r.value, r.err
+	}
+	// the waiters are released even if fetch panics
+	defer close(r.done)
+	r.err = errLoadPanicked
+	value, err := l.fetch(key)
+	if bsclient.IsNotFound(err) {
+		err = nil
+	}
+	r.value, r.err = value, err
+	return value, err
+}
+
+// prime sets the value of a key, unless it is already loaded.
+func (l *loader[K, V]) prime(key K, value V) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if _, ok := l.results[key]; !ok {
+		r := &result[V]{done: make(chan struct{}), value: value}
+		close(r.done)
+		l.results[key] = r
+	}
+}
+
+// forget removes the result of a key, after a mutation.
+func (l *loader[K, V]) forget(key K) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	delete(l.results, key)
+}
+
+// subtitlesKey identifies the subtitles of a show or an episode in a language.
+type subtitlesKey struct {
+	id       int
+	language string
+}
+
+// loaders cache the API calls of a request.
+type loaders struct {
+	shows            *loader[int, *bsclient.Show]
+	episodes         *loader[int, *bsclient.Episode]
+	showEpisodes     *loader[int, []bsclient.Episode]
+	nextEpisodes     *loader[int, *bsclient.Episode]
+	latestEpisodes   *loader[int, *bsclient.Episode]
+	characters       *loader[int, []bsclient.Character]
+	similars         *loader[int, []bsclient.Similar]
+	videos           *loader[int, []bsclient.Video]
+	showSubtitles    *loader[subtitlesKey, []bsclient.Subtitle]
+	episodeSubtitles *loader[subtitlesKey, []bsclient.Subtitle]
+	members          *loader[int, *bsclient.Member]
+	friends          *loader[int, []bsclient.Member]
+}
+
+func subtitlesOptions(language string) *bsclient.SubtitlesOptions {
+	return &bsclient.SubtitlesOptions{Language: language}
+}
+
+func newLoaders(bs *bsclient.BetaSeries) *loaders {
+	return &loaders{
+		shows: newLoader(func(id int) (*bsclient.Show, error) {
+			return bs.ShowDisplay(bsclient.ShowID(id))
+		}),
+		episodes: newLoader(func(id int) (*bsclient.Episode, error) {
+			return bs.EpisodeDisplay(bsclient.EpisodeID(id), nil)
+		}),
+		showEpisodes: newLoader(func(id int) ([]bsclient.Episode, error) {
+			return bs.ShowsEpisodes(bsclient.ShowID(id), nil)
+		}),
+		nextEpisodes: newLoader(func(id int) (*bsclient.Episode, error) {
+			return bs.EpisodeNext(bsclient.ShowID(id))
+		}),
+		latestEpisodes: newLoader(func(id int) (*bsclient.Episode, error) {
+			return bs.EpisodeLatest(bsclient.ShowID(id))
+		}),
+		characters: newLoader(func(id int) ([]bsclient.Character, error) {
+			return bs.ShowsCharacters(bsclient.ShowID(id))
+		}),
+		similars: newLoader(func(id int) ([]bsclient.Similar, error) {
+			return bs.ShowsSimilars(bsclient.ShowID(id), nil)
+		}),
+		videos: newLoader(func(id int) ([]bsclient.Video, error) {
+			return bs.ShowsVideos(bsclient.ShowID(id))
+		}),
+		showSubtitles: newLoader(func(key subtitlesKey) ([]bsclient.Subtitle, error) {
+			return bs.SubtitlesShow(bsclient.ShowID(key.id), subtitlesOptions(key.language))
+		}),
+		episodeSubtitles: newLoader(func(key subtitlesKey) ([]bsclient.Subtitle, error) {
+			return bs.SubtitlesEpisode(bsclient.EpisodeID(key.id), subtitlesOptions(key.language))
+		}),
+		members: newLoader(func(id int) (*bsclient.Member, error) {
+			opts := &bsclient.MembersInfosOptions{Only: "shows"}
+			if id > 0 {
+				opts.ID = bsclient.Int(id)
+			}
+			return bs.MembersInfos(opts)
+		}),
+		friends: newLoader(func(id int) ([]bsclient.Member, error) {
+			opts := &bsclient.FriendsListOptions{}
+			if id > 0 {
+				opts.ID = bsclient.Int(id)
+			}
+			return bs.FriendsList(opts)
+		}),
+	}
+}
+
+type loadersKey struct{}
+
+// withLoaders returns a context holding new loaders of a request.
+func withLoaders(ctx context.Context, bs *bsclient.BetaSeries) context.Context {
+	return context.WithValue(ctx, loadersKey{}, newLoaders(bs.WithContext(ctx)))
+}
+
+func loadersOf(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// forgetShow removes what depends on the state of a show for the member.
+func (l *loaders) forgetShow(id int) {
+	l.shows.forget(id)
+	l.showEpisodes.forget(id)
+	l.nextEpisodes.forget(id)
+	l.latestEpisodes.forget(id)
+	l.members.forget(0)
+}
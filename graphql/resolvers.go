@@ -0,0 +1,449 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+const dateLayout = "2006-01-02"
+
+// formatDate returns a date as YYYY-MM-DD, or an empty string if unset.
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateLayout)
+}
+
+// resolver resolves the queries and the mutations.
+type resolver struct {
+	bs *bsclient.BetaSeries
+}
+
+type showArgs struct {
+	ID    *int32
+	TVDB  *int32
+	IMDB  *string
+	TMDB  *int32
+	Title *string
+}
+
+// ref returns the show reference of the arguments, the first one set.
+func (a showArgs) ref() bsclient.ShowRef {
+	switch {
+	case a.ID != nil:
+		return bsclient.ShowID(int(*a.ID))
+	case a.TVDB != nil:
+		return bsclient.ShowTVDB(int(*a.TVDB))
+	case a.IMDB != nil:
+		return bsclient.ShowIMDB(*a.IMDB)
+	case a.TMDB != nil:
+		return bsclient.ShowTMDB(int(*a.TMDB))
+	case a.Title != nil:
+		return bsclient.ShowTitle(*a.Title)
+	}
+	return bsclient.ShowRef{}
+}
+
+func (r *resolver) Show(ctx context.Context, args showArgs) (*showResolver, error) {
+	l := loadersOf(ctx)
+	if args.ID != nil {
+		return newShowResolver(l.shows.load(int(*args.ID)))
+	}
+	ref := args.ref()
+	if ref.IsZero() {
+		return nil, errNoShowArgument
+	}
+	show, err := r.bs.WithContext(ctx).ShowDisplay(ref)
+	if bsclient.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	l.shows.prime(show.ID, show)
+	return &showResolver{show: show}, nil
+}
+
+func (r *resolver) Episode(ctx context.Context, args struct {
+	ID   *int32
+	TVDB *int32
+}) (*episodeResolver, error) {
+	l := loadersOf(ctx)
+	switch {
+	case args.ID != nil:
+		return newEpisodeResolver(l.episodes.load(int(*args.ID)))
+	case args.TVDB != nil:
+		episode, err := r.bs.WithContext(ctx).EpisodeDisplay(bsclient.EpisodeTVDB(int(*args.TVDB)), nil)
+		if bsclient.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		l.episodes.prime(episode.ID, episode)
+		return &episodeResolver{episode: episode}, nil
+	}
+	return nil, errNoEpisodeArgument
+}
+
+func (r *resolver) SearchShows(ctx context.Context, args struct{ Query string }) ([]*showResolver, error) {
+	shows, err := r.bs.WithContext(ctx).ShowsSearch(args.Query, nil)
+	if bsclient.IsNotFound(err) {
+		return []*showResolver{}, nil
+	}
+	return showResolvers(shows), err
+}
+
+func (r *resolver) Member(ctx context.Context, args struct{ ID *int32 }) (*memberResolver, error) {
+	id := 0
+	if args.ID != nil {
+		id = int(*args.ID)
+	}
+	member, err := loadersOf(ctx).members.load(id)
+	if err != nil || member == nil {
+		return nil, err
+	}
+	return &memberResolver{member: member}, nil
+}
+
+func (r *resolver) Planning(ctx context.Context, args struct {
+	Month  *string
+	Unseen *bool
+}) ([]*episodeResolver, error) {
+	opts := &bsclient.PlanningMemberOptions{}
+	if args.Month != nil {
+		opts.Month = *args.Month
+	}
+	if args.Unseen != nil {
+		opts.Unseen = *args.Unseen
+	}
+	episodes, err := r.bs.WithContext(ctx).PlanningMember(opts)
+	if bsclient.IsNotFound(err) {
+		return []*episodeResolver{}, nil
+	}
+	return episodeResolvers(episodes), err
+}
+
+// updateEpisode applies a mutation to an episode and forgets what
+// the request knew about it and its show.
+func (r *resolver) updateEpisode(ctx context.Context, id int32,
+	update func(bs *bsclient.BetaSeries, ref bsclient.EpisodeRef) (*bsclient.Episode, error)) (*episodeResolver, error) {
+	episode, err := update(r.bs.WithContext(ctx), bsclient.EpisodeID(int(id)))
+	if err != nil {
+		return nil, err
+	}
+	l := loadersOf(ctx)
+	l.episodes.forget(episode.ID)
+	l.episodes.prime(episode.ID, episode)
+	l.forgetShow(episode.Show.ID)
+	return &episodeResolver{episode: episode}, nil
+}
+
+func (r *resolver) MarkWatched(ctx context.Context, args struct {
+	Episode int32
+	Note    *int32
+}) (*episodeResolver, error) {
+	// only the given episode changes, which is the one evicted from the loaders
+	opts := &bsclient.EpisodeWatchedOptions{Bulk: bsclient.Bool(false)}
+	if args.Note != nil {
+		opts.Note = bsclient.Int(int(*args.Note))
+	}
+	return r.updateEpisode(ctx, args.Episode, func(bs *bsclient.BetaSeries, ref bsclient.EpisodeRef) (*bsclient.Episode, error) {
+		return bs.EpisodeWatched(ref, opts)
+	})
+}
+
+func (r *resolver) UnmarkWatched(ctx context.Context, args struct{ Episode int32 }) (*episodeResolver, error) {
+	return r.updateEpisode(ctx, args.Episode, (*bsclient.BetaSeries).EpisodeNotWatched)
+}
+
+func (r *resolver) MarkDownloaded(ctx context.Context, args struct{ Episode int32 }) (*episodeResolver, error) {
+	return r.updateEpisode(ctx, args.Episode, (*bsclient.BetaSeries).EpisodeDownloaded)
+}
+
+func (r *resolver) UnmarkDownloaded(ctx context.Context, args struct{ Episode int32 }) (*episodeResolver, error) {
+	return r.updateEpisode(ctx, args.Episode, (*bsclient.BetaSeries).EpisodeNotDownloaded)
+}
+
+func (r *resolver) RateEpisode(ctx context.Context, args struct {
+	Episode int32
+	Note    int32
+}) (*episodeResolver, error) {
+	return r.updateEpisode(ctx, args.Episode, func(bs *bsclient.BetaSeries, ref bsclient.EpisodeRef) (*bsclient.Episode, error) {
+		return bs.EpisodeNote(ref, int(args.Note))
+	})
+}
+
+func (r *resolver) RateShow(ctx context.Context, args struct {
+	Show int32
+	Note int32
+}) (*showResolver, error) {
+	show, err := r.bs.WithContext(ctx).ShowNote(bsclient.ShowID(int(args.Show)), int(args.Note))
+	if err != nil {
+		return nil, err
+	}
+	l := loadersOf(ctx)
+	l.forgetShow(show.ID)
+	l.shows.prime(show.ID, show)
+	return &showResolver{show: show}, nil
+}
+
+// showResolver resolves the fields of a show.
+type showResolver struct {
+	show *bsclient.Show
+}
+
+func newShowResolver(show *bsclient.Show, err error) (*showResolver, error) {
+	if err != nil || show == nil {
+		return nil, err
+	}
+	return &showResolver{show: show}, nil
+}
+
+func showResolvers(shows []bsclient.Show) []*showResolver {
+	resolvers := make([]*showResolver, len(shows))
+	for i := range shows {
+		resolvers[i] = &showResolver{show: &shows[i]}
+	}
+	return resolvers
+}
+
+func (r *showResolver) ID() int32           { return int32(r.show.ID) }
+func (r *showResolver) TVDBID() int32       { return int32(r.show.ThetvdbID) }
+func (r *showResolver) IMDBID() string      { return r.show.ImdbID }
+func (r *showResolver) Title() string       { return r.show.Title }
+func (r *showResolver) Description() string { return r.show.Description }
+func (r *showResolver) Seasons() int32      { return int32(r.show.Seasons) }
+func (r *showResolver) EpisodeCount() int32 { return int32(r.show.Episodes) }
+func (r *showResolver) Followers() int32    { return int32(r.show.Followers) }
+func (r *showResolver) Creation() int32     { return int32(r.show.Creation) }
+func (r *showResolver) Network() string     { return r.show.Network }
+func (r *showResolver) Status() string      { return r.show.Status }
+func (r *showResolver) Language() string    { return r.show.Language }
+func (r *showResolver) Note() float64       { return float64(r.show.Notes.Mean) }
+func (r *showResolver) UserNote() int32     { return int32(r.show.Notes.User) }
+func (r *showResolver) InAccount() bool     { return r.show.InAccount }
+func (r *showResolver) Archived() bool      { return r.show.User.Archived }
+func (r *showResolver) Favorited() bool     { return r.show.User.Favorited }
+func (r *showResolver) Remaining() int32    { return int32(r.show.User.Remaining) }
+func (r *showResolver) Poster() string      { return r.show.Images.Poster }
+
+func (r *showResolver) Genres() []string {
+	if r.show.Genres == nil {
+		return []string{}
+	}
+	return r.show.Genres
+}
+
+func (r *showResolver) Characters(ctx context.Context) ([]*characterResolver, error) {
+	characters, err := loadersOf(ctx).characters.load(r.show.ID)
+	resolvers := make([]*characterResolver, len(characters))
+	for i := range characters {
+		resolvers[i] = &characterResolver{character: &characters[i]}
+	}
+	return resolvers, err
+}
+
+func (r *showResolver) Similars(ctx context.Context) ([]*similarResolver, error) {
+	similars, err := loadersOf(ctx).similars.load(r.show.ID)
+	resolvers := make([]*similarResolver, len(similars))
+	for i := range similars {
+		resolvers[i] = &similarResolver{similar: &similars[i]}
+	}
+	return resolvers, err
+}
+
+func (r *showResolver) Videos(ctx context.Context) ([]*videoResolver, error) {
+	videos, err := loadersOf(ctx).videos.load(r.show.ID)
+	resolvers := make([]*videoResolver, len(videos))
+	for i := range videos {
+		resolvers[i] = &videoResolver{video: &videos[i]}
+	}
+	return resolvers, err
+}
+
+// Episodes returns the episodes of the show, all the seasons being loaded
+// once per request whatever the season asked.
+func (r *showResolver) Episodes(ctx context.Context, args struct{ Season *int32 }) ([]*episodeResolver, error) {
+	episodes, err := loadersOf(ctx).showEpisodes.load(r.show.ID)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := []*episodeResolver{}
+	for i := range episodes {
+		if args.Season == nil || episodes[i].Season == int(*args.Season) {
+			resolvers = append(resolvers, &episodeResolver{episode: &episodes[i]})
+		}
+	}
+	return resolvers, nil
+}
+
+func (r *showResolver) NextEpisode(ctx context.Context) (*episodeResolver, error) {
+	return newEpisodeResolver(loadersOf(ctx).nextEpisodes.load(r.show.ID))
+}
+
+func (r *showResolver) LatestEpisode(ctx context.Context) (*episodeResolver, error) {
+	return newEpisodeResolver(loadersOf(ctx).latestEpisodes.load(r.show.ID))
+}
+
+func (r *showResolver) Subtitles(ctx context.Context, args struct{ Language *string }) ([]*subtitleResolver, error) {
+	subtitles, err := loadersOf(ctx).showSubtitles.load(subtitlesKey{id: r.show.ID, language: language(args.Language)})
+	return subtitleResolvers(subtitles), err
+}
+
+// episodeResolver resolves the fields of an episode.
+type episodeResolver struct {
+	episode *bsclient.Episode
+}
+
+func newEpisodeResolver(episode *bsclient.Episode, err error) (*episodeResolver, error) {
+	if err != nil || episode == nil {
+		return nil, err
+	}
+	return &episodeResolver{episode: episode}, nil
+}
+
+func episodeResolvers(episodes []bsclient.Episode) []*episodeResolver {
+	resolvers := make([]*episodeResolver, len(episodes))
+	for i := range episodes {
+		resolvers[i] = &episodeResolver{episode: &episodes[i]}
+	}
+	return resolvers
+}
+
+func (r *episodeResolver) ID() int32           { return int32(r.episode.ID) }
+func (r *episodeResolver) TVDBID() int32       { return int32(r.episode.ThetvdbID) }
+func (r *episodeResolver) Title() string       { return r.episode.Title }
+func (r *episodeResolver) Season() int32       { return int32(r.episode.Season) }
+func (r *episodeResolver) Episode() int32      { return int32(r.episode.Episode) }
+func (r *episodeResolver) Code() string        { return r.episode.Code }
+func (r *episodeResolver) Date() string        { return formatDate(r.episode.Date) }
+func (r *episodeResolver) Description() string { return r.episode.Description }
+func (r *episodeResolver) Note() float64       { return float64(r.episode.Note.Mean) }
+func (r *episodeResolver) UserNote() int32     { return int32(r.episode.Note.User) }
+func (r *episodeResolver) Seen() bool          { return r.episode.User.Seen }
+func (r *episodeResolver) Downloaded() bool    { return r.episode.User.Downloaded }
+
+func (r *episodeResolver) Show(ctx context.Context) (*showResolver, error) {
+	if r.episode.Show.ID == 0 {
+		return nil, nil
+	}
+	return newShowResolver(loadersOf(ctx).shows.load(r.episode.Show.ID))
+}
+
+func (r *episodeResolver) Subtitles(ctx context.Context, args struct{ Language *string }) ([]*subtitleResolver, error) {
+	subtitles, err := loadersOf(ctx).episodeSubtitles.load(subtitlesKey{id: r.episode.ID, language: language(args.Language)})
+	return subtitleResolvers(subtitles), err
+}
+
+// memberResolver resolves the fields of a member.
+type memberResolver struct {
+	member *bsclient.Member
+}
+
+func (r *memberResolver) ID() int32                  { return int32(r.member.ID) }
+func (r *memberResolver) Login() string              { return r.member.Login }
+func (r *memberResolver) XP() int32                  { return int32(r.member.XP) }
+func (r *memberResolver) Avatar() string             { return r.member.Avatar }
+func (r *memberResolver) Favorites() []*showResolver { return showResolvers(r.member.Favorites) }
+
+// Shows returns the shows of the member, loading them if the member
+// comes from a list without them.
+func (r *memberResolver) Shows(ctx context.Context) ([]*showResolver, error) {
+	if r.member.Shows != nil {
+		return showResolvers(r.member.Shows), nil
+	}
+	member, err := loadersOf(ctx).members.load(r.member.ID)
+	if err != nil || member == nil {
+		return []*showResolver{}, err
+	}
+	return showResolvers(member.Shows), nil
+}
+
+func (r *memberResolver) Friends(ctx context.Context) ([]*memberResolver, error) {
+	friends, err := loadersOf(ctx).friends.load(r.member.ID)
+	resolvers := make([]*memberResolver, len(friends))
+	for i := range friends {
+		resolvers[i] = &memberResolver{member: &friends[i]}
+	}
+	return resolvers, err
+}
+
+// subtitleResolver resolves the fields of a subtitle.
+type subtitleResolver struct {
+	subtitle *bsclient.Subtitle
+}
+
+func subtitleResolvers(subtitles []bsclient.Subtitle) []*subtitleResolver {
+	resolvers := make([]*subtitleResolver, len(subtitles))
+	for i := range subtitles {
+		resolvers[i] = &subtitleResolver{subtitle: &subtitles[i]}
+	}
+	return resolvers
+}
+
+// language returns the language argument of subtitles, all by default.
+func language(arg *string) string {
+	if arg == nil {
+		return ""
+	}
+	return *arg
+}
+
+func (r *subtitleResolver) ID() int32        { return int32(r.subtitle.ID) }
+func (r *subtitleResolver) Language() string { return r.subtitle.Language }
+func (r *subtitleResolver) Source() string   { return r.subtitle.Source }
+func (r *subtitleResolver) Quality() int32   { return int32(r.subtitle.Quality) }
+func (r *subtitleResolver) File() string     { return r.subtitle.File }
+func (r *subtitleResolver) URL() string      { return r.subtitle.URL }
+func (r *subtitleResolver) Date() string     { return formatDate(r.subtitle.Date) }
+
+func (r *subtitleResolver) Episode(ctx context.Context) (*episodeResolver, error) {
+	if r.subtitle.Episode.EpisodeID == 0 {
+		return nil, nil
+	}
+	return newEpisodeResolver(loadersOf(ctx).episodes.load(r.subtitle.Episode.EpisodeID))
+}
+
+// characterResolver resolves the fields of a character.
+type characterResolver struct {
+	character *bsclient.Character
+}
+
+func (r *characterResolver) ID() int32           { return int32(r.character.ID) }
+func (r *characterResolver) Name() string        { return r.character.Name }
+func (r *characterResolver) Role() string        { return r.character.Role }
+func (r *characterResolver) Actor() string       { return r.character.Actor }
+func (r *characterResolver) Picture() string     { return r.character.Picture }
+func (r *characterResolver) Description() string { return r.character.Description }
+
+// similarResolver resolves the fields of a similar show.
+type similarResolver struct {
+	similar *bsclient.Similar
+}
+
+func (r *similarResolver) ID() int32 { return int32(r.similar.ID) }
+
+// Show returns the similar show, loaded unless the API returned its details.
+func (r *similarResolver) Show(ctx context.Context) (*showResolver, error) {
+	if r.similar.Show.ID != 0 {
+		return &showResolver{show: &r.similar.Show}, nil
+	}
+	if r.similar.ShowID == 0 {
+		return nil, nil
+	}
+	return newShowResolver(loadersOf(ctx).shows.load(r.similar.ShowID))
+}
+
+// videoResolver resolves the fields of a video.
+type videoResolver struct {
+	video *bsclient.Video
+}
+
+func (r *videoResolver) ID() int32          { return int32(r.video.ID) }
+func (r *videoResolver) Title() string      { return r.video.Title }
+func (r *videoResolver) YoutubeID() string  { return r.video.YoutubeID }
+func (r *videoResolver) YoutubeURL() string { return r.video.YoutubeURL }
+func (r *videoResolver) Season() int32      { return int32(r.video.Season) }
+func (r *videoResolver) Episode() int32     { return int32(r.video.Episode) }
@@ -0,0 +1,121 @@
+package graphql
+
+// Schema is the GraphQL schema of the facade.
+const Schema = `
+schema {
+	query: Query
+	mutation: Mutation
+}
+
+type Query {
+	# show returns a show by betaseries, TheTVDB, IMDB or TMDB id, or by title.
+	show(id: Int, tvdb: Int, imdb: String, tmdb: Int, title: String): Show
+	# episode returns an episode by betaseries or TheTVDB id.
+	episode(id: Int, tvdb: Int): Episode
+	searchShows(query: String!): [Show!]!
+	# member returns a member, the identified one by default.
+	member(id: Int): Member
+	# planning returns the planning of the identified member.
+	planning(month: String, unseen: Boolean): [Episode!]!
+}
+
+type Mutation {
+	markWatched(episode: Int!, note: Int): Episode
+	unmarkWatched(episode: Int!): Episode
+	markDownloaded(episode: Int!): Episode
+	unmarkDownloaded(episode: Int!): Episode
+	rateEpisode(episode: Int!, note: Int!): Episode
+	rateShow(show: Int!, note: Int!): Show
+}
+
+type Show {
+	id: Int!
+	tvdbId: Int!
+	imdbId: String!
+	title: String!
+	description: String!
+	seasons: Int!
+	episodeCount: Int!
+	followers: Int!
+	creation: Int!
+	genres: [String!]!
+	network: String!
+	status: String!
+	language: String!
+	note: Float!
+	userNote: Int!
+	inAccount: Boolean!
+	archived: Boolean!
+	favorited: Boolean!
+	remaining: Int!
+	poster: String!
+	characters: [Character!]!
+	similars: [Similar!]!
+	videos: [Video!]!
+	episodes(season: Int): [Episode!]!
+	nextEpisode: Episode
+	latestEpisode: Episode
+	subtitles(language: String): [Subtitle!]!
+}
+
+type Episode {
+	id: Int!
+	tvdbId: Int!
+	title: String!
+	season: Int!
+	episode: Int!
+	code: String!
+	date: String!
+	description: String!
+	note: Float!
+	userNote: Int!
+	seen: Boolean!
+	downloaded: Boolean!
+	show: Show
+	subtitles(language: String): [Subtitle!]!
+}
+
+type Member {
+	id: Int!
+	login: String!
+	xp: Int!
+	avatar: String!
+	shows: [Show!]!
+	favorites: [Show!]!
+	friends: [Member!]!
+}
+
+type Subtitle {
+	id: Int!
+	language: String!
+	source: String!
+	quality: Int!
+	file: String!
+	url: String!
+	date: String!
+	episode: Episode
+}
+
+type Character {
+	id: Int!
+	name: String!
+	role: String!
+	actor: String!
+	picture: String!
+	description: String!
+}
+
+type Similar {
+	id: Int!
+	show: Show
+}
+
+type Video {
+	id: Int!
+	title: String!
+	youtubeId: String!
+	youtubeUrl: String!
+	season: Int!
+	episode: Int!
+}
+`
@@ -0,0 +1,169 @@
+package ical
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+// DefaultLength is the episode runtime used when the show length is unknown.
+const DefaultLength = 30 * time.Minute
+
+var (
+	errInvalidRange  = errors.New("invalid date range")
+	errRangeTooLong  = errors.New("date range is too long")
+	errInvalidMember = errors.New("invalid member id")
+)
+
+// Query selects the episodes of a calendar.
+type Query struct {
+	// MemberID selects the planning of a member, the identified one if 0.
+	MemberID int
+	// General selects the planning of every show instead of a member's.
+	General bool
+	// Unseen only selects the episodes not seen by the member.
+	Unseen bool
+	// From and To are the dates of the first and the last selected days.
+	From, To time.Time
+}
+
+// Exporter builds calendars from the planning returned by the betaseries API.
+// Show runtimes are cached, an exporter can be used concurrently.
+type Exporter struct {
+	Client *bsclient.BetaSeries
+	// Name is the calendar name.
+	Name string
+	// AirTime is the offset from midnight given to air dates without
+	// a time of day. Episodes are whole day events if 0.
+	AirTime time.Duration
+	// Refresh is the update interval suggested to subscribed clients.
+	Refresh time.Duration
+
+	mutex   sync.Mutex
+	lengths map[int]time.Duration
+}
+
+// NewExporter creates an exporter of calendars named 'BetaSeries'.
+func NewExporter(bs *bsclient.BetaSeries) *Exporter {
+	return &Exporter{
+		Client:  bs,
+		Name:    "BetaSeries",
+		Refresh: 6 * time.Hour,
+		lengths: map[int]time.Duration{},
+	}
+}
+
+func day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, bsclient.APILocation)
+}
+
+// Planning returns the episodes selected by the query, ordered by date.
+func (e *Exporter) Planning(ctx context.Context, query *Query) ([]bsclient.Episode, error) {
+	client := e.Client.WithContext(ctx)
+	from, to := day(query.From), day(query.To)
+	if to.Before(from) {
+		return nil, errInvalidRange
+	}
+	episodes := []bsclient.Episode{}
+	if query.General {
+		days := int(to.Sub(from).Hours()/24 + 0.5)
+		found, err := client.PlanningGeneral(&bsclient.PlanningGeneralOptions{
+			Date:   from.Format("2006-01-02"),
+			Before: bsclient.Int(0),
+			After:  bsclient.Int(days),
+		})
+		if err != nil && !bsclient.IsNotFound(err) {
+			return nil, err
+		}
+		episodes = append(episodes, found...)
+	} else {
+		// the member planning is requested month by month
+		for month := from.AddDate(0, 0, 1-from.Day()); !month.After(to); month = month.AddDate(0, 1, 0) {
+			opts := &bsclient.PlanningMemberOptions{
+				Unseen: query.Unseen,
+				Month:  month.Format("2006-01"),
+			}
+			if query.MemberID > 0 {
+				opts.ID = bsclient.Int(query.MemberID)
+			}
+			found, err := client.PlanningMember(opts)
+			if err != nil && !bsclient.IsNotFound(err) {
+				return nil, err
+			}
+			episodes = append(episodes, found...)
+		}
+	}
+
+	seen := map[int]bool{}
+	selected := []bsclient.Episode{}
+	for _, episode := range episodes {
+		if seen[episode.ID] || episode.Date.IsZero() ||
+			episode.Date.Before(from) || !episode.Date.Before(to.AddDate(0, 0, 1)) {
+			continue
+		}
+		seen[episode.ID] = true
+		selected = append(selected, episode)
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].Date.Before(selected[j].Date)
+	})
+	return selected, nil
+}
+
+// length returns the runtime of the episodes of a show, DefaultLength if unknown.
+func (e *Exporter) length(client *bsclient.BetaSeries, showID int) time.Duration {
+	e.mutex.Lock()
+	length, ok := e.lengths[showID]
+	e.mutex.Unlock()
+	if ok {
+		return length
+	}
+	length = DefaultLength
+	show, err := client.ShowDisplay(bsclient.ShowID(showID))
+	if err != nil {
+		// transient errors are not cached
+		return length
+	}
+	if show != nil && show.Length > 0 {
+		length = show.Length
+	}
+	e.mutex.Lock()
+	if e.lengths == nil {
+		e.lengths = map[int]time.Duration{}
+	}
+	e.lengths[showID] = length
+	e.mutex.Unlock()
+	return length
+}
+
+// Calendar returns the calendar of the given episodes.
+func (e *Exporter) Calendar(ctx context.Context, episodes []bsclient.Episode) *Calendar {
+	client := e.Client.WithContext(ctx)
+	calendar := &Calendar{
+		Name:    e.Name,
+		Refresh: e.Refresh,
+		Events:  make([]Event, 0, len(episodes)),
+	}
+	for i := range episodes {
+		episode := &episodes[i]
+		calendar.Events = append(calendar.Events,
+			EpisodeEvent(episode, e.length(client, episode.Show.ID), e.AirTime))
+	}
+	return calendar
+}
+
+// Export writes the calendar of the episodes selected by the query.
+func (e *Exporter) Export(ctx context.Context, w io.Writer, query *Query) error {
+	episodes, err := e.Planning(ctx, query)
+	if err != nil {
+		return err
+	}
+	_, err = e.Calendar(ctx, episodes).WriteTo(w)
+	return err
+}
@@ -0,0 +1,87 @@
+package ical
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+// Default range of the calendars served by the handler, relative to today.
+const (
+	DefaultDaysBefore = 7
+	DefaultDaysAfter  = 30
+)
+
+// MaxDays is the longest range of the calendars served by the handler,
+// the member planning being requested month by month.
+const MaxDays = 366
+
+// parseQuery reads the calendar query from the URL parameters 'member',
+// 'general', 'unseen', 'from' and 'to' (YYYY-MM-DD).
+func parseQuery(values url.Values, now time.Time) (*Query, error) {
+	today := day(now)
+	query := &Query{
+		From: today.AddDate(0, 0, -DefaultDaysBefore),
+		To:   today.AddDate(0, 0, DefaultDaysAfter),
+	}
+	var err error
+	if member := values.Get("member"); member != "" {
+		if query.MemberID, err = strconv.Atoi(member); err != nil || query.MemberID <= 0 {
+			return nil, errInvalidMember
+		}
+	}
+	if general := values.Get("general"); general != "" {
+		if query.General, err = strconv.ParseBool(general); err != nil {
+			return nil, err
+		}
+	}
+	if unseen := values.Get("unseen"); unseen != "" {
+		if query.Unseen, err = strconv.ParseBool(unseen); err != nil {
+			return nil, err
+		}
+	}
+	for name, dst := range map[string]*time.Time{"from": &query.From, "to": &query.To} {
+		if value := values.Get(name); value != "" {
+			if *dst, err = time.ParseInLocation("2006-01-02", value, bsclient.APILocation); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if query.To.Before(query.From) {
+		return nil, errInvalidRange
+	}
+	if query.To.After(query.From.AddDate(0, 0, MaxDays)) {
+		return nil, errRangeTooLong
+	}
+	return query, nil
+}
+
+// ServeHTTP serves the calendar selected by the URL parameters as a
+// subscribable feed. See parseQuery for the parameters.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	query, err := parseQuery(r.URL.Query(), time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	buf := &bytes.Buffer{}
+	if err := e.Export(r.Context(), buf, query); err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="betaseries.ics"`)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	if r.Method == http.MethodGet {
+		w.Write(buf.Bytes())
+	}
+}
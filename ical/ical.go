@@ -0,0 +1,182 @@
+// Package ical exports the betaseries planning as iCalendar (RFC 5545) files
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+const (
+	prodID = "-//dns-gh//bs-client//EN"
+	// maxLineLength is the maximum length in octets of a content line, without the CRLF.
+	maxLineLength = 75
+
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405Z"
+)
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// Event represents a VEVENT component.
+type Event struct {
+	UID   string
+	Start time.Time
+	// AllDay events only use the date of Start and last one day.
+	AllDay      bool
+	Duration    time.Duration
+	Summary     string
+	Description string
+}
+
+// Calendar represents a VCALENDAR object.
+type Calendar struct {
+	Name string
+	// Refresh is the interval suggested to subscribed clients to update the calendar.
+	Refresh time.Duration
+	// Stamp is the creation date of the calendar, now if not set.
+	Stamp  time.Time
+	Events []Event
+}
+
+// EpisodeUID returns the stable unique identifier of the event of an episode.
+func EpisodeUID(episode *bsclient.Episode) string {
+	return fmt.Sprintf("episode-%d@betaseries.com", episode.ID)
+}
+
+// EpisodeEvent returns the event of an episode lasting 'length'. Air dates
+// without a time of day are set to 'airTime' after midnight, or to the whole
+// day if 'airTime' is 0.
+func EpisodeEvent(episode *bsclient.Episode, length, airTime time.Duration) Event {
+	summary := episode.Show.Title + " - " + episode.Code
+	if episode.Title != "" {
+		summary += " - " + episode.Title
+	}
+	description := episode.Description
+	if length > 0 {
+		if description != "" {
+			description += "\n\n"
+		}
+		description += fmt.Sprintf("Runtime: %d min", int(length/time.Minute))
+	}
+	event := Event{
+		UID:         EpisodeUID(episode),
+		Start:       episode.Date,
+		Duration:    length,
+		Summary:     summary,
+		Description: description,
+	}
+	hour, min, sec := episode.Date.Clock()
+	if hour == 0 && min == 0 && sec == 0 {
+		if airTime > 0 {
+			event.Start = episode.Date.Add(airTime)
+		} else {
+			event.AllDay = true
+		}
+	}
+	return event
+}
+
+// formatDuration returns a duration value, like 'PT1H30M'.
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return "PT0S"
+	}
+	out := "PT"
+	if h := d / time.Hour; h > 0 {
+		out += fmt.Sprintf("%dH", h)
+		d -= h * time.Hour
+	}
+	if m := d / time.Minute; m > 0 {
+		out += fmt.Sprintf("%dM", m)
+		d -= m * time.Minute
+	}
+	if s := d / time.Second; s > 0 {
+		out += fmt.Sprintf("%dS", s)
+	}
+	return out
+}
+
+// fold splits a content line in lines of at most 75 octets,
+// without breaking UTF-8 sequences.
+func fold(line string) string {
+	if len(line) <= maxLineLength {
+		return line + "\r\n"
+	}
+	b := strings.Builder{}
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// the leading space counts in the length of continuation lines
+		limit = maxLineLength - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+// countWriter keeps the number of bytes written and the first error.
+type countWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countWriter) line(name, value string) {
+	if cw.err != nil {
+		return
+	}
+	n, err := io.WriteString(cw.w, fold(name+":"+value))
+	cw.n += int64(n)
+	cw.err = err
+}
+
+// WriteTo writes the calendar in the iCalendar format.
+func (c *Calendar) WriteTo(w io.Writer) (int64, error) {
+	stamp := c.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+	cw := &countWriter{w: w}
+	cw.line("BEGIN", "VCALENDAR")
+	cw.line("VERSION", "2.0")
+	cw.line("PRODID", prodID)
+	cw.line("CALSCALE", "GREGORIAN")
+	cw.line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		cw.line("X-WR-CALNAME", escaper.Replace(c.Name))
+	}
+	if c.Refresh > 0 {
+		cw.line("REFRESH-INTERVAL;VALUE=DURATION", formatDuration(c.Refresh))
+		cw.line("X-PUBLISHED-TTL", formatDuration(c.Refresh))
+	}
+	for _, event := range c.Events {
+		cw.line("BEGIN", "VEVENT")
+		cw.line("UID", event.UID)
+		cw.line("DTSTAMP", stamp.UTC().Format(dateTimeLayout))
+		if event.AllDay {
+			cw.line("DTSTART;VALUE=DATE", event.Start.Format(dateLayout))
+			cw.line("DTEND;VALUE=DATE", event.Start.AddDate(0, 0, 1).Format(dateLayout))
+		} else {
+			cw.line("DTSTART", event.Start.UTC().Format(dateTimeLayout))
+			cw.line("DURATION", formatDuration(event.Duration))
+		}
+		cw.line("SUMMARY", escaper.Replace(event.Summary))
+		if event.Description != "" {
+			cw.line("DESCRIPTION", escaper.Replace(event.Description))
+		}
+		cw.line("END", "VEVENT")
+	}
+	cw.line("END", "VCALENDAR")
+	return cw.n, cw.err
+}
@@ -0,0 +1,200 @@
+package ical
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dns-gh/bs-client/bsclient"
+	"github.com/dns-gh/bs-client/internal/apitest"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+func makeEpisode(c *C, data string) *bsclient.Episode {
+	episode := &bsclient.Episode{}
+	c.Assert(json.Unmarshal([]byte(data), episode), IsNil)
+	return episode
+}
+
+func (s *MySuite) TestEpisodeEvent(c *C) {
+	episode := makeEpisode(c, `{"id": 42, "code": "S01E02", "title": "Cat's in the Bag...",
+		"date": "2008-01-27", "show": {"id": 481, "title": "Breaking Bad"}}`)
+
+	event := EpisodeEvent(episode, 45*time.Minute, 0)
+	c.Assert(event.UID, Equals, "episode-42@betaseries.com")
+	c.Assert(event.Summary, Equals, "Breaking Bad - S01E02 - Cat's in the Bag...")
+	c.Assert(event.Description, Equals, "Runtime: 45 min")
+	c.Assert(event.AllDay, Equals, true)
+
+	event = EpisodeEvent(episode, 45*time.Minute, 21*time.Hour)
+	c.Assert(event.AllDay, Equals, false)
+	c.Assert(event.Start.Hour(), Equals, 21)
+	c.Assert(event.Duration, Equals, 45*time.Minute)
+}
+
+func (s *MySuite) TestCalendarWriteTo(c *C) {
+	stamp := time.Date(2016, 1, 1, 12, 0, 0, 0, time.UTC)
+	calendar := &Calendar{
+		Name:    "Series",
+		Refresh: 6 * time.Hour,
+		Stamp:   stamp,
+		Events: []Event{
+			{
+				UID:         "episode-1@betaseries.com",
+				Start:       time.Date(2016, 1, 2, 21, 0, 0, 0, time.UTC),
+				Duration:    90 * time.Minute,
+				Summary:     "Show; with, specials",
+				Description: strings.Repeat("é", 50) + "\nend",
+			},
+			{
+				UID:     "episode-2@betaseries.com",
+				Start:   time.Date(2016, 1, 3, 0, 0, 0, 0, time.UTC),
+				AllDay:  true,
+				Summary: "All day",
+			},
+		},
+	}
+	buf := &bytes.Buffer{}
+	n, err := calendar.WriteTo(buf)
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(buf.Len()))
+
+	out := buf.String()
+	c.Assert(strings.HasSuffix(out, "END:VCALENDAR\r\n"), Equals, true)
+	lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+	for _, line := range lines {
+		c.Assert(len(line) <= maxLineLength, Equals, true, Commentf("%q", line))
+	}
+	c.Assert(lines[0], Equals, "BEGIN:VCALENDAR")
+	c.Assert(out, Matches, `(?s).*\r\nX-WR-CALNAME:Series\r\nREFRESH-INTERVAL;VALUE=DURATION:PT6H\r\n.*`)
+	c.Assert(out, Matches, `(?s).*\r\nDTSTAMP:20160101T120000Z\r\nDTSTART:20160102T210000Z\r\nDURATION:PT1H30M\r\n.*`)
+	c.Assert(out, Matches, `(?s).*\r\nSUMMARY:Show\\; with\\, specials\r\n.*`)
+	c.Assert(out, Matches, `(?s).*\r\nDTSTART;VALUE=DATE:20160103\r\nDTEND;VALUE=DATE:20160104\r\n.*`)
+
+	// unfolding restores the escaped description
+	unfolded := strings.Replace(out, "\r\n ", "", -1)
+	c.Assert(strings.Contains(unfolded, "DESCRIPTION:"+strings.Repeat("é", 50)+`\nend`+"\r\n"), Equals, true)
+}
+
+func (s *MySuite) TestParseQuery(c *C) {
+	now := time.Date(2016, 3, 10, 15, 0, 0, 0, bsclient.APILocation)
+	query, err := parseQuery(url.Values{}, now)
+	c.Assert(err, IsNil)
+	c.Assert(query.From.Format("2006-01-02"), Equals, "2016-03-03")
+	c.Assert(query.To.Format("2006-01-02"), Equals, "2016-04-09")
+
+	query, err = parseQuery(url.Values{
+		"member": {"12"},
+		"unseen": {"1"},
+		"from":   {"2016-01-01"},
+		"to":     {"2016-01-31"},
+	}, now)
+	c.Assert(err, IsNil)
+	c.Assert(query.MemberID, Equals, 12)
+	c.Assert(query.Unseen, Equals, true)
+	c.Assert(query.From.Day(), Equals, 1)
+	c.Assert(query.To.Day(), Equals, 31)
+
+	_, err = parseQuery(url.Values{"member": {"-1"}}, now)
+	c.Assert(err, Equals, errInvalidMember)
+	_, err = parseQuery(url.Values{"from": {"2016-02-01"}, "to": {"2016-01-01"}}, now)
+	c.Assert(err, Equals, errInvalidRange)
+	_, err = parseQuery(url.Values{"from": {"01/02/2016"}}, now)
+	c.Assert(err, NotNil)
+	_, err = parseQuery(url.Values{"from": {"2016-01-01"}, "to": {"2016-12-31"}}, now)
+	c.Assert(err, IsNil)
+	_, err = parseQuery(url.Values{"from": {"1900-01-01"}, "to": {"2100-01-01"}}, now)
+	c.Assert(err, Equals, errRangeTooLong)
+}
+
+func newTestExporter(c *C) (*apitest.Server, *Exporter, func()) {
+	api := apitest.NewServer(map[string]string{
+		"GET /planning/general": `{"episodes": [
+			{"id": 4, "code": "S02E01", "title": "Four", "date": "2016-02-01", "show": {"id": 2, "title": "Other"}}]}`,
+		"GET /shows/display": `{"show": {"id": 1, "length": "45"}}`,
+	})
+	// only the planning of January has episodes
+	api.Handle("GET /planning/member", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("month") != "2016-01" {
+			w.Write([]byte(`{"episodes": []}`))
+			return
+		}
+		w.Write([]byte(`{"episodes": [
+			{"id": 2, "code": "S01E02", "title": "Two", "date": "2016-01-31", "show": {"id": 1, "title": "Show"}},
+			{"id": 1, "code": "S01E01", "title": "One", "date": "2016-01-30", "show": {"id": 1, "title": "Show"}},
+			{"id": 3, "code": "S01E03", "title": "Early", "date": "2016-01-02", "show": {"id": 1, "title": "Show"}}]}`))
+	})
+	bs, err := bsclient.NewBetaseriesClient("key", "", "", bsclient.WithBaseURL(api.URL))
+	c.Assert(err, IsNil)
+	return api, NewExporter(bs), func() {
+		c.Check(api.Unexpected(), HasLen, 0)
+		api.Close()
+	}
+}
+
+func (s *MySuite) TestPlanning(c *C) {
+	api, e, stop := newTestExporter(c)
+	defer stop()
+	query := &Query{
+		MemberID: 12,
+		Unseen:   true,
+		From:     time.Date(2016, 1, 30, 0, 0, 0, 0, bsclient.APILocation),
+		To:       time.Date(2016, 2, 2, 0, 0, 0, 0, bsclient.APILocation),
+	}
+	episodes, err := e.Planning(context.Background(), query)
+	c.Assert(err, IsNil)
+	c.Assert(episodes, HasLen, 2)
+	c.Assert(episodes[0].ID, Equals, 1)
+	c.Assert(episodes[1].ID, Equals, 2)
+	c.Assert(api.Requests(), DeepEquals, []string{
+		"GET /planning/member?id=12&month=2016-01&unseen=true",
+		"GET /planning/member?id=12&month=2016-02&unseen=true",
+	})
+
+	api.Reset()
+	query.General = true
+	episodes, err = e.Planning(context.Background(), query)
+	c.Assert(err, IsNil)
+	c.Assert(episodes, HasLen, 1)
+	c.Assert(episodes[0].ID, Equals, 4)
+	c.Assert(api.Requests(), DeepEquals, []string{"GET /planning/general?after=3&before=0&date=2016-01-30"})
+
+	query.To = query.From.AddDate(0, 0, -1)
+	_, err = e.Planning(context.Background(), query)
+	c.Assert(err, Equals, errInvalidRange)
+}
+
+func (s *MySuite) TestExport(c *C) {
+	api, e, stop := newTestExporter(c)
+	defer stop()
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?from=2016-01-30&to=2016-01-31", nil))
+	c.Assert(w.Code, Equals, http.StatusOK)
+	c.Assert(w.Header().Get("Content-Type"), Equals, "text/calendar; charset=utf-8")
+	out := w.Body.String()
+	c.Assert(strings.Count(out, "BEGIN:VEVENT"), Equals, 2)
+	c.Assert(out, Matches, `(?s).*\r\nSUMMARY:Show - S01E01 - One\r\n.*`)
+	c.Assert(out, Matches, `(?s).*\r\nDESCRIPTION:Runtime: 45 min\r\n.*`)
+	// the runtime of the show is only requested once
+	c.Assert(api.Requests(), HasLen, 2)
+
+	// long ranges are refused before any request
+	api.Reset()
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?from=1900-01-01&to=2100-01-01", nil))
+	c.Assert(w.Code, Equals, http.StatusBadRequest)
+	c.Assert(api.Requests(), HasLen, 0)
+}
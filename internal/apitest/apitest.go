@@ -0,0 +1,118 @@
+// Package apitest provides a fake betaseries API for the tests of the client
+// and of the packages built on it.
+package apitest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Server is a fake API answering each request with the body registered for
+// its method and path, and recording the requests.
+type Server struct {
+	*httptest.Server
+	mutex      sync.Mutex
+	bodies     map[string]string
+	handlers   map[string]http.HandlerFunc
+	requests   []string
+	unexpected []string
+}
+
+// NewServer starts a fake API. Bodies are keyed by 'METHOD /path', or by
+// '/path' to answer every method. Other requests get a 404 API error.
+func NewServer(bodies map[string]string) *Server {
+	s := &Server{
+		bodies:   bodies,
+		handlers: map[string]http.HandlerFunc{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	key := r.Method + " " + r.URL.Path
+	s.mutex.Lock()
+	s.requests = append(s.requests, key+"?"+r.URL.RawQuery)
+	handler, ok := s.handlers[key]
+	if !ok {
+		handler, ok = s.handlers[r.URL.Path]
+	}
+	body, found := s.bodies[key]
+	if !found {
+		body, found = s.bodies[r.URL.Path]
+	}
+	if !ok && !found {
+		s.unexpected = append(s.unexpected, key)
+	}
+	s.mutex.Unlock()
+	switch {
+	case ok:
+		handler(w, r)
+	case found:
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	default:
+		WriteError(w, http.StatusNotFound, 0, "unexpected request "+key)
+	}
+}
+
+// Handle answers the requests of a 'METHOD /path' or '/path' key with
+// a handler instead of a body, to fail some of them for instance.
+func (s *Server) Handle(key string, handler http.HandlerFunc) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.handlers[key] = handler
+}
+
+// Requests returns the received requests as 'METHOD /path?query', in order.
+func (s *Server) Requests() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string{}, s.requests...)
+}
+
+// Count returns the number of requests received for a 'METHOD /path' key.
+func (s *Server) Count(key string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	count := 0
+	for _, request := range s.requests {
+		if strings.HasPrefix(request, key+"?") {
+			count++
+		}
+	}
+	return count
+}
+
+// Unexpected returns the 'METHOD /path' of the requests which had neither
+// a body nor a handler.
+func (s *Server) Unexpected() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string{}, s.unexpected...)
+}
+
+// Reset forgets the received requests.
+func (s *Server) Reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requests = nil
+	s.unexpected = nil
+}
+
+type apiError struct {
+	Code int    `json:"code"`
+	Text string `json:"text"`
+}
+
+// WriteError answers a request with an API error.
+func WriteError(w http.ResponseWriter, status, code int, text string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(struct {
+		Errors []apiError `json:"errors"`
+	}{[]apiError{{Code: code, Text: text}}})
+}
@@ -0,0 +1,254 @@
+package library
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+// MissingEpisode represents an aired episode with no matching file.
+type MissingEpisode struct {
+	ID    int    `json:"id"`
+	Code  string `json:"code"`
+	Title string `json:"title"`
+	Date  string `json:"date"`
+}
+
+// SeasonReport lists the missing episodes of a season.
+type SeasonReport struct {
+	Number  int              `json:"number"`
+	Missing []MissingEpisode `json:"missing"`
+}
+
+// ShowReport lists the missing episodes of a show, grouped by season.
+type ShowReport struct {
+	ID      int            `json:"id"`
+	Title   string         `json:"title"`
+	Seasons []SeasonReport `json:"seasons"`
+}
+
+// ExtraFile represents a file for an episode which is not in the account.
+type ExtraFile struct {
+	Path   string `json:"path"`
+	Show   string `json:"show"`
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+// MissingReport is the result of the comparison between a library
+// and the shows of the member's account.
+type MissingReport struct {
+	Shows []ShowReport `json:"shows"`
+	Extra []ExtraFile  `json:"extra"`
+	// Unidentified lists the files whose name could not be parsed.
+	Unidentified []string `json:"unidentified"`
+	// Errors lists the paths which could not be read.
+	Errors map[string]string `json:"errors"`
+}
+
+type episodeKey struct {
+	season  int
+	episode int
+}
+
+type showFiles struct {
+	show     *bsclient.Show
+	numbered map[episodeKey]bool
+	releases []*bsclient.Release
+	paths    []string
+}
+
+// isAired returns true if the episode was broadcast before 'now'.
+func isAired(episode *bsclient.Episode, now time.Time) bool {
+	return !episode.Date.IsZero() && !episode.Date.After(now)
+}
+
+// Missing compares the video files found under the given directories with the
+// episodes of every show in the member's account. It reports aired episodes with
+// no matching file and files for episodes which are not in the account, along
+// with the files whose name could not be parsed.
+// Files are identified locally so the API is only called once per show.
+func (s *Scanner) Missing(dirs ...string) (*MissingReport, error) {
+	extensions := s.Extensions
+	if len(extensions) == 0 {
+		extensions = VideoExtensions
+	}
+	member, err := s.Client.MembersInfos(&bsclient.MembersInfosOptions{Only: "shows"})
+	if err != nil {
+		return nil, err
+	}
+	files, failed := walkVideos(dirs, extensions)
+
+	shows := map[string]*showFiles{}
+	ordered := []*showFiles{}
+	for i := range member.Shows {
+		sf := &showFiles{
+			show:     &member.Shows[i],
+			numbered: map[episodeKey]bool{},
+		}
+		ordered = append(ordered, sf)
+		shows[bsclient.NormalizeTitle(sf.show.Title)] = sf
+		for _, alias := range sf.show.Aliases {
+			shows[bsclient.NormalizeTitle(alias)] = sf
+		}
+	}
+
+	report := &MissingReport{Errors: map[string]string{}}
+	for path, err := range failed {
+		report.Errors[path] = err.Error()
+	}
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		release, err := bsclient.ParseRelease(filepath.Base(path))
+		if err != nil {
+			report.Unidentified = append(report.Unidentified, path)
+			continue
+		}
+		if release.Title == "" {
+			release.Title = showFolder(path)
+		}
+		sf, ok := shows[bsclient.NormalizeTitle(release.Title)]
+		if !ok {
+			report.Extra = append(report.Extra, ExtraFile{
+				Path:   path,
+				Show:   release.Title,
+				Code:   release.Code(),
+				Reason: "show not in account",
+			})
+			continue
+		}
+		for _, number := range release.Episodes {
+			sf.numbered[episodeKey{release.Season, number}] = true
+		}
+		sf.releases = append(sf.releases, release)
+		sf.paths = append(sf.paths, path)
+	}
+
+	now := time.Now()
+	for _, sf := range ordered {
+		episodes, err := s.Client.ShowsEpisodes(sf.show.Ref(), nil)
+		// shows without any episode yet are listed as missing nothing
+		if err != nil && !bsclient.IsNotFound(err) {
+			return nil, err
+		}
+		have := map[int]bool{}
+		known := map[episodeKey]bool{}
+		for _, episode := range episodes {
+			key := episodeKey{episode.Season, episode.Episode}
+			known[key] = true
+			if sf.numbered[key] {
+				have[episode.ID] = true
+			}
+			for _, release := range sf.releases {
+				if !release.Date.IsZero() && release.Date.Format("2006-01-02") == episode.Date.Format("2006-01-02") ||
+					release.Absolute > 0 && release.Absolute == episode.Global {
+					have[episode.ID] = true
+				}
+			}
+		}
+		for i, release := range sf.releases {
+			for _, number := range release.Episodes {
+				if !known[episodeKey{release.Season, number}] {
+					report.Extra = append(report.Extra, ExtraFile{
+						Path:   sf.paths[i],
+						Show:   sf.show.Title,
+						Code:   fmt.Sprintf("S%02dE%02d", release.Season, number),
+						Reason: "episode not found in show",
+					})
+				}
+			}
+		}
+
+		showReport := ShowReport{ID: sf.show.ID, Title: sf.show.Title}
+		for i := range episodes {
+			episode := &episodes[i]
+			if have[episode.ID] || !isAired(episode, now) {
+				continue
+			}
+			n := len(showReport.Seasons)
+			if n == 0 || showReport.Seasons[n-1].Number != episode.Season {
+				showReport.Seasons = append(showReport.Seasons, SeasonReport{Number: episode.Season})
+				n++
+			}
+			showReport.Seasons[n-1].Missing = append(showReport.Seasons[n-1].Missing, MissingEpisode{
+				ID:    episode.ID,
+				Code:  episode.Code,
+				Title: episode.Title,
+				Date:  episode.Date.Format("2006-01-02"),
+			})
+		}
+		if len(showReport.Seasons) > 0 {
+			report.Shows = append(report.Shows, showReport)
+		}
+	}
+	sort.Slice(report.Shows, func(i, j int) bool {
+		return report.Shows[i].Title < report.Shows[j].Title
+	})
+	return report, nil
+}
+
+// WriteText writes a human readable version of the report.
+func (r *MissingReport) WriteText(w io.Writer) error {
+	ew := &errWriter{w: w}
+	for _, show := range r.Shows {
+		ew.printf("%s\n", show.Title)
+		for _, season := range show.Seasons {
+			ew.printf("  Season %d: %d missing\n", season.Number, len(season.Missing))
+			for _, episode := range season.Missing {
+				ew.printf("    %s %s (%s)\n", episode.Code, episode.Title, episode.Date)
+			}
+		}
+	}
+	if len(r.Extra) > 0 {
+		ew.printf("Files not in account\n")
+		for _, extra := range r.Extra {
+			ew.printf("  %s %s: %s (%s)\n", extra.Show, extra.Code, extra.Path, extra.Reason)
+		}
+	}
+	if len(r.Unidentified) > 0 {
+		ew.printf("Unidentified files\n")
+		for _, path := range r.Unidentified {
+			ew.printf("  %s\n", path)
+		}
+	}
+	if len(r.Errors) > 0 {
+		paths := make([]string, 0, len(r.Errors))
+		for path := range r.Errors {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		ew.printf("Unreadable paths\n")
+		for _, path := range paths {
+			ew.printf("  %s: %s\n", path, r.Errors[path])
+		}
+	}
+	return ew.err
+}
+
+// WriteJSON writes the report in JSON.
+func (r *MissingReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// errWriter keeps the first error of a sequence of writes.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err == nil {
+		_, ew.err = fmt.Fprintf(ew.w, format, args...)
+	}
+}
@@ -0,0 +1,113 @@
+package library
+
+import (
+	"bytes"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestMissingReportText(c *C) {
+	report := &MissingReport{
+		Shows: []ShowReport{{
+			ID:    481,
+			Title: "Breaking Bad",
+			Seasons: []SeasonReport{{
+				Number: 5,
+				Missing: []MissingEpisode{
+					{ID: 1, Code: "S05E14", Title: "Ozymandias", Date: "2013-09-15"},
+				},
+			}},
+		}},
+		Extra: []ExtraFile{{
+			Path:   "/videos/Lost.S01E01.mkv",
+			Show:   "Lost",
+			Code:   "S01E01",
+			Reason: "show not in account",
+		}},
+		Unidentified: []string{"/videos/holidays.mkv"},
+		Errors:       map[string]string{"/videos/private": "permission denied"},
+	}
+	buf := &bytes.Buffer{}
+	c.Assert(report.WriteText(buf), IsNil)
+	c.Assert(buf.String(), Equals, `Breaking Bad
+  Season 5: 1 missing
+    S05E14 Ozymandias (2013-09-15)
+Files not in account
+  Lost S01E01: /videos/Lost.S01E01.mkv (show not in account)
+Unidentified files
+  /videos/holidays.mkv
+Unreadable paths
+  /videos/private: permission denied
+`)
+}
+
+func (s *MySuite) TestMissing(c *C) {
+	api, bs, stop := newFakeAPI(c, map[string]string{
+		"GET /members/infos": `{"member": {"id": 1, "shows": [
+			{"id": 1, "title": "Breaking Bad", "aliases": ["Breaking Bad (US)"]}]}}`,
+		"GET /shows/episodes": `{"episodes": [
+			{"id": 113, "code": "S05E13", "season": 5, "episode": 13, "title": "To'hajiilee", "date": "2013-09-08"},
+			{"id": 114, "code": "S05E14", "season": 5, "episode": 14, "title": "Ozymandias", "date": "2013-09-15"},
+			{"id": 115, "code": "S05E15", "season": 5, "episode": 15, "title": "Granite State", "date": "2013-09-22"},
+			{"id": 116, "code": "S05E16", "season": 5, "episode": 16, "title": "Felina", "date": "2099-09-29"}]}`,
+	})
+	defer stop()
+	dir := c.MkDir()
+	writeFiles(c, dir,
+		"Breaking Bad/Season 5/S05E13.mkv",
+		"Breaking.Bad.US.S05E14.mkv",
+		"Breaking.Bad.S06E01.mkv",
+		"Dexter.S01E01.mkv",
+		"holidays.mkv")
+	missing := filepath.Join(dir, "missing")
+
+	report, err := NewScanner(bs).Missing(dir, missing)
+	c.Assert(err, IsNil)
+	c.Assert(report.Shows, DeepEquals, []ShowReport{{
+		ID:    1,
+		Title: "Breaking Bad",
+		Seasons: []SeasonReport{{
+			Number: 5,
+			Missing: []MissingEpisode{
+				{ID: 115, Code: "S05E15", Title: "Granite State", Date: "2013-09-22"},
+			},
+		}},
+	}})
+	c.Assert(report.Extra, DeepEquals, []ExtraFile{{
+		Path:   filepath.Join(dir, "Dexter.S01E01.mkv"),
+		Show:   "Dexter",
+		Code:   "S01E01",
+		Reason: "show not in account",
+	}, {
+		Path:   filepath.Join(dir, "Breaking.Bad.S06E01.mkv"),
+		Show:   "Breaking Bad",
+		Code:   "S06E01",
+		Reason: "episode not found in show",
+	}})
+	c.Assert(report.Unidentified, DeepEquals, []string{filepath.Join(dir, "holidays.mkv")})
+	c.Assert(report.Errors, HasLen, 1)
+	c.Assert(report.Errors[missing], Not(Equals), "")
+	// the episodes are only requested once per show
+	c.Assert(api.Requests(), HasLen, 2)
+}
+
+func (s *MySuite) TestMissingWithoutEpisodes(c *C) {
+	_, bs, stop := newFakeAPI(c, map[string]string{
+		"GET /members/infos":  `{"member": {"id": 1, "shows": [{"id": 2, "title": "Westworld"}]}}`,
+		"GET /shows/episodes": `{"episodes": []}`,
+	})
+	defer stop()
+	dir := c.MkDir()
+	writeFiles(c, dir, "Westworld.S01E01.mkv")
+
+	report, err := NewScanner(bs).Missing(dir)
+	c.Assert(err, IsNil)
+	c.Assert(report.Shows, HasLen, 0)
+	c.Assert(report.Extra, DeepEquals, []ExtraFile{{
+		Path:   filepath.Join(dir, "Westworld.S01E01.mkv"),
+		Show:   "Westworld",
+		Code:   "S01E01",
+		Reason: "episode not found in show",
+	}})
+}
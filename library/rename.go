@@ -0,0 +1,426 @@
+package library
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+// DefaultTemplate is the naming template used when none is set.
+// The template is relative to the destination directory and must end with '.{ext}'.
+const DefaultTemplate = "{Show}/Season {Season:02}/{Show} - {Code} - {Title}.{ext}"
+
+// SubtitleExtensions are the extensions of subtitle files moved along with their video.
+var SubtitleExtensions = []string{".ass", ".idx", ".srt", ".ssa", ".sub", ".vtt"}
+
+var (
+	errTemplateExtension = errors.New("template must end with '.{ext}'")
+	errDestinationExists = errors.New("destination already exists")
+	errVideoNotMoved     = errors.New("video was not moved")
+
+	reTemplateField = regexp.MustCompile(`\{(\w+)(?::(\d+))?\}`)
+	unsafeChars     = strings.NewReplacer(
+		"/", "-", "\\", "-", ":", " -", "*", "", "?", "", "\"", "'", "<", "", ">", "", "|", "-")
+
+	// subtitle names are the video name with an optional language: '.srt', '.fr.srt'
+	reSubtitleSuffix = regexp.MustCompile(`^(\.[A-Za-z]{2,3})?\.[A-Za-z]+$`)
+)
+
+// Collision policies, used when a destination file already exists.
+const (
+	// CollisionSkip leaves the source file in place and reports an error.
+	CollisionSkip = iota
+	// CollisionNumber appends ' (2)', ' (3)', ... to the destination name.
+	CollisionNumber
+)
+
+// Move represents the renaming of a file.
+type Move struct {
+	From string    `json:"from"`
+	To   string    `json:"to"`
+	Date time.Time `json:"date"`
+	// Video is the source of the video move that a subtitle move follows,
+	// so that the subtitle is left in place if its video could not be moved.
+	Video string `json:"-"`
+	Err   error  `json:"-"`
+	// LogErr is set if the move was applied but could not be
+	// written to the undo log.
+	LogErr error `json:"-"`
+}
+
+// Renamer renames and moves video files and their subtitles according to a
+// template filled with the episode metadata returned by EpisodeScraper and EpisodeDisplay.
+// Available template fields are {Show}, {ShowID}, {Season}, {Episode}, {Code},
+// {Title}, {ID} and {ext}. Numbers can be zero padded with '{Season:02}'.
+type Renamer struct {
+	Client *bsclient.BetaSeries
+	// Template defaults to DefaultTemplate.
+	Template string
+	// Dest is the destination root directory. Files are renamed
+	// relatively to the scanned directory containing them if empty.
+	Dest string
+	// Collision is the policy applied when a destination already exists.
+	Collision int
+	// DryRun only previews the moves.
+	DryRun bool
+	// UndoLog is the path of a log file receiving the applied moves,
+	// so that they can be reverted with Undo.
+	UndoLog string
+	// Output receives a line for each move, if set.
+	Output io.Writer
+}
+
+func sanitize(value string) string {
+	value = unsafeChars.Replace(value)
+	value = strings.Map(func(r rune) rune {
+		if r < 32 {
+			return -1
+		}
+		return r
+	}, value)
+	// trailing dots and spaces are not allowed on some filesystems
+	return strings.TrimRight(strings.Join(strings.Fields(value), " "), ". ")
+}
+
+// templateFields returns the values available in templates for the given episode.
+func templateFields(episode *bsclient.Episode, ext string) map[string]interface{} {
+	return map[string]interface{}{
+		"Show":    episode.Show.Title,
+		"ShowID":  episode.Show.ID,
+		"Season":  episode.Season,
+		"Episode": episode.Episode,
+		"Code":    episode.Code,
+		"Title":   episode.Title,
+		"ID":      episode.ID,
+		"ext":     ext,
+	}
+}
+
+// Format fills the template with the given episode data. Every value is
+// stripped from the characters that are unsafe in file names.
+func Format(template string, episode *bsclient.Episode, ext string) (string, error) {
+	if !strings.HasSuffix(template, ".{ext}") {
+		return "", errTemplateExtension
+	}
+	fields := templateFields(episode, strings.TrimPrefix(ext, "."))
+	var err error
+	out := reTemplateField.ReplaceAllStringFunc(template, func(field string) string {
+		m := reTemplateField.FindStringSubmatch(field)
+		value, ok := fields[m[1]]
+		if !ok {
+			err = fmt.Errorf("unknown template field %s", m[1])
+			return ""
+		}
+		if number, isInt := value.(int); isInt && m[2] != "" {
+			width, _ := strconv.Atoi(m[2])
+			return fmt.Sprintf("%0*d", width, number)
+		}
+		return sanitize(fmt.Sprint(value))
+	})
+	return filepath.FromSlash(out), err
+}
+
+func (r *Renamer) printf(format string, args ...interface{}) {
+	if r.Output != nil {
+		fmt.Fprintf(r.Output, format, args...)
+	}
+}
+
+// identify returns the complete episode data of a video file.
+func (r *Renamer) identify(path string) (*bsclient.Episode, error) {
+	episode, err := r.Client.EpisodeScraper(filepath.Base(path))
+	if err != nil {
+		return nil, err
+	}
+	if episode == nil {
+		return nil, fmt.Errorf("no episode found for %s", path)
+	}
+	// the scraper does not return every episode detail, like its title
+	if full, err := r.Client.EpisodeDisplay(episode.Ref(), nil); err == nil && full != nil {
+		episode = full
+	}
+	return episode, nil
+}
+
+// subtitlesOf returns the subtitle files named after a video, with an
+// optional 2 or 3 letters language code, along with their suffix ('.fr.srt').
+func subtitlesOf(video string) (map[string]string, error) {
+	base := strings.TrimSuffix(video, filepath.Ext(video))
+	matches, err := filepath.Glob(globEscape(base) + ".*")
+	if err != nil {
+		return nil, err
+	}
+	subtitles := map[string]string{}
+	for _, match := range matches {
+		suffix := strings.TrimPrefix(match, base)
+		if isVideo(match, SubtitleExtensions) && reSubtitleSuffix.MatchString(suffix) {
+			subtitles[match] = suffix
+		}
+	}
+	return subtitles, nil
+}
+
+func globEscape(path string) string {
+	return strings.NewReplacer("[", "\\[", "]", "\\]", "*", "\\*", "?", "\\?").Replace(path)
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// resolveCollision returns a free destination for the file 'from' according
+// to the collision policy. 'from' is returned if it is already a valid destination.
+func (r *Renamer) resolveCollision(from, to string, planned map[string]bool) (string, error) {
+	if to == from || !exists(to) && !planned[to] {
+		return to, nil
+	}
+	if r.Collision != CollisionNumber {
+		return "", errDestinationExists
+	}
+	ext := filepath.Ext(to)
+	base := strings.TrimSuffix(to, ext)
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)%s", base, i, ext)
+		if candidate == from || !exists(candidate) && !planned[candidate] {
+			return candidate, nil
+		}
+	}
+}
+
+// libraryRoot returns the deepest of the scanned directories containing 'path'.
+func libraryRoot(path string, dirs []string) string {
+	root := ""
+	for _, dir := range dirs {
+		abs, err := filepath.Abs(dir)
+		if err != nil || abs == path {
+			continue
+		}
+		if inDirs(path, []string{abs}) && len(abs) > len(root) {
+			root = abs
+		}
+	}
+	if root == "" {
+		return filepath.Dir(path)
+	}
+	return root
+}
+
+// Plan identifies the video files found under the given directories and
+// returns the moves needed to rename them and their subtitles, without
+// touching the filesystem. Files already named after the template are left
+// in place, so that planning again after a rename returns no moves.
+func (r *Renamer) Plan(dirs ...string) ([]Move, error) {
+	template := r.Template
+	if template == "" {
+		template = DefaultTemplate
+	}
+	files, failed := walkVideos(dirs, VideoExtensions)
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	moves := []Move{}
+	unreadable := make([]string, 0, len(failed))
+	for path := range failed {
+		unreadable = append(unreadable, path)
+	}
+	sort.Strings(unreadable)
+	for _, path := range unreadable {
+		moves = append(moves, Move{From: path, Err: failed[path]})
+	}
+	planned := map[string]bool{}
+	for _, path := range paths {
+		episode, err := r.identify(path)
+		if err != nil {
+			moves = append(moves, Move{From: path, Err: err})
+			continue
+		}
+		name, err := Format(template, episode, filepath.Ext(path))
+		if err != nil {
+			return nil, err
+		}
+		root := r.Dest
+		if root == "" {
+			root = libraryRoot(path, dirs)
+		}
+		to, err := r.resolveCollision(path, filepath.Join(root, name), planned)
+		if err != nil {
+			moves = append(moves, Move{From: path, To: filepath.Join(root, name), Err: err})
+			continue
+		}
+		if to == path {
+			continue
+		}
+		planned[to] = true
+		moves = append(moves, Move{From: path, To: to})
+
+		subtitles, err := subtitlesOf(path)
+		if err != nil {
+			return nil, err
+		}
+		base := strings.TrimSuffix(to, filepath.Ext(to))
+		for subtitle, suffix := range subtitles {
+			target, err := r.resolveCollision(subtitle, base+suffix, planned)
+			if err != nil {
+				moves = append(moves, Move{From: subtitle, To: base + suffix, Video: path, Err: err})
+				continue
+			}
+			planned[target] = true
+			moves = append(moves, Move{From: subtitle, To: target, Video: path})
+		}
+	}
+	return moves, nil
+}
+
+// moveFile renames a file, copying it if the destination is on another filesystem.
+func moveFile(from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	if exists(to) {
+		return errDestinationExists
+	}
+	if err := os.Rename(from, to); err == nil {
+		return nil
+	}
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(to)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(to)
+		return err
+	}
+	return os.Remove(from)
+}
+
+func appendUndoLog(path string, move Move) error {
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return json.NewEncoder(f).Encode(move)
+}
+
+// Apply executes the given moves, skipping those with an error and the
+// subtitles of the videos which could not be moved. Each applied move is
+// written to the undo log, failures to do so being reported in LogErr since
+// the move has still been made. In dry-run mode, moves are only printed.
+func (r *Renamer) Apply(moves []Move) []Move {
+	prefix := ""
+	if r.DryRun {
+		prefix = "[dry-run] "
+	}
+	failed := map[string]bool{}
+	for i := range moves {
+		move := &moves[i]
+		if move.Err == nil && move.Video != "" && failed[move.Video] {
+			move.Err = errVideoNotMoved
+		}
+		if move.Err != nil {
+			failed[move.From] = true
+			r.printf("skipped: %s: %s\n", move.From, move.Err)
+			continue
+		}
+		r.printf("%s%s -> %s\n", prefix, move.From, move.To)
+		if r.DryRun {
+			continue
+		}
+		if move.Err = moveFile(move.From, move.To); move.Err != nil {
+			failed[move.From] = true
+			continue
+		}
+		move.Date = time.Now()
+		if move.LogErr = appendUndoLog(r.UndoLog, *move); move.LogErr != nil {
+			r.printf("undo log: %s: %s\n", move.From, move.LogErr)
+		}
+	}
+	return moves
+}
+
+// Rename plans and applies the renaming of the video files found under the
+// given directories.
+func (r *Renamer) Rename(dirs ...string) ([]Move, error) {
+	moves, err := r.Plan(dirs...)
+	if err != nil {
+		return nil, err
+	}
+	return r.Apply(moves), nil
+}
+
+// Undo reverts, from the most recent to the oldest, the moves recorded in the
+// undo log, then empties it. Moves which could not be reverted are returned with
+// their error and kept in the log.
+func Undo(undoLog string, dryRun bool) ([]Move, error) {
+	f, err := os.Open(undoLog)
+	if err != nil {
+		return nil, err
+	}
+	moves := []Move{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		move := Move{}
+		if err := json.Unmarshal(scanner.Bytes(), &move); err != nil {
+			f.Close()
+			return nil, err
+		}
+		moves = append(moves, move)
+	}
+	f.Close()
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	reverted := make([]Move, 0, len(moves))
+	failed := []Move{}
+	for i := len(moves) - 1; i >= 0; i-- {
+		move := Move{From: moves[i].To, To: moves[i].From}
+		if !dryRun {
+			move.Err = moveFile(move.From, move.To)
+		}
+		if move.Err != nil {
+			failed = append([]Move{moves[i]}, failed...)
+		}
+		reverted = append(reverted, move)
+	}
+	if dryRun {
+		return reverted, nil
+	}
+	if err := os.Remove(undoLog); err != nil {
+		return reverted, err
+	}
+	for _, move := range failed {
+		if err := appendUndoLog(undoLog, move); err != nil {
+			return reverted, err
+		}
+	}
+	return reverted, nil
+}
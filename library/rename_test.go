@@ -0,0 +1,135 @@
+package library
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/dns-gh/bs-client/bsclient"
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestFormat(c *C) {
+	episode := &bsclient.Episode{
+		ID:      1,
+		Title:   "Face Off: Part 1/2?",
+		Season:  4,
+		Episode: 13,
+		Code:    "S04E13",
+	}
+	episode.Show.Title = "Breaking Bad"
+	name, err := Format(DefaultTemplate, episode, ".mkv")
+	c.Assert(err, IsNil)
+	c.Assert(name, Equals, filepath.FromSlash("Breaking Bad/Season 04/Breaking Bad - S04E13 - Face Off - Part 1-2.mkv"))
+
+	_, err = Format("{Show}/{Code}", episode, ".mkv")
+	c.Assert(err, Equals, errTemplateExtension)
+
+	_, err = Format("{Show}/{Unknown}.{ext}", episode, ".mkv")
+	c.Assert(err, NotNil)
+}
+
+func (s *MySuite) TestMoveAndUndo(c *C) {
+	dir := c.MkDir()
+	video := filepath.Join(dir, "show.s01e01.mkv")
+	subtitle := filepath.Join(dir, "show.s01e01.fr.srt")
+	c.Assert(os.WriteFile(video, []byte("video"), 0644), IsNil)
+	c.Assert(os.WriteFile(subtitle, []byte("subtitle"), 0644), IsNil)
+	// neither languages nor subtitles of another video
+	writeFiles(c, dir, "show.s01e01.commentary.srt", "show.s01e01.e02.srt", "show.s01e01.fr.txt")
+
+	subtitles, err := subtitlesOf(video)
+	c.Assert(err, IsNil)
+	c.Assert(subtitles, DeepEquals, map[string]string{subtitle: ".fr.srt"})
+
+	undoLog := filepath.Join(dir, "undo.log")
+	r := &Renamer{UndoLog: undoLog}
+	target := filepath.Join(dir, "Show", "Season 01", "Show - S01E01.mkv")
+	moves := r.Apply([]Move{
+		{From: video, To: target},
+		{From: subtitle, To: filepath.Join(dir, "Show", "Season 01", "Show - S01E01.fr.srt")},
+	})
+	c.Assert(moves[0].Err, IsNil)
+	c.Assert(moves[1].Err, IsNil)
+	c.Assert(exists(video), Equals, false)
+	c.Assert(exists(target), Equals, true)
+
+	next, err := r.resolveCollision(video, target, map[string]bool{})
+	c.Assert(err, Equals, errDestinationExists)
+	r.Collision = CollisionNumber
+	next, err = r.resolveCollision(video, target, map[string]bool{})
+	c.Assert(err, IsNil)
+	c.Assert(next, Equals, filepath.Join(dir, "Show", "Season 01", "Show - S01E01 (2).mkv"))
+	next, err = r.resolveCollision(target, target, map[string]bool{})
+	c.Assert(err, IsNil)
+	c.Assert(next, Equals, target)
+
+	reverted, err := Undo(undoLog, false)
+	c.Assert(err, IsNil)
+	c.Assert(reverted, HasLen, 2)
+	c.Assert(exists(video), Equals, true)
+	c.Assert(exists(subtitle), Equals, true)
+	c.Assert(exists(undoLog), Equals, false)
+}
+
+func (s *MySuite) TestPlanTwice(c *C) {
+	_, bs, stop := newFakeAPI(c, map[string]string{
+		"GET /episodes/scraper": `{"episode": {"id": 114, "code": "S05E14", "season": 5, "episode": 14,
+			"show": {"id": 1, "title": "Breaking Bad"}}}`,
+		"GET /episodes/display": `{"episode": {"id": 114, "code": "S05E14", "season": 5, "episode": 14,
+			"title": "Ozymandias", "show": {"id": 1, "title": "Breaking Bad"}}}`,
+	})
+	defer stop()
+	dir := c.MkDir()
+	writeFiles(c, dir, "downloads/breaking.bad.s05e14.mkv", "downloads/breaking.bad.s05e14.fr.srt")
+	undoLog := filepath.Join(dir, "undo.log")
+	r := &Renamer{Client: bs, UndoLog: undoLog}
+
+	moves, err := r.Rename(dir)
+	c.Assert(err, IsNil)
+	c.Assert(moves, HasLen, 2)
+	target := filepath.Join(dir, "Breaking Bad", "Season 05", "Breaking Bad - S05E14 - Ozymandias")
+	c.Assert(moves[0].To, Equals, target+".mkv")
+	c.Assert(moves[1].To, Equals, target+".fr.srt")
+	for _, move := range moves {
+		c.Assert(move.Err, IsNil)
+		c.Assert(move.LogErr, IsNil)
+	}
+
+	// renamed files are left in place, whatever the collision policy
+	moves, err = r.Plan(dir)
+	c.Assert(err, IsNil)
+	c.Assert(moves, HasLen, 0)
+	r.Collision = CollisionNumber
+	moves, err = r.Plan(dir)
+	c.Assert(err, IsNil)
+	c.Assert(moves, HasLen, 0)
+
+	// a failure to log the move does not hide that it was made
+	c.Assert(os.Remove(target+".mkv"), IsNil)
+	writeFiles(c, dir, "breaking.bad.s05e14.mkv")
+	r.UndoLog = dir
+	moves, err = r.Rename(dir)
+	c.Assert(err, IsNil)
+	c.Assert(moves, HasLen, 1)
+	c.Assert(moves[0].Err, IsNil)
+	c.Assert(moves[0].LogErr, NotNil)
+	c.Assert(exists(target+".mkv"), Equals, true)
+}
+
+func (s *MySuite) TestApplySubtitlesOfFailedVideo(c *C) {
+	dir := c.MkDir()
+	writeFiles(c, dir, "show.s01e01.mkv", "show.s01e01.fr.srt", "Show - S01E01.mkv")
+	video := filepath.Join(dir, "show.s01e01.mkv")
+	subtitle := filepath.Join(dir, "show.s01e01.fr.srt")
+
+	// the video destination appeared after planning
+	r := &Renamer{}
+	moves := r.Apply([]Move{
+		{From: video, To: filepath.Join(dir, "Show - S01E01.mkv")},
+		{From: subtitle, To: filepath.Join(dir, "Show - S01E01.fr.srt"), Video: video},
+	})
+	c.Assert(moves[0].Err, Equals, errDestinationExists)
+	c.Assert(moves[1].Err, Equals, errVideoNotMoved)
+	c.Assert(exists(subtitle), Equals, true)
+	c.Assert(exists(filepath.Join(dir, "Show - S01E01.fr.srt")), Equals, false)
+}
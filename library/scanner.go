@@ -0,0 +1,339 @@
+// Package library synchronizes a local media library with a betaseries account
+package library
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+var errNoEpisodeFound = errors.New("no episode found")
+
+// VideoExtensions are the file extensions scanned by default.
+var VideoExtensions = []string{".avi", ".m4v", ".mkv", ".mov", ".mp4", ".mpg", ".ts", ".wmv"}
+
+// Entry represents a video file identified as one or several episodes.
+type Entry struct {
+	Path     string `json:"path"`
+	Show     string `json:"show"`
+	Code     string `json:"code"`
+	Episodes []int  `json:"episodes"`
+}
+
+// Report summarizes a scan.
+type Report struct {
+	Scanned int `json:"scanned"`
+	// Unchanged counts files already synchronized by a previous scan.
+	Unchanged int `json:"unchanged"`
+	// Downloaded lists files whose episodes have been marked as downloaded.
+	Downloaded []Entry `json:"downloaded"`
+	// Removed lists files gone from the disk whose episodes
+	// have been marked as not downloaded.
+	Removed []Entry `json:"removed"`
+	// Unidentified lists files that could not be matched to any episode.
+	Unidentified []string `json:"unidentified"`
+	// Errors lists the files for which the API returned an error,
+	// and the paths which could not be read.
+	Errors map[string]string `json:"errors"`
+}
+
+// Scanner walks directories, identifies video files and synchronizes the
+// downloaded flags of the account with the episodes found on disk.
+type Scanner struct {
+	Client *bsclient.BetaSeries
+	// Scraper enables the EpisodeScraper API for files the local
+	// parser cannot identify. Each call costs an API request.
+	Scraper bool
+	// Extensions overrides VideoExtensions if set.
+	Extensions []string
+	// DryRun reports what would be done without calling the API
+	// for updates nor writing the state file.
+	DryRun bool
+	// StateFile is the path of the local state file. Rescans are
+	// incremental only if it is set.
+	StateFile string
+	// Output receives a line for each change, if set.
+	Output io.Writer
+
+	resolver *bsclient.ReleaseResolver
+}
+
+// NewScanner creates a library scanner using the given client.
+func NewScanner(bs *bsclient.BetaSeries) *Scanner {
+	return &Scanner{
+		Client:   bs,
+		resolver: bsclient.NewReleaseResolver(bs),
+	}
+}
+
+func (s *Scanner) printf(format string, args ...interface{}) {
+	if s.Output != nil {
+		fmt.Fprintf(s.Output, format, args...)
+	}
+}
+
+func isVideo(path string, extensions []string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// walkVideos returns the video files found under the given directories,
+// along with the paths which could not be read. Unreadable directories are
+// skipped without stopping the walk.
+func walkVideos(dirs []string, extensions []string) (map[string]os.FileInfo, map[string]error) {
+	files := map[string]os.FileInfo{}
+	failed := map[string]error{}
+	for _, dir := range dirs {
+		filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			abs, absErr := filepath.Abs(path)
+			if absErr != nil {
+				failed[path] = absErr
+				return nil
+			}
+			if err != nil {
+				failed[abs] = err
+				return nil
+			}
+			if info.Mode().IsRegular() && isVideo(path, extensions) {
+				files[abs] = info
+			}
+			return nil
+		})
+	}
+	return files, failed
+}
+
+// inDirs returns true if 'path' is located under one of the directories.
+func inDirs(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(abs, path)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Identify returns the episodes contained in the given video file, parsing its
+// name locally and falling back to the EpisodeScraper API if enabled.
+func (s *Scanner) Identify(path string) ([]bsclient.Episode, error) {
+	if s.resolver == nil {
+		s.resolver = bsclient.NewReleaseResolver(s.Client)
+	}
+	name := filepath.Base(path)
+	release, err := bsclient.ParseRelease(name)
+	if err == nil {
+		// shows stored by folder: 'Show Name/Season 1/S01E01.mkv'
+		if release.Title == "" {
+			release.Title = showFolder(path)
+		}
+		episodes, err := s.resolver.Episodes(release)
+		if err == nil || !s.Scraper {
+			return episodes, err
+		}
+	} else if !s.Scraper {
+		return nil, err
+	}
+	episode, err := s.Client.EpisodeScraper(name)
+	if err != nil {
+		return nil, err
+	}
+	if episode == nil {
+		return nil, fmt.Errorf("%w for %s", errNoEpisodeFound, name)
+	}
+	return []bsclient.Episode{*episode}, nil
+}
+
+// isUnidentified returns true if the error returned by Identify means that the
+// file matches no episode, as opposed to a failure of the API.
+func isUnidentified(err error) bool {
+	return bsclient.IsNotParsed(err) || bsclient.IsNotFound(err) || errors.Is(err, errNoEpisodeFound)
+}
+
+// showFolder guesses the show title from the folders of a file.
+func showFolder(path string) string {
+	dir := filepath.Dir(path)
+	base := filepath.Base(dir)
+	if strings.HasPrefix(strings.ToLower(base), "season") || strings.HasPrefix(strings.ToLower(base), "saison") {
+		base = filepath.Base(filepath.Dir(dir))
+	}
+	return base
+}
+
+func newEntry(path string, episodes []bsclient.Episode) Entry {
+	entry := Entry{Path: path}
+	for _, episode := range episodes {
+		entry.Episodes = append(entry.Episodes, episode.ID)
+	}
+	if len(episodes) > 0 {
+		entry.Show = episodes[0].Show.Title
+		entry.Code = episodes[0].Code
+	}
+	return entry
+}
+
+func (s *Scanner) markDownloaded(ids []int, downloaded bool) error {
+	if s.DryRun {
+		return nil
+	}
+	for _, id := range ids {
+		var err error
+		if downloaded {
+			_, err = s.Client.EpisodeDownloaded(bsclient.EpisodeID(id))
+		} else {
+			_, err = s.Client.EpisodeNotDownloaded(bsclient.EpisodeID(id))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Scan synchronizes the account with the video files found under the given
+// directories: new files are marked as downloaded and files that disappeared
+// since the previous scan are marked as not downloaded.
+func (s *Scanner) Scan(dirs ...string) (*Report, error) {
+	extensions := s.Extensions
+	if len(extensions) == 0 {
+		extensions = VideoExtensions
+	}
+	state, err := LoadState(s.StateFile)
+	if err != nil {
+		return nil, err
+	}
+	files, failed := walkVideos(dirs, extensions)
+	report := &Report{
+		Scanned: len(files),
+		Errors:  map[string]string{},
+	}
+	unreadable := []string{}
+	for path, err := range failed {
+		report.Errors[path] = err.Error()
+		unreadable = append(unreadable, path)
+	}
+	prefix := ""
+	if s.DryRun {
+		prefix = "[dry-run] "
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	present := map[int]bool{}
+	// changed files which no longer match their previous episodes
+	stale := []string{}
+	for _, path := range paths {
+		info := files[path]
+		known := state.Files[path]
+		if known != nil && known.Size == info.Size() && known.ModTime.Equal(info.ModTime()) {
+			report.Unchanged++
+			for _, id := range known.Episodes {
+				present[id] = true
+			}
+			continue
+		}
+		episodes, err := s.Identify(path)
+		if err != nil && !isUnidentified(err) {
+			report.Errors[path] = err.Error()
+			if known != nil {
+				// the file may still hold its previous episodes
+				for _, id := range known.Episodes {
+					present[id] = true
+				}
+			}
+			continue
+		}
+		if err != nil || len(episodes) == 0 {
+			report.Unidentified = append(report.Unidentified, path)
+			if known != nil {
+				stale = append(stale, path)
+			}
+			continue
+		}
+		entry := newEntry(path, episodes)
+		if err := s.markDownloaded(entry.Episodes, true); err != nil {
+			report.Errors[path] = err.Error()
+			continue
+		}
+		for _, id := range entry.Episodes {
+			present[id] = true
+		}
+		s.printf("%sdownloaded: %s %s (%s)\n", prefix, entry.Show, entry.Code, path)
+		report.Downloaded = append(report.Downloaded, entry)
+		state.Files[path] = &FileState{
+			Size:     info.Size(),
+			ModTime:  info.ModTime(),
+			Episodes: entry.Episodes,
+			Code:     entry.Code,
+			Show:     entry.Show,
+		}
+	}
+
+	removed := stale
+	for path := range state.Files {
+		// files under an unreadable directory may still be there
+		if _, ok := files[path]; !ok && inDirs(path, dirs) && !inDirs(path, unreadable) {
+			removed = append(removed, path)
+		}
+	}
+	sort.Strings(removed)
+	for _, path := range removed {
+		known := state.Files[path]
+		ids := []int{}
+		for _, id := range known.Episodes {
+			// another copy of the episode is still on disk
+			if !present[id] {
+				ids = append(ids, id)
+			}
+		}
+		if err := s.markDownloaded(ids, false); err != nil {
+			report.Errors[path] = err.Error()
+			continue
+		}
+		s.printf("%snot downloaded: %s %s (%s)\n", prefix, known.Show, known.Code, path)
+		report.Removed = append(report.Removed, Entry{
+			Path:     path,
+			Show:     known.Show,
+			Code:     known.Code,
+			Episodes: ids,
+		})
+		delete(state.Files, path)
+	}
+
+	for _, path := range report.Unidentified {
+		s.printf("unidentified: %s\n", path)
+	}
+	if s.DryRun || s.StateFile == "" {
+		return report, nil
+	}
+	return report, state.Save(s.StateFile)
+}
+
+// WriteUnidentified writes the list of unidentified files, one per line.
+func (r *Report) WriteUnidentified(w io.Writer) error {
+	for _, path := range r.Unidentified {
+		if _, err := fmt.Fprintln(w, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
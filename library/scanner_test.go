@@ -0,0 +1,171 @@
+package library
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dns-gh/bs-client/bsclient"
+	"github.com/dns-gh/bs-client/internal/apitest"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+// newFakeAPI returns a client of a fake API answering with a body per method
+// and path, and failing the searches of the 'lost' show. The returned
+// function checks that no other endpoint was requested.
+func newFakeAPI(c *C, bodies map[string]string) (*apitest.Server, *bsclient.BetaSeries, func()) {
+	api := apitest.NewServer(bodies)
+	if search, ok := bodies["GET /shows/search"]; ok {
+		api.Handle("GET /shows/search", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("title") == "lost" {
+				apitest.WriteError(w, http.StatusServiceUnavailable, 1001, "Service unavailable.")
+				return
+			}
+			w.Write([]byte(search))
+		})
+	}
+	bs, err := bsclient.NewBetaseriesClient("key", "", "", bsclient.WithBaseURL(api.URL))
+	c.Assert(err, IsNil)
+	return api, bs, func() {
+		c.Check(api.Unexpected(), HasLen, 0)
+		api.Close()
+	}
+}
+
+// writeFiles creates empty files under 'dir'.
+func writeFiles(c *C, dir string, names ...string) {
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		c.Assert(os.MkdirAll(filepath.Dir(path), 0755), IsNil)
+		c.Assert(os.WriteFile(path, nil, 0644), IsNil)
+	}
+}
+
+func (s *MySuite) TestWalkVideos(c *C) {
+	dir := c.MkDir()
+	writeFiles(c, dir,
+		"Show/Season 1/Show.S01E01.mkv",
+		"Show/Season 1/Show.S01E01.fr.srt",
+		"Show/Season 1/Show.S01E02.AVI",
+		"notes.txt")
+	missing := filepath.Join(dir, "missing")
+	files, failed := walkVideos([]string{missing, dir}, VideoExtensions)
+	c.Assert(files, HasLen, 2)
+	c.Assert(failed, HasLen, 1)
+	c.Assert(failed[missing], NotNil)
+	_, ok := files[filepath.Join(dir, "Show/Season 1/Show.S01E02.AVI")]
+	c.Assert(ok, Equals, true)
+
+	c.Assert(showFolder(filepath.Join(dir, "Show/Season 1/S01E01.mkv")), Equals, "Show")
+	c.Assert(inDirs(filepath.Join(dir, "Show/S01E01.mkv"), []string{dir}), Equals, true)
+	c.Assert(inDirs("/elsewhere/S01E01.mkv", []string{dir}), Equals, false)
+	c.Assert(inDirs(filepath.Join(dir, "..hidden/S01E01.mkv"), []string{dir}), Equals, true)
+	c.Assert(inDirs(filepath.Dir(dir), []string{dir}), Equals, false)
+}
+
+func (s *MySuite) TestState(c *C) {
+	path := filepath.Join(c.MkDir(), "state.json")
+	state, err := LoadState(path)
+	c.Assert(err, IsNil)
+	c.Assert(state.Files, HasLen, 0)
+
+	state.Files["/videos/Show.S01E01.mkv"] = &FileState{
+		Size:     42,
+		Episodes: []int{123},
+		Code:     "S01E01",
+		Show:     "Show",
+	}
+	c.Assert(state.Save(path), IsNil)
+
+	loaded, err := LoadState(path)
+	c.Assert(err, IsNil)
+	c.Assert(loaded, DeepEquals, state)
+}
+
+func (s *MySuite) TestScan(c *C) {
+	api, bs, stop := newFakeAPI(c, map[string]string{
+		"GET /shows/search":         `{"shows": [{"id": 1, "title": "Breaking Bad"}]}`,
+		"GET /shows/episodes":       `{"episodes": [{"id": 100, "code": "S05E14", "season": 5, "episode": 14, "show": {"id": 1, "title": "Breaking Bad"}}]}`,
+		"POST /episodes/downloaded": `{"episode": {"id": 100}}`,
+	})
+	defer stop()
+	dir := c.MkDir()
+	writeFiles(c, dir,
+		"Breaking Bad/Season 5/S05E14.mkv",
+		"Lost.S01E01.mkv",
+		"holidays.mkv")
+	missing := filepath.Join(dir, "missing")
+
+	scanner := NewScanner(bs)
+	report, err := scanner.Scan(dir, missing)
+	c.Assert(err, IsNil)
+	c.Assert(report.Scanned, Equals, 3)
+	c.Assert(report.Downloaded, DeepEquals, []Entry{{
+		Path:     filepath.Join(dir, "Breaking Bad/Season 5/S05E14.mkv"),
+		Show:     "Breaking Bad",
+		Code:     "S05E14",
+		Episodes: []int{100},
+	}})
+	// a failing API is not mistaken for an unknown file
+	c.Assert(report.Unidentified, DeepEquals, []string{filepath.Join(dir, "holidays.mkv")})
+	c.Assert(report.Errors, HasLen, 2)
+	c.Assert(report.Errors[filepath.Join(dir, "Lost.S01E01.mkv")], Equals, "Service unavailable.\n")
+	c.Assert(report.Errors[missing], Not(Equals), "")
+	c.Assert(api.Requests(), DeepEquals, []string{
+		"GET /shows/search?nbpp=100&order=popularity&title=breaking+bad",
+		"GET /shows/episodes?id=1",
+		"POST /episodes/downloaded?id=100",
+		"GET /shows/search?nbpp=100&order=popularity&title=lost",
+	})
+}
+
+func (s *MySuite) TestScanChangedFile(c *C) {
+	_, bs, stop := newFakeAPI(c, map[string]string{
+		"GET /shows/search":         `{"shows": [{"id": 1, "title": "Breaking Bad"}]}`,
+		"GET /shows/episodes":       `{"episodes": [{"id": 100, "code": "S05E14", "season": 5, "episode": 14, "show": {"id": 1, "title": "Breaking Bad"}}]}`,
+		"POST /episodes/downloaded": `{"episode": {"id": 100}}`,
+	})
+	defer stop()
+	dir := c.MkDir()
+	writeFiles(c, dir, "Breaking.Bad.S05E14.mkv")
+	path := filepath.Join(dir, "Breaking.Bad.S05E14.mkv")
+
+	scanner := NewScanner(bs)
+	scanner.StateFile = filepath.Join(c.MkDir(), "state.json")
+	report, err := scanner.Scan(dir)
+	c.Assert(err, IsNil)
+	c.Assert(report.Downloaded, HasLen, 1)
+
+	// the file is replaced by a video matching no show anymore
+	api, bs, stop := newFakeAPI(c, map[string]string{
+		"GET /shows/search":           `{"shows": []}`,
+		"DELETE /episodes/downloaded": `{"episode": {"id": 100}}`,
+	})
+	defer stop()
+	c.Assert(os.WriteFile(path, []byte("other"), 0644), IsNil)
+	scanner.Client = bs
+	scanner.resolver = nil
+	report, err = scanner.Scan(dir)
+	c.Assert(err, IsNil)
+	c.Assert(report.Unidentified, DeepEquals, []string{path})
+	c.Assert(report.Removed, DeepEquals, []Entry{{
+		Path:     path,
+		Show:     "Breaking Bad",
+		Code:     "S05E14",
+		Episodes: []int{100},
+	}})
+	c.Assert(api.Requests(), DeepEquals, []string{
+		"GET /shows/search?nbpp=100&order=popularity&title=breaking+bad",
+		"DELETE /episodes/downloaded?id=100",
+	})
+	state, err := LoadState(scanner.StateFile)
+	c.Assert(err, IsNil)
+	c.Assert(state.Files, HasLen, 0)
+}
@@ -0,0 +1,76 @@
+package library
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const stateVersion = 1
+
+// FileState represents what is known about a video file from a previous scan.
+type FileState struct {
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"mod_time"`
+	Episodes []int     `json:"episodes"`
+	Code     string    `json:"code"`
+	Show     string    `json:"show"`
+}
+
+// State represents the local state file of a library, making rescans incremental:
+// files unchanged since the last scan are neither identified nor marked again.
+type State struct {
+	Version int                   `json:"version"`
+	Files   map[string]*FileState `json:"files"`
+}
+
+// NewState returns an empty state.
+func NewState() *State {
+	return &State{
+		Version: stateVersion,
+		Files:   map[string]*FileState{},
+	}
+}
+
+// LoadState reads a state file. An empty state is returned if it does not exist.
+func LoadState(path string) (*State, error) {
+	state := NewState()
+	if path == "" {
+		return state, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Files == nil {
+		state.Files = map[string]*FileState{}
+	}
+	return state, nil
+}
+
+// Save writes the state file atomically.
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
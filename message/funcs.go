@@ -0,0 +1,230 @@
+package message
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// URLLength is the length counted for every URL, whatever its real length,
+// like Twitter does when shortening links.
+const URLLength = 23
+
+const ellipsis = "…"
+
+var reURL = regexp.MustCompile(`https?://\S+`)
+
+// Code returns the code of an episode, like 'S01E02'.
+func Code(season, episode int) string {
+	return fmt.Sprintf("S%02dE%02d", season, episode)
+}
+
+// Hashtag returns a hashtag made of the words of a title,
+// like '#BreakingBad', or an empty string if the title has no letter.
+func Hashtag(title string) string {
+	b := strings.Builder{}
+	letter := false
+	for _, word := range strings.FieldsFunc(title, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-' || r == '_' || r == '/'
+	}) {
+		first := true
+		for _, r := range word {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				continue
+			}
+			if first {
+				r = unicode.ToUpper(r)
+				first = false
+			}
+			letter = letter || unicode.IsLetter(r)
+			b.WriteRune(r)
+		}
+	}
+	if !letter {
+		return ""
+	}
+	return "#" + b.String()
+}
+
+// Length returns the length of a message in characters, every URL counting for URLLength.
+func Length(text string) int {
+	length := 0
+	last := 0
+	for _, loc := range reURL.FindAllStringIndex(text, -1) {
+		length += utf8.RuneCountInString(text[last:loc[0]]) + URLLength
+		last = loc[1]
+	}
+	return length + utf8.RuneCountInString(text[last:])
+}
+
+// cut returns the beginning of a text fitting in 'size' characters, including
+// the ellipsis, cut at a word boundary when possible.
+func cut(text string, size int) string {
+	if size <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(text) <= size {
+		return text
+	}
+	runes := []rune(text)
+	out := string(runes[:size-1])
+	if unicode.IsSpace(runes[size-1]) {
+		return out + ellipsis
+	}
+	if i := strings.LastIndexFunc(out, unicode.IsSpace); i > len(out)/2 {
+		out = out[:i]
+	}
+	return strings.TrimRightFunc(out, unicode.IsSpace) + ellipsis
+}
+
+// Truncate shortens a message to 'limit' characters as counted by Length.
+// URLs are never cut: the text before, between and after them is kept
+// while it fits, the first text which does not fit is cut with an ellipsis,
+// and the remaining text is dropped.
+func Truncate(limit int, text string) string {
+	if limit <= 0 || Length(text) <= limit {
+		return text
+	}
+	locs := reURL.FindAllStringIndex(text, -1)
+	budget := limit - len(locs)*URLLength
+	b := strings.Builder{}
+	last := 0
+	truncated := false
+	for i := 0; i <= len(locs); i++ {
+		end := len(text)
+		if i < len(locs) {
+			end = locs[i][0]
+		}
+		segment := text[last:end]
+		if truncated {
+			// keep a separator before the next URL
+			segment = ""
+			if i < len(locs) && b.Len() > 0 {
+				segment = " "
+			}
+		} else if n := utf8.RuneCountInString(segment); n > budget {
+			if i < len(locs) {
+				segment = cut(segment, budget-1) + " "
+			} else {
+				segment = cut(segment, budget)
+			}
+			truncated = true
+		}
+		budget -= utf8.RuneCountInString(segment)
+		b.WriteString(segment)
+		if i < len(locs) {
+			b.WriteString(text[locs[i][0]:locs[i][1]])
+			last = locs[i][1]
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// units of the relative times, from the largest.
+var units = []struct {
+	duration time.Duration
+	names    map[string][2]string // singular and plural per language
+}{
+	{365 * 24 * time.Hour, map[string][2]string{EN: {"year", "years"}, FR: {"an", "ans"}}},
+	{30 * 24 * time.Hour, map[string][2]string{EN: {"month", "months"}, FR: {"mois", "mois"}}},
+	{7 * 24 * time.Hour, map[string][2]string{EN: {"week", "weeks"}, FR: {"semaine", "semaines"}}},
+	{24 * time.Hour, map[string][2]string{EN: {"day", "days"}, FR: {"jour", "jours"}}},
+	{time.Hour, map[string][2]string{EN: {"hour", "hours"}, FR: {"heure", "heures"}}},
+	{time.Minute, map[string][2]string{EN: {"minute", "minutes"}, FR: {"minute", "minutes"}}},
+}
+
+var relativeWords = map[string]struct {
+	now, today, tomorrow, yesterday, future, past string
+}{
+	EN: {"now", "today", "tomorrow", "yesterday", "in %s", "%s ago"},
+	FR: {"maintenant", "aujourd'hui", "demain", "hier", "dans %s", "il y a %s"},
+}
+
+func isMidnight(t time.Time) bool {
+	hour, min, sec := t.Clock()
+	return hour == 0 && min == 0 && sec == 0
+}
+
+// Relative returns the time 't' relatively to 'now' in the given language,
+// like 'in 3 hours' or 'yesterday'. Dates without a time of day are
+// compared in days.
+func Relative(lang string, t, now time.Time) string {
+	words, ok := relativeWords[lang]
+	if !ok {
+		lang, words = EN, relativeWords[EN]
+	}
+	d := t.Sub(now)
+	if isMidnight(t) {
+		y, m, day := now.In(t.Location()).Date()
+		days := int(t.Sub(time.Date(y, m, day, 0, 0, 0, 0, t.Location())).Hours() / 24)
+		switch days {
+		case 0:
+			return words.today
+		case 1:
+			return words.tomorrow
+		case -1:
+			return words.yesterday
+		}
+		d = time.Duration(days) * 24 * time.Hour
+	}
+	format := words.future
+	if d < 0 {
+		d, format = -d, words.past
+	}
+	for _, unit := range units {
+		if n := int(d / unit.duration); n >= 1 {
+			name := unit.names[lang][0]
+			if n > 1 {
+				name = unit.names[lang][1]
+			}
+			return fmt.Sprintf(format, fmt.Sprintf("%d %s", n, name))
+		}
+	}
+	return words.now
+}
+
+var statuses = map[string]map[string]string{
+	FR: {"Continuing": "en cours", "Ended": "terminée"},
+}
+
+// Status returns the status of a show in the given language.
+func Status(lang, status string) string {
+	if localized, ok := statuses[lang][status]; ok {
+		return localized
+	}
+	return strings.ToLower(status)
+}
+
+// Funcs returns the template functions of the given language, 'now'
+// giving the reference time of relative times:
+//
+//	code      {{code .Season .Episode}}  S01E02
+//	relative  {{relative .Date}}         in 3 hours
+//	hashtag   {{hashtag .Show.Title}}    #BreakingBad
+//	future    {{if future .Date}}        true if the time is after now
+//	status    {{status .Status}}         ended
+//	truncate  {{truncate 50 .Title}}     cut with an ellipsis, URLs kept
+//	upper, lower, join
+func Funcs(lang string, now func() time.Time) template.FuncMap {
+	return template.FuncMap{
+		"code":    Code,
+		"hashtag": Hashtag,
+		"relative": func(t time.Time) string {
+			return Relative(lang, t, now())
+		},
+		"future": func(t time.Time) bool {
+			return t.After(now())
+		},
+		"status": func(status string) string {
+			return Status(lang, status)
+		},
+		"truncate": Truncate,
+		"upper":    strings.ToUpper,
+		"lower":    strings.ToLower,
+		"join":     strings.Join,
+	}
+}
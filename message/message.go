@@ -0,0 +1,129 @@
+// Package message formats betaseries episodes, shows, news and subtitles
+// into short messages, like tweets or chat messages, with text/template.
+package message
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+// Languages of the default templates.
+const (
+	EN = "en"
+	FR = "fr"
+)
+
+// Kinds of formatted values.
+const (
+	KindEpisode  = "episode"
+	KindShow     = "show"
+	KindNews     = "news"
+	KindSubtitle = "subtitle"
+)
+
+// TweetLimit is the maximum length of a tweet.
+const TweetLimit = 280
+
+var (
+	errUnknownLanguage = errors.New("unknown language")
+	errUnknownKind     = errors.New("unknown kind of value")
+	errUnsupportedType = errors.New("unsupported type of value")
+)
+
+// Formatter formats values with a template per kind.
+type Formatter struct {
+	// Limit is the maximum length of the messages as counted by Length,
+	// longer messages are truncated. There is no limit if 0.
+	Limit int
+	// Now returns the reference time of relative times, time.Now if nil.
+	Now func() time.Time
+
+	lang      string
+	templates map[string]*template.Template
+}
+
+// NewFormatter returns a formatter using the default templates of the given language.
+func NewFormatter(lang string) (*Formatter, error) {
+	defaults, ok := Templates[lang]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", errUnknownLanguage, lang)
+	}
+	f := &Formatter{
+		lang:      lang,
+		templates: map[string]*template.Template{},
+	}
+	for kind, text := range defaults {
+		if err := f.SetTemplate(kind, text); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// Lang returns the language of the formatter.
+func (f *Formatter) Lang() string {
+	return f.lang
+}
+
+func (f *Formatter) now() time.Time {
+	if f.Now == nil {
+		return time.Now()
+	}
+	return f.Now()
+}
+
+// SetTemplate replaces the template of a kind of value. The template
+// can use the functions returned by Funcs.
+func (f *Formatter) SetTemplate(kind, text string) error {
+	if _, ok := Templates[EN][kind]; !ok {
+		return fmt.Errorf("%w: %q", errUnknownKind, kind)
+	}
+	tmpl, err := template.New(kind).Funcs(Funcs(f.lang, f.now)).Parse(text)
+	if err != nil {
+		return err
+	}
+	f.templates[kind] = tmpl
+	return nil
+}
+
+// clean removes the spaces left around empty template values.
+func clean(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Format formats a value with the template of its kind.
+func (f *Formatter) Format(kind string, value interface{}) (string, error) {
+	tmpl, ok := f.templates[kind]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", errUnknownKind, kind)
+	}
+	b := strings.Builder{}
+	if err := tmpl.Execute(&b, value); err != nil {
+		return "", err
+	}
+	return Truncate(f.Limit, clean(b.String())), nil
+}
+
+// Message formats a bsclient Episode, Show, News or Subtitle, or a pointer to one of them.
+func (f *Formatter) Message(value interface{}) (string, error) {
+	switch value.(type) {
+	case bsclient.Episode, *bsclient.Episode:
+		return f.Format(KindEpisode, value)
+	case bsclient.Show, *bsclient.Show:
+		return f.Format(KindShow, value)
+	case bsclient.News, *bsclient.News:
+		return f.Format(KindNews, value)
+	case bsclient.Subtitle, *bsclient.Subtitle:
+		return f.Format(KindSubtitle, value)
+	}
+	return "", fmt.Errorf("%w: %T", errUnsupportedType, value)
+}
@@ -0,0 +1,123 @@
+package message
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dns-gh/bs-client/bsclient"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+func (s *MySuite) TestHelpers(c *C) {
+	c.Assert(Code(1, 2), Equals, "S01E02")
+	c.Assert(Code(12, 103), Equals, "S12E103")
+	c.Assert(Hashtag("Breaking Bad"), Equals, "#BreakingBad")
+	c.Assert(Hashtag("Marvel's Agents of S.H.I.E.L.D."), Equals, "#MarvelsAgentsOfSHIELD")
+	c.Assert(Hashtag("Le Bureau des légendes"), Equals, "#LeBureauDesLégendes")
+	c.Assert(Hashtag("24"), Equals, "")
+	c.Assert(Status(EN, "Ended"), Equals, "ended")
+	c.Assert(Status(FR, "Continuing"), Equals, "en cours")
+}
+
+func (s *MySuite) TestTruncate(c *C) {
+	url := "https://www.betaseries.com/news/a-very-long-url-to-a-news-article"
+	c.Assert(Length("abc "+url), Equals, 4+URLLength)
+	c.Assert(Length("é"), Equals, 1)
+
+	c.Assert(Truncate(0, "no limit"), Equals, "no limit")
+	c.Assert(Truncate(20, "short"), Equals, "short")
+	c.Assert(Truncate(12, "one two three four"), Equals, "one two…")
+
+	// the URL is kept whole and the text before it is cut
+	text := "Breaking Bad is back for a sixth season " + url
+	out := Truncate(40, text)
+	c.Assert(out, Equals, "Breaking Bad is…"+" "+url)
+	c.Assert(Length(out) <= 40, Equals, true)
+
+	// the text after a cut is dropped, but not the URLs
+	out = Truncate(60, "first part of the text "+url+" second part of the text "+url)
+	c.Assert(strings.Count(out, url), Equals, 2)
+	c.Assert(strings.Contains(out, "second"), Equals, false)
+	c.Assert(Length(out) <= 60, Equals, true)
+}
+
+func (s *MySuite) TestRelative(c *C) {
+	now := time.Date(2016, 3, 10, 12, 0, 0, 0, time.UTC)
+	c.Assert(Relative(EN, now.Add(30*time.Second), now), Equals, "now")
+	c.Assert(Relative(EN, now.Add(time.Minute), now), Equals, "in 1 minute")
+	c.Assert(Relative(EN, now.Add(3*time.Hour), now), Equals, "in 3 hours")
+	c.Assert(Relative(EN, now.Add(-50*time.Hour), now), Equals, "2 days ago")
+	c.Assert(Relative(FR, now.Add(3*time.Hour), now), Equals, "dans 3 heures")
+	c.Assert(Relative(FR, now.Add(-400*24*time.Hour), now), Equals, "il y a 1 an")
+	c.Assert(Relative("de", now.Add(3*time.Hour), now), Equals, "in 3 hours")
+
+	// dates without a time of day
+	day := time.Date(2016, 3, 10, 0, 0, 0, 0, time.UTC)
+	c.Assert(Relative(EN, day, now), Equals, "today")
+	c.Assert(Relative(EN, day.AddDate(0, 0, 1), now), Equals, "tomorrow")
+	c.Assert(Relative(FR, day.AddDate(0, 0, -1), now), Equals, "hier")
+	c.Assert(Relative(FR, day.AddDate(0, 0, 14), now), Equals, "dans 2 semaines")
+}
+
+func (s *MySuite) TestFormatter(c *C) {
+	now := time.Date(2016, 3, 10, 12, 0, 0, 0, time.UTC)
+	episode := bsclient.Episode{Title: "Pilot", Season: 1, Episode: 1, Date: time.Date(2016, 3, 11, 0, 0, 0, 0, time.UTC)}
+	episode.Show.Title = "Breaking Bad"
+	show := &bsclient.Show{Title: "Breaking Bad", Network: "AMC", Creation: 2008, Seasons: 5, Status: "Ended"}
+	news := bsclient.News{Title: "A news", URL: "https://www.betaseries.com/news/1"}
+	subtitle := bsclient.Subtitle{Language: "vf", File: "pilot.srt", URL: "https://www.betaseries.com/srt/1"}
+	subtitle.Episode.Season = 1
+	subtitle.Episode.Episode = 1
+
+	_, err := NewFormatter("de")
+	c.Assert(err, ErrorMatches, `unknown language: "de"`)
+
+	en, err := NewFormatter(EN)
+	c.Assert(err, IsNil)
+	en.Now = func() time.Time { return now }
+	expected := []struct {
+		value   interface{}
+		message string
+	}{
+		{episode, `Breaking Bad S01E01 "Pilot" airs tomorrow #BreakingBad`},
+		{show, `Breaking Bad (AMC, 2008): ended, 5 seasons #BreakingBad`},
+		{news, `A news https://www.betaseries.com/news/1`},
+		{subtitle, `New VF subtitles for S01E01: pilot.srt https://www.betaseries.com/srt/1`},
+	}
+	for _, e := range expected {
+		out, err := en.Message(e.value)
+		c.Assert(err, IsNil)
+		c.Assert(out, Equals, e.message)
+	}
+
+	fr, err := NewFormatter(FR)
+	c.Assert(err, IsNil)
+	fr.Now = func() time.Time { return now.AddDate(0, 0, 3) }
+	out, err := fr.Message(&episode)
+	c.Assert(err, IsNil)
+	c.Assert(out, Equals, `Breaking Bad S01E01 « Pilot » diffusé il y a 2 jours #BreakingBad`)
+	out, err = fr.Message(show)
+	c.Assert(err, IsNil)
+	c.Assert(out, Equals, `Breaking Bad (AMC, 2008) : terminée, 5 saisons #BreakingBad`)
+
+	// custom template, empty values and limit
+	c.Assert(en.SetTemplate(KindShow, `{{.Title}} {{hashtag .Network}} {{truncate 5 .Description}}`), IsNil)
+	out, err = en.Message(bsclient.Show{Title: "24", Description: "Jack Bauer"})
+	c.Assert(err, IsNil)
+	c.Assert(out, Equals, "24 Jack…")
+	en.Limit = 10
+	out, err = en.Message(bsclient.Show{Title: "The Wire", Network: "HBO"})
+	c.Assert(err, IsNil)
+	c.Assert(out, Equals, "The Wire…")
+
+	c.Assert(en.SetTemplate("movie", ""), ErrorMatches, `unknown kind of value: "movie"`)
+	_, err = en.Message(42)
+	c.Assert(err, ErrorMatches, "unsupported type of value: int")
+}
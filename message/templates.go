@@ -0,0 +1,23 @@
+package message
+
+// Templates are the default templates per language and kind of value.
+var Templates = map[string]map[string]string{
+	EN: {
+		KindEpisode: `{{.Show.Title}} {{code .Season .Episode}} "{{.Title}}" ` +
+			`{{if future .Date}}airs{{else}}aired{{end}} {{relative .Date}} {{hashtag .Show.Title}}`,
+		KindShow: `{{.Title}} ({{.Network}}, {{.Creation}}): {{status .Status}}, ` +
+			`{{.Seasons}} season{{if gt .Seasons 1}}s{{end}} {{hashtag .Title}}`,
+		KindNews: `{{.Title}} {{.URL}}`,
+		KindSubtitle: `New {{upper .Language}} subtitles for {{code .Episode.Season .Episode.Episode}}: ` +
+			`{{.File}} {{.URL}}`,
+	},
+	FR: {
+		KindEpisode: `{{.Show.Title}} {{code .Season .Episode}} « {{.Title}} » ` +
+			`{{if future .Date}}sera diffusé{{else}}diffusé{{end}} {{relative .Date}} {{hashtag .Show.Title}}`,
+		KindShow: `{{.Title}} ({{.Network}}, {{.Creation}}) : {{status .Status}}, ` +
+			`{{.Seasons}} saison{{if gt .Seasons 1}}s{{end}} {{hashtag .Title}}`,
+		KindNews: `{{.Title}} {{.URL}}`,
+		KindSubtitle: `Nouveaux sous-titres {{upper .Language}} pour {{code .Episode.Season .Episode.Episode}} : ` +
+			`{{.File}} {{.URL}}`,
+	},
+}
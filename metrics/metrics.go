@@ -0,0 +1,125 @@
+// Package metrics records Prometheus metrics of the requests sent by
+// betaseries clients, and serves them for scraping.
+//
+//	m := metrics.New()
+//	bs, err := bsclient.NewBetaseriesClient(key, login, password, m.Option())
+//	http.Handle("/metrics", m.Handler())
+//
+// The client sends each request once and never retries by itself,
+// so there is no retry counter.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/dns-gh/bs-client/bsclient"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "betaseries_client"
+
+// headerCache is the header set by a bs-proxy on its responses: HIT for
+// cached responses, SHARED for responses shared with an identical request
+// in flight and MISS for fetched ones.
+const headerCache = "X-Cache"
+
+// Error code labels of the errors without a betaseries error code.
+const (
+	CodeTransport = "transport" // no response was received
+	CodeHTTP      = "http"      // the response did not hold an API error
+)
+
+// Metrics holds the collectors of the requests sent to the API.
+type Metrics struct {
+	// Requests counts the requests by endpoint, method and status class.
+	Requests *prometheus.CounterVec
+	// Durations observes the durations of the requests by endpoint, method and status class.
+	Durations *prometheus.HistogramVec
+	// Errors counts the failed requests by endpoint and betaseries error code.
+	Errors *prometheus.CounterVec
+	// CacheHits counts the responses served by a bs-proxy cache by endpoint.
+	CacheHits *prometheus.CounterVec
+
+	registry *prometheus.Registry
+}
+
+// New returns metrics registered in their own registry.
+func New() *Metrics {
+	labels := []string{"endpoint", "method", "status"}
+	m := &Metrics{
+		Requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Requests sent to the betaseries API.",
+		}, labels),
+		Durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Durations of the requests sent to the betaseries API.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+		Errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "errors_total",
+			Help:      "Failed requests by betaseries error code.",
+		}, []string{"endpoint", "code"}),
+		CacheHits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_hits_total",
+			Help:      "Responses served by the cache of a bs-proxy.",
+		}, []string{"endpoint"}),
+		registry: prometheus.NewRegistry(),
+	}
+	m.registry.MustRegister(m.Requests, m.Durations, m.Errors, m.CacheHits)
+	return m
+}
+
+// Registry returns the registry of the metrics, to add other collectors.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler returns the handler serving the metrics, usually on /metrics.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Option returns the client option recording the requests of a client.
+func (m *Metrics) Option() bsclient.ClientOption {
+	return bsclient.WithObserver(m.Observe)
+}
+
+// statusClass returns the class of an HTTP status, like '2xx', or 'none' without response.
+func statusClass(status int) string {
+	if status == 0 {
+		return "none"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
+
+// errorCode returns the label of the error of a request.
+func errorCode(o bsclient.Observation) string {
+	if code := bsclient.ErrorCode(o.Err); code != 0 {
+		return strconv.Itoa(code)
+	}
+	if o.Status == 0 {
+		return CodeTransport
+	}
+	return CodeHTTP
+}
+
+// Observe records a request.
+func (m *Metrics) Observe(o bsclient.Observation) {
+	status := statusClass(o.Status)
+	m.Requests.WithLabelValues(o.Endpoint, o.Method, status).Inc()
+	m.Durations.WithLabelValues(o.Endpoint, o.Method, status).Observe(o.Duration.Seconds())
+	if o.Err != nil {
+		m.Errors.WithLabelValues(o.Endpoint, errorCode(o)).Inc()
+	}
+	switch o.Header.Get(headerCache) {
+	case "HIT", "SHARED":
+		m.CacheHits.WithLabelValues(o.Endpoint).Inc()
+	}
+}
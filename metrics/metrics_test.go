@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dns-gh/bs-client/bsclient"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+func (s *MySuite) TestObserve(c *C) {
+	m := New()
+	m.Observe(bsclient.Observation{Method: "GET", Endpoint: "/shows/display", Status: 200, Duration: time.Second})
+	m.Observe(bsclient.Observation{Method: "GET", Endpoint: "/shows/display", Status: 200,
+		Header: http.Header{"X-Cache": {"HIT"}}})
+	m.Observe(bsclient.Observation{Method: "GET", Endpoint: "/shows/display", Status: 502, Err: errors.New("bad gateway")})
+	m.Observe(bsclient.Observation{Method: "POST", Endpoint: "/episodes/watched", Err: errors.New("timeout")})
+
+	c.Assert(testutil.ToFloat64(m.Requests.WithLabelValues("/shows/display", "GET", "2xx")), Equals, 2.0)
+	c.Assert(testutil.ToFloat64(m.Requests.WithLabelValues("/shows/display", "GET", "5xx")), Equals, 1.0)
+	c.Assert(testutil.ToFloat64(m.Requests.WithLabelValues("/episodes/watched", "POST", "none")), Equals, 1.0)
+	c.Assert(testutil.ToFloat64(m.Errors.WithLabelValues("/shows/display", CodeHTTP)), Equals, 1.0)
+	c.Assert(testutil.ToFloat64(m.Errors.WithLabelValues("/episodes/watched", CodeTransport)), Equals, 1.0)
+	c.Assert(testutil.ToFloat64(m.CacheHits.WithLabelValues("/shows/display")), Equals, 1.0)
+	c.Assert(testutil.CollectAndCount(m.Durations), Equals, 3)
+}
+
+func (s *MySuite) TestClient(c *C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/shows/display" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors": [{"code": 4001, "text": "Show not found."}]}`))
+			return
+		}
+		w.Write([]byte(`{"shows": [{"id": 1}]}`))
+	}))
+	defer server.Close()
+	m := New()
+	bs, err := bsclient.NewBetaseriesClient("key", "", "", bsclient.WithBaseURL(server.URL+"/"), m.Option())
+	c.Assert(err, IsNil)
+	_, err = bs.ShowsList(nil)
+	c.Assert(err, IsNil)
+	_, err = bs.ShowDisplay(bsclient.ShowID(1))
+	c.Assert(err, NotNil)
+
+	c.Assert(testutil.ToFloat64(m.Requests.WithLabelValues("/shows/list", "GET", "2xx")), Equals, 1.0)
+	c.Assert(testutil.ToFloat64(m.Errors.WithLabelValues("/shows/display", "4001")), Equals, 1.0)
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, err := io.ReadAll(w.Body)
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(string(body),
+		`betaseries_client_requests_total{endpoint="/shows/list",method="GET",status="2xx"} 1`), Equals, true)
+	c.Assert(strings.Contains(string(body),
+		`betaseries_client_errors_total{code="4001",endpoint="/shows/display"} 1`), Equals, true)
+}
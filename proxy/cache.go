@@ -0,0 +1,94 @@
+package proxy
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// response represents a response of the upstream API.
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// entry represents a cached response.
+type entry struct {
+	key     string
+	token   string
+	expires time.Time
+	resp    *response
+}
+
+// cache is a LRU cache of responses, safe for concurrent use.
+type cache struct {
+	mutex      sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List // most recently used first
+}
+
+func newCache(maxEntries int) *cache {
+	return &cache{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+	}
+}
+
+// get returns the response cached for 'key' if it has not expired.
+func (c *cache) get(key string, now time.Time) *response {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	e := element.Value.(*entry)
+	if !now.Before(e.expires) {
+		c.remove(element)
+		return nil
+	}
+	c.lru.MoveToFront(element)
+	return e.resp
+}
+
+// add caches a response until 'expires', removing the least recently used
+// response if the cache is full.
+func (c *cache) add(key, token string, resp *response, expires time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+	c.entries[key] = c.lru.PushFront(&entry{key: key, token: token, expires: expires, resp: resp})
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+// invalidate removes the responses cached for a token.
+func (c *cache) invalidate(token string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for element := c.lru.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(*entry).token == token {
+			c.remove(element)
+		}
+		element = next
+	}
+}
+
+func (c *cache) remove(element *list.Element) {
+	delete(c.entries, element.Value.(*entry).key)
+	c.lru.Remove(element)
+}
+
+func (c *cache) len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.lru.Len()
+}
@@ -0,0 +1,294 @@
+// Package proxy implements a caching reverse proxy of the betaseries API,
+// so that several services share one API key and one request quota.
+// Clients only change their base URL, see bsclient.WithBaseURL.
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultUpstream is the URL of the betaseries API.
+	DefaultUpstream = "https://api.betaseries.com"
+	// DefaultTTL is the duration GET responses are cached.
+	DefaultTTL = 5 * time.Minute
+	// DefaultMaxEntries is the maximum number of cached responses.
+	DefaultMaxEntries = 10000
+	// maxBodySize is the maximum size of the upstream responses.
+	maxBodySize = 32 << 20
+)
+
+// Headers of the betaseries API.
+const (
+	headerKey     = "X-BetaSeries-Key"
+	headerToken   = "X-BetaSeries-Token"
+	headerVersion = "X-BetaSeries-Version"
+	// HeaderCache tells whether a response was served from the cache (HIT),
+	// shared with an identical request in flight (SHARED) or fetched (MISS).
+	HeaderCache = "X-Cache"
+)
+
+// hopHeaders are the headers which are not forwarded.
+var hopHeaders = []string{
+	"Connection", "Keep-Alive", "Proxy-Authenticate", "Proxy-Authorization",
+	"Te", "Trailer", "Transfer-Encoding", "Upgrade", "Content-Length",
+}
+
+// Stats counts the requests handled by a proxy.
+type Stats struct {
+	Hits     int64 // served from the cache
+	Shared   int64 // shared with an identical request in flight
+	Upstream int64 // forwarded to the API
+	Rejected int64 // rejected because of the quota
+	Errors   int64 // failed to reach the API
+}
+
+// call represents an upstream request in flight.
+type call struct {
+	done chan struct{}
+	resp *response
+	err  error
+}
+
+// Proxy is an http.Handler forwarding requests to the betaseries API with
+// a shared API key. GET responses are cached per user token, identical
+// requests in flight are only sent once, and upstream requests are limited
+// to Quota per QuotaPeriod. Other requests invalidate the cache of their token.
+type Proxy struct {
+	// Key is the API key sent upstream, replacing the one of the clients.
+	Key string
+	// Upstream defaults to DefaultUpstream.
+	Upstream string
+	// Client defaults to http.DefaultClient.
+	Client *http.Client
+	// TTL defaults to DefaultTTL.
+	TTL time.Duration
+	// MaxEntries defaults to DefaultMaxEntries.
+	MaxEntries int
+	// Quota is the maximum number of upstream requests per QuotaPeriod,
+	// there is no limit if 0.
+	Quota       int
+	QuotaPeriod time.Duration
+
+	once     sync.Once
+	cache    *cache
+	quota    *quota
+	mutex    sync.Mutex
+	inflight map[string]*call
+	stats    Stats
+	now      func() time.Time
+}
+
+// New creates a proxy forwarding requests with the given API key.
+func New(key string) *Proxy {
+	return &Proxy{Key: key}
+}
+
+func (p *Proxy) init() {
+	p.once.Do(func() {
+		if p.Upstream == "" {
+			p.Upstream = DefaultUpstream
+		}
+		p.Upstream = strings.TrimSuffix(p.Upstream, "/")
+		if p.Client == nil {
+			p.Client = http.DefaultClient
+		}
+		if p.TTL <= 0 {
+			p.TTL = DefaultTTL
+		}
+		if p.MaxEntries <= 0 {
+			p.MaxEntries = DefaultMaxEntries
+		}
+		if p.QuotaPeriod <= 0 {
+			p.QuotaPeriod = time.Hour
+		}
+		if p.now == nil {
+			p.now = time.Now
+		}
+		p.cache = newCache(p.MaxEntries)
+		p.quota = &quota{limit: p.Quota, period: p.QuotaPeriod}
+		p.inflight = map[string]*call{}
+	})
+}
+
+// Stats returns the counters of the proxy.
+func (p *Proxy) Stats() Stats {
+	return Stats{
+		Hits:     atomic.LoadInt64(&p.stats.Hits),
+		Shared:   atomic.LoadInt64(&p.stats.Shared),
+		Upstream: atomic.LoadInt64(&p.stats.Upstream),
+		Rejected: atomic.LoadInt64(&p.stats.Rejected),
+		Errors:   atomic.LoadInt64(&p.stats.Errors),
+	}
+}
+
+// CacheLen returns the number of cached responses.
+func (p *Proxy) CacheLen() int {
+	p.init()
+	return p.cache.len()
+}
+
+// token returns the user token of a request, if any.
+func token(r *http.Request) string {
+	if token := r.Header.Get(headerToken); token != "" {
+		return token
+	}
+	return r.URL.Query().Get("token")
+}
+
+// cacheKey identifies the GET requests returning the same response.
+func cacheKey(r *http.Request) string {
+	q := r.URL.Query()
+	q.Del("key")
+	return strings.Join([]string{token(r), r.Header.Get(headerVersion), r.URL.Path, q.Encode()}, "\n")
+}
+
+// writeError writes an error in the format of the API.
+func writeError(w http.ResponseWriter, status int, text string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]string{{"text": text}},
+	})
+}
+
+func writeResponse(w http.ResponseWriter, resp *response, cache string) {
+	for name, values := range resp.header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(HeaderCache, cache)
+	w.WriteHeader(resp.status)
+	w.Write(resp.body)
+}
+
+// ServeHTTP forwards a request to the API.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.init()
+	if r.Method != http.MethodGet {
+		resp, err := p.forward(r.Context(), r)
+		if err != nil {
+			p.fail(w, err)
+			return
+		}
+		if resp.status < 300 {
+			p.cache.invalidate(token(r))
+		}
+		writeResponse(w, resp, "MISS")
+		return
+	}
+
+	key := cacheKey(r)
+	if resp := p.cache.get(key, p.now()); resp != nil {
+		atomic.AddInt64(&p.stats.Hits, 1)
+		writeResponse(w, resp, "HIT")
+		return
+	}
+	p.mutex.Lock()
+	c, shared := p.inflight[key]
+	if !shared {
+		c = &call{done: make(chan struct{})}
+		p.inflight[key] = c
+	}
+	p.mutex.Unlock()
+
+	if shared {
+		atomic.AddInt64(&p.stats.Shared, 1)
+		select {
+		case <-c.done:
+		case <-r.Context().Done():
+			return
+		}
+		if c.err != nil {
+			p.fail(w, c.err)
+			return
+		}
+		writeResponse(w, c.resp, "SHARED")
+		return
+	}
+
+	// the request is not cancelled with its client, as others may wait for it
+	c.resp, c.err = p.forward(context.WithoutCancel(r.Context()), r)
+	if c.err == nil && c.resp.status == http.StatusOK {
+		p.cache.add(key, token(r), c.resp, p.now().Add(p.TTL))
+	}
+	p.mutex.Lock()
+	delete(p.inflight, key)
+	p.mutex.Unlock()
+	close(c.done)
+	if c.err != nil {
+		p.fail(w, c.err)
+		return
+	}
+	writeResponse(w, c.resp, "MISS")
+}
+
+// errQuota is returned when the quota of upstream requests is reached.
+type errQuota struct {
+	retry time.Duration
+}
+
+func (e *errQuota) Error() string {
+	return fmt.Sprintf("quota of requests reached, retry in %v", e.retry.Round(time.Second))
+}
+
+func (p *Proxy) fail(w http.ResponseWriter, err error) {
+	if quota, ok := err.(*errQuota); ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(quota.retry.Seconds()))))
+		writeError(w, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	writeError(w, http.StatusBadGateway, err.Error())
+}
+
+// forward sends a request upstream, within the quota.
+func (p *Proxy) forward(ctx context.Context, r *http.Request) (*response, error) {
+	if ok, retry := p.quota.take(p.now()); !ok {
+		atomic.AddInt64(&p.stats.Rejected, 1)
+		return nil, &errQuota{retry: retry}
+	}
+	atomic.AddInt64(&p.stats.Upstream, 1)
+
+	q := r.URL.Query()
+	q.Del("key")
+	u := p.Upstream + r.URL.Path
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, r.Method, u, r.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = r.Header.Clone()
+	for _, name := range hopHeaders {
+		req.Header.Del(name)
+	}
+	req.Header.Set(headerKey, p.Key)
+	req.ContentLength = r.ContentLength
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		atomic.AddInt64(&p.stats.Errors, 1)
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		atomic.AddInt64(&p.stats.Errors, 1)
+		return nil, err
+	}
+	header := resp.Header.Clone()
+	for _, name := range append(hopHeaders, "Set-Cookie") {
+		header.Del(name)
+	}
+	return &response{status: resp.StatusCode, header: header, body: body}, nil
+}
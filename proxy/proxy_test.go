@@ -0,0 +1,208 @@
+package proxy
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dns-gh/bs-client/bsclient"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+// upstream is a fake API answering the token and the path of the requests.
+type upstream struct {
+	server   *httptest.Server
+	requests int64
+	block    chan struct{} // if set, requests wait for it to be closed
+}
+
+func newUpstream(c *C) *upstream {
+	u := &upstream{}
+	u.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&u.requests, 1)
+		c.Check(r.Header.Get("X-BetaSeries-Key"), Equals, "shared")
+		c.Check(r.URL.Query().Get("key"), Equals, "")
+		if u.block != nil {
+			<-u.block
+		}
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"news": [{"id": "%s %s %s"}]}`,
+			r.Method, r.Header.Get("X-BetaSeries-Token"), r.URL.RequestURI())
+	}))
+	return u
+}
+
+func get(c *C, server *httptest.Server, path, token string) (int, string, string) {
+	return do(c, server, http.MethodGet, path, token)
+}
+
+func do(c *C, server *httptest.Server, method, path, token string) (int, string, string) {
+	req, err := http.NewRequest(method, server.URL+path, nil)
+	c.Assert(err, IsNil)
+	req.Header.Set("X-BetaSeries-Key", "client")
+	if token != "" {
+		req.Header.Set("X-BetaSeries-Token", token)
+	}
+	resp, err := server.Client().Do(req)
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	c.Assert(err, IsNil)
+	return resp.StatusCode, resp.Header.Get(HeaderCache), string(body)
+}
+
+func newProxy(u *upstream) (*Proxy, *httptest.Server) {
+	p := New("shared")
+	p.Upstream = u.server.URL
+	return p, httptest.NewServer(p)
+}
+
+func (s *MySuite) TestCache(c *C) {
+	u := newUpstream(c)
+	defer u.server.Close()
+	p, server := newProxy(u)
+	defer server.Close()
+	now := time.Now()
+	p.now = func() time.Time { return now }
+
+	status, cache, body := get(c, server, "/news/last?number=2&key=client", "a")
+	c.Assert(status, Equals, http.StatusOK)
+	c.Assert(cache, Equals, "MISS")
+	c.Assert(body, Equals, `{"news": [{"id": "GET a /news/last?number=2"}]}`)
+	_, cache, body2 := get(c, server, "/news/last?number=2", "a")
+	c.Assert(cache, Equals, "HIT")
+	c.Assert(body2, Equals, body)
+
+	// responses are cached per token
+	_, cache, body = get(c, server, "/news/last?number=2", "b")
+	c.Assert(cache, Equals, "MISS")
+	c.Assert(body, Equals, `{"news": [{"id": "GET b /news/last?number=2"}]}`)
+	c.Assert(atomic.LoadInt64(&u.requests), Equals, int64(2))
+
+	// errors are not cached
+	status, _, _ = get(c, server, "/missing", "a")
+	c.Assert(status, Equals, http.StatusNotFound)
+	_, cache, _ = get(c, server, "/missing", "a")
+	c.Assert(cache, Equals, "MISS")
+
+	// other methods invalidate the cache of their token
+	_, cache, _ = do(c, server, http.MethodPost, "/episodes/watched?id=1", "a")
+	c.Assert(cache, Equals, "MISS")
+	_, cache, _ = get(c, server, "/news/last?number=2", "a")
+	c.Assert(cache, Equals, "MISS")
+	_, cache, _ = get(c, server, "/news/last?number=2", "b")
+	c.Assert(cache, Equals, "HIT")
+
+	now = now.Add(DefaultTTL)
+	_, cache, _ = get(c, server, "/news/last?number=2", "b")
+	c.Assert(cache, Equals, "MISS")
+	c.Assert(p.Stats(), Equals, Stats{Hits: 2, Upstream: 7})
+}
+
+func (s *MySuite) TestCacheEviction(c *C) {
+	cache := newCache(2)
+	now := time.Now()
+	resp := &response{status: http.StatusOK}
+	cache.add("1", "a", resp, now.Add(time.Hour))
+	cache.add("2", "a", resp, now.Add(time.Hour))
+	c.Assert(cache.get("1", now), Equals, resp)
+	cache.add("3", "b", resp, now.Add(time.Hour))
+	c.Assert(cache.get("2", now), IsNil)
+	c.Assert(cache.get("1", now), Equals, resp)
+	cache.invalidate("a")
+	c.Assert(cache.len(), Equals, 1)
+	c.Assert(cache.get("3", now.Add(time.Hour)), IsNil)
+	c.Assert(cache.len(), Equals, 0)
+}
+
+func (s *MySuite) TestCoalescing(c *C) {
+	u := newUpstream(c)
+	u.block = make(chan struct{})
+	defer u.server.Close()
+	p, server := newProxy(u)
+	defer server.Close()
+
+	const n = 5
+	wg := sync.WaitGroup{}
+	caches := make(chan string, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, cache, body := get(c, server, "/shows/display?id=1", "a")
+			c.Check(body, Equals, `{"news": [{"id": "GET a /shows/display?id=1"}]}`)
+			caches <- cache
+		}()
+	}
+	for p.Stats().Shared < n-1 {
+		time.Sleep(time.Millisecond)
+	}
+	close(u.block)
+	wg.Wait()
+	close(caches)
+	counts := map[string]int{}
+	for cache := range caches {
+		counts[cache]++
+	}
+	c.Assert(counts, DeepEquals, map[string]int{"MISS": 1, "SHARED": n - 1})
+	c.Assert(atomic.LoadInt64(&u.requests), Equals, int64(1))
+}
+
+func (s *MySuite) TestQuota(c *C) {
+	u := newUpstream(c)
+	defer u.server.Close()
+	p, server := newProxy(u)
+	defer server.Close()
+	p.Quota = 1
+	p.QuotaPeriod = time.Minute
+	now := time.Now()
+	p.now = func() time.Time { return now }
+
+	status, _, _ := get(c, server, "/news/last", "a")
+	c.Assert(status, Equals, http.StatusOK)
+	// cached responses do not count
+	status, _, _ = get(c, server, "/news/last", "a")
+	c.Assert(status, Equals, http.StatusOK)
+
+	now = now.Add(30 * time.Second)
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/news/last", nil)
+	resp, err := server.Client().Do(req)
+	c.Assert(err, IsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusTooManyRequests)
+	c.Assert(resp.Header.Get("Retry-After"), Equals, "30")
+
+	now = now.Add(30 * time.Second)
+	status, _, _ = get(c, server, "/news/last", "b")
+	c.Assert(status, Equals, http.StatusOK)
+	c.Assert(p.Stats().Rejected, Equals, int64(1))
+}
+
+func (s *MySuite) TestClient(c *C) {
+	u := newUpstream(c)
+	defer u.server.Close()
+	_, server := newProxy(u)
+	defer server.Close()
+
+	bs, err := bsclient.NewBetaseriesClient("client", "", "", bsclient.WithBaseURL(server.URL+"/"))
+	c.Assert(err, IsNil)
+	news, err := bs.NewsLast(nil)
+	c.Assert(err, IsNil)
+	c.Assert(news, HasLen, 1)
+	c.Assert(news[0].ID, Equals, "GET  /news/last?tailored=false")
+}
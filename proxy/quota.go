@@ -0,0 +1,34 @@
+package proxy
+
+import (
+	"sync"
+	"time"
+)
+
+// quota limits the number of upstream requests per period, safe for concurrent use.
+type quota struct {
+	mutex  sync.Mutex
+	limit  int
+	period time.Duration
+	start  time.Time
+	used   int
+}
+
+// take counts a request and returns true if it is allowed. Otherwise, it
+// returns the duration before the next period.
+func (q *quota) take(now time.Time) (bool, time.Duration) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.limit <= 0 {
+		return true, 0
+	}
+	if now.Sub(q.start) >= q.period {
+		q.start = now
+		q.used = 0
+	}
+	if q.used >= q.limit {
+		return false, q.start.Add(q.period).Sub(now)
+	}
+	q.used++
+	return true, 0
+}
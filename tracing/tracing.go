@@ -0,0 +1,120 @@
+// Package tracing traces the requests sent by betaseries clients with
+// OpenTelemetry. Every request produces a client span named after its endpoint,
+// child of the span of the context the client is bound to, and the trace is
+// propagated to the API in the request headers.
+//
+//	t := tracing.New(nil)
+//	bs, err := bsclient.NewBetaseriesClient(key, login, password, t.Option())
+//	episode, err := bs.WithContext(ctx).EpisodeWatched(bsclient.EpisodeID(id), nil)
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/dns-gh/bs-client/bsclient"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the spans.
+const ScopeName = "github.com/dns-gh/bs-client/tracing"
+
+// Attribute keys of the spans.
+const (
+	AttrMethod    = attribute.Key("http.request.method")
+	AttrStatus    = attribute.Key("http.response.status_code")
+	AttrErrorCode = attribute.Key("betaseries.error.code")
+)
+
+// episodeEndpoints are the endpoints whose 'id' parameter is an episode id,
+// other endpoints taking a show id but the members and friends ones.
+var episodeEndpoints = map[string]bool{
+	"/episodes/display":    true,
+	"/episodes/watched":    true,
+	"/episodes/downloaded": true,
+	"/episodes/note":       true,
+	"/subtitles/episode":   true,
+}
+
+// Tracer traces the requests of clients.
+type Tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// New returns a tracer creating spans with the given provider, the global one if nil.
+// The trace is propagated with the global propagator.
+func New(provider trace.TracerProvider) *Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return &Tracer{
+		tracer:     provider.Tracer(ScopeName),
+		propagator: otel.GetTextMapPropagator(),
+	}
+}
+
+// Option returns the client option tracing the requests of a client.
+func (t *Tracer) Option() bsclient.ClientOption {
+	return bsclient.WithInterceptor(t.Intercept)
+}
+
+// kind returns the kind of the object referenced by the 'id' parameter of an endpoint.
+func kind(endpoint string) string {
+	switch {
+	case episodeEndpoints[endpoint]:
+		return "episode"
+	case strings.HasPrefix(endpoint, "/members/"), strings.HasPrefix(endpoint, "/friends/"):
+		return "member"
+	}
+	return "show"
+}
+
+// idAttributes returns the attributes of the ids of a request query, like betaseries.show.id.
+func idAttributes(endpoint string, q url.Values) []attribute.KeyValue {
+	prefix := "betaseries." + kind(endpoint) + "."
+	params := []struct{ param, key string }{
+		{"id", prefix + "id"},
+		{"thetvdb_id", prefix + "tvdb_id"},
+		{"imdb_id", prefix + "imdb_id"},
+		{"tmdb_id", prefix + "tmdb_id"},
+		{"episode_id", "betaseries.episode.id"},
+		{"showId", "betaseries.show.id"},
+		{"userId", "betaseries.member.id"},
+	}
+	attrs := []attribute.KeyValue{}
+	for _, p := range params {
+		if value := q.Get(p.param); value != "" {
+			attrs = append(attrs, attribute.String(p.key, value))
+		}
+	}
+	return attrs
+}
+
+// Intercept starts the span of a request and injects its context in the
+// request headers. The span ends with the returned observer.
+func (t *Tracer) Intercept(req *http.Request, endpoint string) (context.Context, bsclient.Observer) {
+	attrs := append([]attribute.KeyValue{AttrMethod.String(req.Method)}, idAttributes(endpoint, req.URL.Query())...)
+	ctx, span := t.tracer.Start(req.Context(), endpoint,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	return ctx, func(o bsclient.Observation) {
+		if o.Status != 0 {
+			span.SetAttributes(AttrStatus.Int(o.Status))
+		}
+		if o.Err != nil {
+			if code := bsclient.ErrorCode(o.Err); code != 0 {
+				span.SetAttributes(AttrErrorCode.Int(code))
+			}
+			span.RecordError(o.Err)
+			span.SetStatus(codes.Error, strings.TrimSpace(o.Err.Error()))
+		}
+		span.End()
+	}
+}
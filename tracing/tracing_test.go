@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/dns-gh/bs-client/bsclient"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+func (s *MySuite) TestIDAttributes(c *C) {
+	c.Assert(idAttributes("/shows/display", url.Values{"id": {"1"}}), DeepEquals,
+		[]attribute.KeyValue{attribute.String("betaseries.show.id", "1")})
+	c.Assert(idAttributes("/episodes/watched", url.Values{"thetvdb_id": {"2"}}), DeepEquals,
+		[]attribute.KeyValue{attribute.String("betaseries.episode.tvdb_id", "2")})
+	c.Assert(idAttributes("/episodes/next", url.Values{"id": {"3"}}), DeepEquals,
+		[]attribute.KeyValue{attribute.String("betaseries.show.id", "3")})
+	c.Assert(idAttributes("/members/infos", url.Values{"id": {"4"}}), DeepEquals,
+		[]attribute.KeyValue{attribute.String("betaseries.member.id", "4")})
+	c.Assert(idAttributes("/shows/list", url.Values{"order": {"title"}}), HasLen, 0)
+}
+
+// attributes returns the attributes of a span by key.
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, attr := range span.Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+	return attrs
+}
+
+func (s *MySuite) TestSpans(c *C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Header.Get("traceparent"), Not(Equals), "")
+		if r.URL.Path == "/episodes/watched" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors": [{"code": 4001, "text": "Episode not found."}]}`))
+			return
+		}
+		w.Write([]byte(`{"show": {"id": 1}}`))
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t := New(provider)
+	t.propagator = propagation.TraceContext{}
+	bs, err := bsclient.NewBetaseriesClient("key", "", "", bsclient.WithBaseURL(server.URL), t.Option())
+	c.Assert(err, IsNil)
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "bot request")
+	bs = bs.WithContext(ctx)
+	_, err = bs.ShowDisplay(bsclient.ShowID(1))
+	c.Assert(err, IsNil)
+	_, err = bs.EpisodeWatched(bsclient.EpisodeID(2), nil)
+	c.Assert(err, NotNil)
+	parent.End()
+
+	spans := recorder.Ended()
+	c.Assert(spans, HasLen, 3)
+	display, watched := spans[0], spans[1]
+	c.Assert(display.Name(), Equals, "/shows/display")
+	c.Assert(display.Parent().SpanID(), Equals, parent.SpanContext().SpanID())
+	c.Assert(display.SpanContext().TraceID(), Equals, parent.SpanContext().TraceID())
+	c.Assert(attributes(display), DeepEquals, map[attribute.Key]attribute.Value{
+		AttrMethod:           attribute.StringValue("GET"),
+		AttrStatus:           attribute.IntValue(200),
+		"betaseries.show.id": attribute.StringValue("1"),
+	})
+	c.Assert(display.Status().Code, Equals, codes.Unset)
+
+	c.Assert(watched.Name(), Equals, "/episodes/watched")
+	c.Assert(attributes(watched), DeepEquals, map[attribute.Key]attribute.Value{
+		AttrMethod:              attribute.StringValue("POST"),
+		AttrStatus:              attribute.IntValue(400),
+		AttrErrorCode:           attribute.IntValue(4001),
+		"betaseries.episode.id": attribute.StringValue("2"),
+	})
+	c.Assert(watched.Status().Code, Equals, codes.Error)
+	c.Assert(watched.Status().Description, Equals, "Episode not found.")
+}
@@ -0,0 +1,92 @@
+package trakt
+
+import (
+	"context"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+// exporter is the part of bsclient.BetaSeries used by Export.
+type exporter interface {
+	MembersInfos(opts *bsclient.MembersInfosOptions) (*bsclient.Member, error)
+	ShowsEpisodes(show bsclient.ShowRef, opts *bsclient.ShowsEpisodesOptions) ([]bsclient.Episode, error)
+}
+
+func traktShow(show *bsclient.Show) Show {
+	return Show{
+		Title: show.Title,
+		Year:  show.Creation,
+		IDs: IDs{
+			TVDB: show.ThetvdbID,
+			IMDB: show.ImdbID,
+		},
+	}
+}
+
+// Export converts the account of the identified member to a Trakt history:
+// the seen episodes of its shows, its notes of shows and episodes, its
+// favorites, and the shows without any seen episode as the watchlist.
+func Export(ctx context.Context, bs *bsclient.BetaSeries) (*History, error) {
+	return export(bs.WithContext(ctx))
+}
+
+func export(bs exporter) (*History, error) {
+	member, err := bs.MembersInfos(&bsclient.MembersInfosOptions{Only: "shows"})
+	if err != nil {
+		return nil, err
+	}
+	h := &History{}
+	for i := range member.Shows {
+		show := &member.Shows[i]
+		tshow := traktShow(show)
+		if show.User.Favorited {
+			h.Favorites = append(h.Favorites, ListItem{Type: TypeShow, Show: tshow})
+		}
+		if show.Notes.User > 0 {
+			h.Ratings = append(h.Ratings, Rating{
+				Rating: ratingFromNote(show.Notes.User),
+				Type:   TypeShow,
+				Show:   tshow,
+			})
+		}
+		episodes, err := bs.ShowsEpisodes(show.Ref(), nil)
+		if err != nil && !bsclient.IsNotFound(err) {
+			return nil, err
+		}
+		watched := WatchedShow{Show: tshow}
+		seasons := map[int]int{} // index of the seasons in watched
+		for _, episode := range episodes {
+			if episode.Note.User > 0 {
+				h.Ratings = append(h.Ratings, Rating{
+					Rating: ratingFromNote(episode.Note.User),
+					Type:   TypeEpisode,
+					Show:   tshow,
+					Episode: &Episode{
+						Season: episode.Season,
+						Number: episode.Episode,
+						Title:  episode.Title,
+						IDs:    IDs{TVDB: episode.ThetvdbID},
+					},
+				})
+			}
+			if !episode.User.Seen {
+				continue
+			}
+			i, ok := seasons[episode.Season]
+			if !ok {
+				i = len(watched.Seasons)
+				seasons[episode.Season] = i
+				watched.Seasons = append(watched.Seasons, WatchedSeason{Number: episode.Season})
+			}
+			watched.Seasons[i].Episodes = append(watched.Seasons[i].Episodes,
+				WatchedEpisode{Number: episode.Episode, Plays: 1})
+			watched.Plays++
+		}
+		if watched.Plays > 0 {
+			h.Watched = append(h.Watched, watched)
+		} else {
+			h.Watchlist = append(h.Watchlist, ListItem{Type: TypeShow, Show: tshow})
+		}
+	}
+	return h, nil
+}
@@ -0,0 +1,286 @@
+package trakt
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+// Reasons of unmatched items.
+const (
+	ReasonNoID            = "no tvdb, imdb or tmdb id"
+	ReasonShowNotFound    = "show not found"
+	ReasonEpisodeNotFound = "episode not found"
+)
+
+// importer is the part of bsclient.BetaSeries used by Import.
+type importer interface {
+	ShowDisplay(show bsclient.ShowRef) (*bsclient.Show, error)
+	ShowsEpisodes(show bsclient.ShowRef, opts *bsclient.ShowsEpisodesOptions) ([]bsclient.Episode, error)
+	ShowAdd(show bsclient.ShowRef, opts *bsclient.ShowAddOptions) (*bsclient.Show, error)
+	ShowNote(show bsclient.ShowRef, note int) (*bsclient.Show, error)
+	ShowFavorite(show bsclient.ShowRef) (*bsclient.Show, error)
+	EpisodeWatched(episode bsclient.EpisodeRef, opts *bsclient.EpisodeWatchedOptions) (*bsclient.Episode, error)
+	EpisodeNote(episode bsclient.EpisodeRef, note int) (*bsclient.Episode, error)
+}
+
+// Unmatched represents a Trakt item which could not be found on betaseries.
+type Unmatched struct {
+	Type   string `json:"type"`
+	Show   Show   `json:"show"`
+	Season int    `json:"season,omitempty"`
+	Number int    `json:"number,omitempty"`
+	Reason string `json:"reason"`
+}
+
+func (u Unmatched) String() string {
+	if u.Type == TypeEpisode {
+		return fmt.Sprintf("%s S%02dE%02d: %s", u.Show.Title, u.Season, u.Number, u.Reason)
+	}
+	return fmt.Sprintf("%s: %s", u.Show.Title, u.Reason)
+}
+
+// Report represents the changes applied, or to apply in a dry run, by Import.
+type Report struct {
+	ShowsAdded      int         `json:"shows_added"`
+	ShowsNoted      int         `json:"shows_noted"`
+	ShowsFavorited  int         `json:"shows_favorited"`
+	EpisodesWatched int         `json:"episodes_watched"`
+	EpisodesNoted   int         `json:"episodes_noted"`
+	Unmatched       []Unmatched `json:"unmatched"`
+}
+
+// Importer applies a Trakt history to the account of the identified member.
+type Importer struct {
+	Client *bsclient.BetaSeries
+	// DryRun only reports the changes without applying them.
+	DryRun bool
+}
+
+// NewImporter creates an importer.
+func NewImporter(bs *bsclient.BetaSeries) *Importer {
+	return &Importer{Client: bs}
+}
+
+// showRef returns the reference of a Trakt show, by TVDB, IMDB then TMDB id.
+func showRef(show Show) (bsclient.ShowRef, bool) {
+	switch {
+	case show.IDs.TVDB > 0:
+		return bsclient.ShowTVDB(show.IDs.TVDB), true
+	case show.IDs.IMDB != "":
+		return bsclient.ShowIMDB(show.IDs.IMDB), true
+	case show.IDs.TMDB > 0:
+		return bsclient.ShowTMDB(show.IDs.TMDB), true
+	}
+	return bsclient.ShowRef{}, false
+}
+
+// showChanges gathers the changes of a show found in the history.
+type showChanges struct {
+	show     Show
+	add      bool
+	note     int
+	favorite bool
+	watched  map[episodeKey]bool
+	ratings  map[episodeKey]int
+	tvdbIDs  map[int]episodeKey // keys of the rated episodes by TVDB id
+}
+
+// episodeKey is the season and number of an episode.
+type episodeKey [2]int
+
+// collect groups the history by show, in order of appearance. Shows are
+// grouped by ids, or by title and year for the shows without any.
+func collect(h *History) []*showChanges {
+	changes := []*showChanges{}
+	index := map[Show]*showChanges{}
+	get := func(show Show) *showChanges {
+		key := Show{IDs: show.IDs}
+		if show.IDs == (IDs{}) {
+			key = Show{Title: show.Title, Year: show.Year}
+		}
+		c, ok := index[key]
+		if !ok {
+			c = &showChanges{
+				show:    show,
+				watched: map[episodeKey]bool{},
+				ratings: map[episodeKey]int{},
+				tvdbIDs: map[int]episodeKey{},
+			}
+			index[key] = c
+			changes = append(changes, c)
+		}
+		return c
+	}
+	for _, watched := range h.Watched {
+		c := get(watched.Show)
+		c.add = true
+		for _, season := range watched.Seasons {
+			for _, episode := range season.Episodes {
+				c.watched[episodeKey{season.Number, episode.Number}] = true
+			}
+		}
+	}
+	for _, rating := range h.Ratings {
+		c := get(rating.Show)
+		c.add = true
+		if rating.Type != TypeEpisode || rating.Episode == nil {
+			c.note = noteFromRating(rating.Rating)
+			continue
+		}
+		key := episodeKey{rating.Episode.Season, rating.Episode.Number}
+		c.ratings[key] = noteFromRating(rating.Rating)
+		if rating.Episode.IDs.TVDB > 0 {
+			c.tvdbIDs[rating.Episode.IDs.TVDB] = key
+		}
+	}
+	for _, item := range h.Watchlist {
+		get(item.Show).add = true
+	}
+	for _, item := range h.Favorites {
+		c := get(item.Show)
+		c.add = true
+		c.favorite = true
+	}
+	return changes
+}
+
+// Import adds the shows of the history to the account, marks their episodes
+// as watched, and sets the notes and favorites. Shows and episodes are matched
+// by TVDB or IMDB ids, the ones which could not be matched are reported.
+func (i *Importer) Import(ctx context.Context, h *History) (*Report, error) {
+	return i.apply(i.Client.WithContext(ctx), h)
+}
+
+func (i *Importer) apply(bs importer, h *History) (*Report, error) {
+	report := &Report{}
+	for _, c := range collect(h) {
+		if err := i.applyShow(bs, c, report); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+func (i *Importer) applyShow(bs importer, c *showChanges, report *Report) error {
+	ref, ok := showRef(c.show)
+	if !ok {
+		report.Unmatched = append(report.Unmatched, Unmatched{Type: TypeShow, Show: c.show, Reason: ReasonNoID})
+		return nil
+	}
+	// the API answers an error for unknown ids: lookup errors are reported
+	// rather than stopping the import, unlike the errors of the changes.
+	show, err := bs.ShowDisplay(ref)
+	if err != nil || show == nil {
+		reason := ReasonShowNotFound
+		if err != nil && !bsclient.IsNotFound(err) {
+			reason = strings.TrimSpace(err.Error())
+		}
+		report.Unmatched = append(report.Unmatched, Unmatched{Type: TypeShow, Show: c.show, Reason: reason})
+		return nil
+	}
+	id := bsclient.ShowID(show.ID)
+
+	if c.add && !show.InAccount {
+		report.ShowsAdded++
+		if !i.DryRun {
+			if _, err := bs.ShowAdd(id, nil); err != nil {
+				return err
+			}
+		}
+	}
+	if c.note > 0 && c.note != show.Notes.User {
+		report.ShowsNoted++
+		if !i.DryRun {
+			if _, err := bs.ShowNote(id, c.note); err != nil {
+				return err
+			}
+		}
+	}
+	if c.favorite && !show.User.Favorited {
+		report.ShowsFavorited++
+		if !i.DryRun {
+			if _, err := bs.ShowFavorite(id); err != nil {
+				return err
+			}
+		}
+	}
+	if len(c.watched) == 0 && len(c.ratings) == 0 {
+		return nil
+	}
+
+	episodes, err := bs.ShowsEpisodes(id, nil)
+	if err != nil && !bsclient.IsNotFound(err) {
+		return err
+	}
+	found := map[episodeKey]bool{}
+	for _, episode := range episodes {
+		key := episodeKey{episode.Season, episode.Episode}
+		ratingKey := key
+		if k, ok := c.tvdbIDs[episode.ThetvdbID]; ok && episode.ThetvdbID > 0 {
+			ratingKey = k
+		}
+		note, rated := c.ratings[ratingKey]
+		if rated {
+			found[ratingKey] = true
+			rated = note != episode.Note.User
+		}
+		watched := c.watched[key]
+		if watched {
+			found[key] = true
+			watched = !episode.User.Seen
+		}
+		switch {
+		case watched:
+			// the note is set along with the watched flag
+			report.EpisodesWatched++
+			if i.DryRun {
+				continue
+			}
+			opts := &bsclient.EpisodeWatchedOptions{Bulk: bsclient.Bool(false)}
+			if rated {
+				opts.Note = bsclient.Int(note)
+			}
+			if _, err := bs.EpisodeWatched(bsclient.EpisodeID(episode.ID), opts); err != nil {
+				return err
+			}
+		case rated:
+			// rated episodes are not marked as watched if not in the history
+			report.EpisodesNoted++
+			if i.DryRun {
+				continue
+			}
+			if _, err := bs.EpisodeNote(bsclient.EpisodeID(episode.ID), note); err != nil {
+				return err
+			}
+		}
+	}
+
+	missing := []episodeKey{}
+	for key := range c.watched {
+		missing = append(missing, key)
+	}
+	for key := range c.ratings {
+		if !c.watched[key] {
+			missing = append(missing, key)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		return missing[i][0] < missing[j][0] || missing[i][0] == missing[j][0] && missing[i][1] < missing[j][1]
+	})
+	for _, key := range missing {
+		if !found[key] {
+			report.Unmatched = append(report.Unmatched, Unmatched{
+				Type:   TypeEpisode,
+				Show:   c.show,
+				Season: key[0],
+				Number: key[1],
+				Reason: ReasonEpisodeNotFound,
+			})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+// Package trakt converts between a betaseries account and the JSON export
+// format of Trakt, so that users can migrate from one tracker to the other.
+package trakt
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Types of rated and listed items.
+const (
+	TypeShow    = "show"
+	TypeEpisode = "episode"
+)
+
+// Files of a Trakt export directory.
+const (
+	WatchedFile        = "watched-shows.json"
+	ShowRatingsFile    = "ratings-shows.json"
+	EpisodeRatingsFile = "ratings-episodes.json"
+	WatchlistFile      = "watchlist-shows.json"
+	FavoritesFile      = "favorites-shows.json"
+)
+
+// IDs represents the identifiers of a Trakt item.
+type IDs struct {
+	Trakt int    `json:"trakt,omitempty"`
+	Slug  string `json:"slug,omitempty"`
+	TVDB  int    `json:"tvdb,omitempty"`
+	IMDB  string `json:"imdb,omitempty"`
+	TMDB  int    `json:"tmdb,omitempty"`
+}
+
+// Show represents a Trakt show.
+type Show struct {
+	Title string `json:"title"`
+	Year  int    `json:"year,omitempty"`
+	IDs   IDs    `json:"ids"`
+}
+
+// Episode represents a Trakt episode.
+type Episode struct {
+	Season int    `json:"season"`
+	Number int    `json:"number"`
+	Title  string `json:"title,omitempty"`
+	IDs    IDs    `json:"ids"`
+}
+
+// WatchedEpisode represents a watched episode of a season.
+type WatchedEpisode struct {
+	Number        int        `json:"number"`
+	Plays         int        `json:"plays"`
+	LastWatchedAt *time.Time `json:"last_watched_at,omitempty"`
+}
+
+// WatchedSeason represents the watched episodes of a season.
+type WatchedSeason struct {
+	Number   int              `json:"number"`
+	Episodes []WatchedEpisode `json:"episodes"`
+}
+
+// WatchedShow represents the watched episodes of a show.
+type WatchedShow struct {
+	Plays         int             `json:"plays"`
+	LastWatchedAt *time.Time      `json:"last_watched_at,omitempty"`
+	Show          Show            `json:"show"`
+	Seasons       []WatchedSeason `json:"seasons"`
+}
+
+// Rating represents the rating, from 1 to 10, of a show or an episode.
+type Rating struct {
+	RatedAt *time.Time `json:"rated_at,omitempty"`
+	Rating  int        `json:"rating"`
+	Type    string     `json:"type"`
+	Show    Show       `json:"show"`
+	Episode *Episode   `json:"episode,omitempty"`
+}
+
+// ListItem represents a show of the watchlist or of the favorites.
+type ListItem struct {
+	ListedAt *time.Time `json:"listed_at,omitempty"`
+	Type     string     `json:"type"`
+	Show     Show       `json:"show"`
+}
+
+// History represents the content of a Trakt export.
+type History struct {
+	Watched   []WatchedShow
+	Ratings   []Rating
+	Watchlist []ListItem
+	Favorites []ListItem
+}
+
+func readFile(path string, data interface{}) error {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, data)
+}
+
+func writeFile(path string, data interface{}) error {
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+// ReadDir reads the files of a Trakt export directory. Missing files are ignored.
+func ReadDir(dir string) (*History, error) {
+	h := &History{}
+	showRatings := []Rating{}
+	episodeRatings := []Rating{}
+	for name, data := range map[string]interface{}{
+		WatchedFile:        &h.Watched,
+		ShowRatingsFile:    &showRatings,
+		EpisodeRatingsFile: &episodeRatings,
+		WatchlistFile:      &h.Watchlist,
+		FavoritesFile:      &h.Favorites,
+	} {
+		if err := readFile(filepath.Join(dir, name), data); err != nil {
+			return nil, err
+		}
+	}
+	h.Ratings = append(showRatings, episodeRatings...)
+	return h, nil
+}
+
+// WriteDir writes the files of a Trakt export in a directory, creating it if needed.
+func (h *History) WriteDir(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	showRatings := []Rating{}
+	episodeRatings := []Rating{}
+	for _, rating := range h.Ratings {
+		if rating.Type == TypeEpisode {
+			episodeRatings = append(episodeRatings, rating)
+		} else {
+			showRatings = append(showRatings, rating)
+		}
+	}
+	for name, data := range map[string]interface{}{
+		WatchedFile:        nonNil(h.Watched),
+		ShowRatingsFile:    showRatings,
+		EpisodeRatingsFile: episodeRatings,
+		WatchlistFile:      nonNil(h.Watchlist),
+		FavoritesFile:      nonNil(h.Favorites),
+	} {
+		if err := writeFile(filepath.Join(dir, name), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nonNil returns an empty slice instead of nil, so that it is written as [].
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+// ratingFromNote converts a betaseries note, from 1 to 5, to a Trakt rating, from 1 to 10.
+func ratingFromNote(note int) int {
+	return note * 2
+}
+
+// noteFromRating converts a Trakt rating, from 1 to 10, to a betaseries note, from 1 to 5.
+func noteFromRating(rating int) int {
+	note := (rating + 1) / 2
+	if note < 1 {
+		return 1
+	} else if note > 5 {
+		return 5
+	}
+	return note
+}
@@ -0,0 +1,214 @@
+package trakt
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dns-gh/bs-client/bsclient"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+// fakeAccount is a betaseries account recording the changes applied to it.
+type fakeAccount struct {
+	shows    []bsclient.Show
+	episodes map[int][]bsclient.Episode
+	calls    []string
+}
+
+func (f *fakeAccount) find(ref bsclient.ShowRef) *bsclient.Show {
+	for i := range f.shows {
+		show := &f.shows[i]
+		if ref.ID > 0 && show.ID == ref.ID || ref.TVDB > 0 && show.ThetvdbID == ref.TVDB ||
+			ref.IMDB != "" && show.ImdbID == ref.IMDB {
+			return show
+		}
+	}
+	return nil
+}
+
+func (f *fakeAccount) MembersInfos(opts *bsclient.MembersInfosOptions) (*bsclient.Member, error) {
+	return &bsclient.Member{Shows: f.shows}, nil
+}
+
+func (f *fakeAccount) ShowsEpisodes(show bsclient.ShowRef, opts *bsclient.ShowsEpisodesOptions) ([]bsclient.Episode, error) {
+	return f.episodes[f.find(show).ID], nil
+}
+
+func (f *fakeAccount) ShowDisplay(ref bsclient.ShowRef) (*bsclient.Show, error) {
+	show := f.find(ref)
+	if show == nil {
+		return nil, fmt.Errorf("show not found\n")
+	}
+	return show, nil
+}
+
+func (f *fakeAccount) ShowAdd(show bsclient.ShowRef, opts *bsclient.ShowAddOptions) (*bsclient.Show, error) {
+	f.calls = append(f.calls, fmt.Sprintf("add %d", show.ID))
+	return nil, nil
+}
+
+func (f *fakeAccount) ShowNote(show bsclient.ShowRef, note int) (*bsclient.Show, error) {
+	f.calls = append(f.calls, fmt.Sprintf("note %d %d", show.ID, note))
+	return nil, nil
+}
+
+func (f *fakeAccount) ShowFavorite(show bsclient.ShowRef) (*bsclient.Show, error) {
+	f.calls = append(f.calls, fmt.Sprintf("favorite %d", show.ID))
+	return nil, nil
+}
+
+func (f *fakeAccount) EpisodeWatched(episode bsclient.EpisodeRef, opts *bsclient.EpisodeWatchedOptions) (*bsclient.Episode, error) {
+	call := fmt.Sprintf("watched %d", episode.ID)
+	if opts.Note != nil {
+		call += fmt.Sprintf(" %d", *opts.Note)
+	}
+	f.calls = append(f.calls, call)
+	return nil, nil
+}
+
+func (f *fakeAccount) EpisodeNote(episode bsclient.EpisodeRef, note int) (*bsclient.Episode, error) {
+	f.calls = append(f.calls, fmt.Sprintf("episode note %d %d", episode.ID, note))
+	return nil, nil
+}
+
+func newEpisode(id, tvdbID, season, number int, seen bool, note int) bsclient.Episode {
+	episode := bsclient.Episode{ID: id, ThetvdbID: tvdbID, Season: season, Episode: number}
+	episode.User.Seen = seen
+	episode.Note.User = note
+	return episode
+}
+
+func (s *MySuite) TestReadWriteDir(c *C) {
+	dir := c.MkDir()
+	h, err := ReadDir(dir)
+	c.Assert(err, IsNil)
+	c.Assert(h.Watched, HasLen, 0)
+
+	bb := Show{Title: "Breaking Bad", Year: 2008, IDs: IDs{TVDB: 81189, IMDB: "tt0903747"}}
+	h = &History{
+		Watched: []WatchedShow{{Plays: 1, Show: bb, Seasons: []WatchedSeason{
+			{Number: 1, Episodes: []WatchedEpisode{{Number: 1, Plays: 1}}},
+		}}},
+		Ratings: []Rating{
+			{Rating: 10, Type: TypeShow, Show: bb},
+			{Rating: 8, Type: TypeEpisode, Show: bb, Episode: &Episode{Season: 1, Number: 1}},
+		},
+		Favorites: []ListItem{{Type: TypeShow, Show: bb}},
+	}
+	c.Assert(h.WriteDir(dir), IsNil)
+	read, err := ReadDir(dir)
+	c.Assert(err, IsNil)
+	h.Watchlist = []ListItem{}
+	c.Assert(read, DeepEquals, h)
+}
+
+func (s *MySuite) TestNotes(c *C) {
+	c.Assert(ratingFromNote(4), Equals, 8)
+	c.Assert(noteFromRating(10), Equals, 5)
+	c.Assert(noteFromRating(7), Equals, 4)
+	c.Assert(noteFromRating(1), Equals, 1)
+	c.Assert(noteFromRating(0), Equals, 1)
+}
+
+func (s *MySuite) TestExport(c *C) {
+	bb := bsclient.Show{ID: 481, ThetvdbID: 81189, ImdbID: "tt0903747", Title: "Breaking Bad", Creation: 2008}
+	bb.User.Favorited = true
+	bb.Notes.User = 5
+	wire := bsclient.Show{ID: 1, ThetvdbID: 79126, Title: "The Wire"}
+	account := &fakeAccount{
+		shows: []bsclient.Show{bb, wire},
+		episodes: map[int][]bsclient.Episode{
+			481: {
+				newEpisode(10, 349232, 1, 1, true, 4),
+				newEpisode(11, 349233, 1, 2, true, 0),
+				newEpisode(20, 438913, 2, 1, true, 0),
+				newEpisode(21, 438914, 2, 2, false, 0),
+			},
+			1: {newEpisode(30, 1, 1, 1, false, 0)},
+		},
+	}
+	h, err := export(account)
+	c.Assert(err, IsNil)
+	tbb := Show{Title: "Breaking Bad", Year: 2008, IDs: IDs{TVDB: 81189, IMDB: "tt0903747"}}
+	c.Assert(h.Watched, DeepEquals, []WatchedShow{{Plays: 3, Show: tbb, Seasons: []WatchedSeason{
+		{Number: 1, Episodes: []WatchedEpisode{{Number: 1, Plays: 1}, {Number: 2, Plays: 1}}},
+		{Number: 2, Episodes: []WatchedEpisode{{Number: 1, Plays: 1}}},
+	}}})
+	c.Assert(h.Ratings, DeepEquals, []Rating{
+		{Rating: 10, Type: TypeShow, Show: tbb},
+		{Rating: 8, Type: TypeEpisode, Show: tbb, Episode: &Episode{Season: 1, Number: 1, IDs: IDs{TVDB: 349232}}},
+	})
+	c.Assert(h.Favorites, DeepEquals, []ListItem{{Type: TypeShow, Show: tbb}})
+	c.Assert(h.Watchlist, DeepEquals, []ListItem{{Type: TypeShow, Show: Show{Title: "The Wire", IDs: IDs{TVDB: 79126}}}})
+}
+
+func (s *MySuite) TestImport(c *C) {
+	bb := bsclient.Show{ID: 481, ThetvdbID: 81189, Title: "Breaking Bad"}
+	wire := bsclient.Show{ID: 1, ImdbID: "tt0306414", Title: "The Wire", InAccount: true}
+	newAccount := func() *fakeAccount {
+		return &fakeAccount{
+			shows: []bsclient.Show{bb, wire},
+			episodes: map[int][]bsclient.Episode{
+				481: {
+					newEpisode(10, 349232, 1, 1, false, 0),
+					newEpisode(11, 349233, 1, 2, true, 0),
+					newEpisode(12, 349234, 1, 3, false, 0),
+				},
+			},
+		}
+	}
+	tbb := Show{Title: "Breaking Bad", IDs: IDs{TVDB: 81189}}
+	h := &History{
+		Watched: []WatchedShow{{Show: tbb, Seasons: []WatchedSeason{
+			{Number: 1, Episodes: []WatchedEpisode{{Number: 1}, {Number: 2}, {Number: 9}}},
+		}}},
+		Ratings: []Rating{
+			{Rating: 9, Type: TypeShow, Show: tbb},
+			// matched by TVDB id rather than by number
+			{Rating: 6, Type: TypeEpisode, Show: tbb, Episode: &Episode{Season: 1, Number: 30, IDs: IDs{TVDB: 349234}}},
+		},
+		Watchlist: []ListItem{
+			{Type: TypeShow, Show: Show{Title: "The Wire", IDs: IDs{IMDB: "tt0306414"}}},
+			{Type: TypeShow, Show: Show{Title: "Unknown", IDs: IDs{TVDB: 1}}},
+			{Type: TypeShow, Show: Show{Title: "No ids"}},
+			{Type: TypeShow, Show: Show{Title: "No ids either"}},
+		},
+		Favorites: []ListItem{{Type: TypeShow, Show: Show{Title: "The Wire", IDs: IDs{IMDB: "tt0306414"}}}},
+	}
+	expected := &Report{
+		ShowsAdded:      1,
+		ShowsNoted:      1,
+		ShowsFavorited:  1,
+		EpisodesWatched: 1,
+		EpisodesNoted:   1,
+		Unmatched: []Unmatched{
+			{Type: TypeEpisode, Show: tbb, Season: 1, Number: 9, Reason: ReasonEpisodeNotFound},
+			{Type: TypeShow, Show: Show{Title: "Unknown", IDs: IDs{TVDB: 1}}, Reason: ReasonShowNotFound},
+			// shows without ids are not merged
+			{Type: TypeShow, Show: Show{Title: "No ids"}, Reason: ReasonNoID},
+			{Type: TypeShow, Show: Show{Title: "No ids either"}, Reason: ReasonNoID},
+		},
+	}
+
+	// a dry run does not change anything
+	account := newAccount()
+	report, err := (&Importer{DryRun: true}).apply(account, h)
+	c.Assert(err, IsNil)
+	c.Assert(report, DeepEquals, expected)
+	c.Assert(account.calls, HasLen, 0)
+
+	account = newAccount()
+	report, err = (&Importer{}).apply(account, h)
+	c.Assert(err, IsNil)
+	c.Assert(report, DeepEquals, expected)
+	c.Assert(account.calls, DeepEquals, []string{
+		"add 481", "note 481 5", "watched 10", "episode note 12 3", "favorite 1",
+	})
+	c.Assert(report.Unmatched[0].String(), Equals, "Breaking Bad S01E09: episode not found")
+}
@@ -0,0 +1,383 @@
+// Package tui implements an interactive terminal interface to the account
+// of a betaseries member: its shows, their seasons and episodes, and its planning.
+package tui
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+// client is the part of bsclient.BetaSeries used by the interface.
+type client interface {
+	MembersInfos(opts *bsclient.MembersInfosOptions) (*bsclient.Member, error)
+	ShowsEpisodes(show bsclient.ShowRef, opts *bsclient.ShowsEpisodesOptions) ([]bsclient.Episode, error)
+	PlanningMember(opts *bsclient.PlanningMemberOptions) ([]bsclient.Episode, error)
+	EpisodeWatched(episode bsclient.EpisodeRef, opts *bsclient.EpisodeWatchedOptions) (*bsclient.Episode, error)
+	EpisodeNotWatched(episode bsclient.EpisodeRef) (*bsclient.Episode, error)
+	EpisodeDownloaded(episode bsclient.EpisodeRef) (*bsclient.Episode, error)
+	EpisodeNotDownloaded(episode bsclient.EpisodeRef) (*bsclient.Episode, error)
+	EpisodeNote(episode bsclient.EpisodeRef, note int) (*bsclient.Episode, error)
+}
+
+// view is a screen of the interface.
+type view int
+
+const (
+	viewShows view = iota
+	viewSeasons
+	viewEpisodes
+	viewPlanning
+)
+
+// messages sent by the commands calling the API.
+type (
+	showsMsg struct {
+		shows []bsclient.Show
+	}
+	episodesMsg struct {
+		showID   int
+		episodes []bsclient.Episode
+	}
+	planningMsg struct {
+		episodes []bsclient.Episode
+	}
+	// updatedMsg is sent once an episode has been changed by 'update'.
+	updatedMsg struct {
+		id     int
+		update func(episode *bsclient.Episode)
+		status string
+	}
+	errMsg struct {
+		err error
+	}
+)
+
+// season represents a season of the current show.
+type season struct {
+	number   int
+	episodes []int // indexes in Model.episodes
+	seen     int
+}
+
+// Model is the bubbletea model of the interface.
+type Model struct {
+	client client
+	now    func() time.Time
+
+	view     view
+	shows    []bsclient.Show
+	showID   int
+	episodes []bsclient.Episode
+	seasons  []season
+	season   int // index in seasons
+	planning []bsclient.Episode
+	cursors  map[view]int
+
+	loading bool
+	status  string
+	width   int
+	height  int
+}
+
+// New returns the model of the interface for the identified member.
+func New(bs *bsclient.BetaSeries) Model {
+	return newModel(bs)
+}
+
+func newModel(c client) Model {
+	return Model{
+		client:  c,
+		now:     time.Now,
+		cursors: map[view]int{},
+		loading: true,
+	}
+}
+
+// NewProgram returns the program running the interface in the terminal
+// until the user quits or the context is cancelled.
+func NewProgram(ctx context.Context, bs *bsclient.BetaSeries) *tea.Program {
+	return tea.NewProgram(New(bs.WithContext(ctx)), tea.WithAltScreen(), tea.WithContext(ctx))
+}
+
+// Init loads the shows of the member.
+func (m Model) Init() tea.Cmd {
+	return m.loadShows
+}
+
+func (m Model) loadShows() tea.Msg {
+	member, err := m.client.MembersInfos(&bsclient.MembersInfosOptions{Only: "shows"})
+	if err != nil {
+		return errMsg{err}
+	}
+	shows := member.Shows
+	sort.SliceStable(shows, func(i, j int) bool {
+		return shows[i].User.Remaining > shows[j].User.Remaining
+	})
+	return showsMsg{shows}
+}
+
+func (m Model) loadEpisodes(showID int) tea.Cmd {
+	return func() tea.Msg {
+		episodes, err := m.client.ShowsEpisodes(bsclient.ShowID(showID), nil)
+		if err != nil && !bsclient.IsNotFound(err) {
+			return errMsg{err}
+		}
+		return episodesMsg{showID: showID, episodes: episodes}
+	}
+}
+
+func (m Model) loadPlanning() tea.Msg {
+	episodes, err := m.client.PlanningMember(nil)
+	if err != nil && !bsclient.IsNotFound(err) {
+		return errMsg{err}
+	}
+	// episode dates are midnights in the API time zone
+	y, month, d := m.now().Date()
+	today := time.Date(y, month, d, 0, 0, 0, 0, bsclient.APILocation)
+	upcoming := []bsclient.Episode{}
+	for _, episode := range episodes {
+		if !episode.Date.Before(today) {
+			upcoming = append(upcoming, episode)
+		}
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return upcoming[i].Date.Before(upcoming[j].Date)
+	})
+	return planningMsg{upcoming}
+}
+
+// updateEpisode returns a command calling the API, then applying 'update' to the episode.
+func (m Model) updateEpisode(episode *bsclient.Episode, status string,
+	call func(ref bsclient.EpisodeRef) error, update func(episode *bsclient.Episode)) tea.Cmd {
+	id := episode.ID
+	return func() tea.Msg {
+		if err := call(bsclient.EpisodeID(id)); err != nil {
+			return errMsg{err}
+		}
+		return updatedMsg{id: id, update: update, status: status}
+	}
+}
+
+// groupSeasons groups the episodes of the current show by season.
+func (m *Model) groupSeasons() {
+	m.seasons = nil
+	index := map[int]int{}
+	for i, episode := range m.episodes {
+		s, ok := index[episode.Season]
+		if !ok {
+			s = len(m.seasons)
+			index[episode.Season] = s
+			m.seasons = append(m.seasons, season{number: episode.Season})
+		}
+		m.seasons[s].episodes = append(m.seasons[s].episodes, i)
+		if episode.User.Seen {
+			m.seasons[s].seen++
+		}
+	}
+	sort.Slice(m.seasons, func(i, j int) bool {
+		return m.seasons[i].number < m.seasons[j].number
+	})
+}
+
+// length returns the number of items of the current view.
+func (m *Model) length() int {
+	switch m.view {
+	case viewShows:
+		return len(m.shows)
+	case viewSeasons:
+		return len(m.seasons)
+	case viewEpisodes:
+		if m.season < len(m.seasons) {
+			return len(m.seasons[m.season].episodes)
+		}
+	case viewPlanning:
+		return len(m.planning)
+	}
+	return 0
+}
+
+// current returns the episode under the cursor, if any.
+func (m *Model) current() *bsclient.Episode {
+	if m.view != viewEpisodes || m.season >= len(m.seasons) {
+		return nil
+	}
+	indexes := m.seasons[m.season].episodes
+	cursor := m.cursors[viewEpisodes]
+	if cursor >= len(indexes) {
+		return nil
+	}
+	return &m.episodes[indexes[cursor]]
+}
+
+// setRemaining changes the remaining episodes of the current show.
+func (m *Model) setRemaining(delta int) {
+	for i := range m.shows {
+		if m.shows[i].ID == m.showID {
+			m.shows[i].User.Remaining += delta
+		}
+	}
+}
+
+// Update handles the keys and the results of the API calls.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case showsMsg:
+		m.loading = false
+		m.shows = msg.shows
+		m.status = ""
+	case episodesMsg:
+		if msg.showID != m.showID {
+			break
+		}
+		m.loading = false
+		m.episodes = msg.episodes
+		m.groupSeasons()
+		m.status = ""
+	case planningMsg:
+		m.loading = false
+		m.planning = msg.episodes
+		m.status = ""
+	case updatedMsg:
+		for i := range m.episodes {
+			if m.episodes[i].ID == msg.id {
+				seen := m.episodes[i].User.Seen
+				msg.update(&m.episodes[i])
+				if seen != m.episodes[i].User.Seen {
+					if seen {
+						m.setRemaining(1)
+					} else {
+						m.setRemaining(-1)
+					}
+				}
+			}
+		}
+		m.groupSeasons()
+		m.status = msg.status
+	case errMsg:
+		m.loading = false
+		m.status = "error: " + msg.err.Error()
+	case tea.KeyMsg:
+		return m.key(msg.String())
+	}
+	return m, nil
+}
+
+func (m Model) key(key string) (tea.Model, tea.Cmd) {
+	cursor := m.cursors[m.view]
+	switch key {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "up", "k":
+		if cursor > 0 {
+			m.cursors[m.view] = cursor - 1
+		}
+	case "down", "j":
+		if cursor < m.length()-1 {
+			m.cursors[m.view] = cursor + 1
+		}
+	case "home", "g":
+		m.cursors[m.view] = 0
+	case "end", "G":
+		if m.length() > 0 {
+			m.cursors[m.view] = m.length() - 1
+		}
+	case "esc", "backspace", "left", "h":
+		switch m.view {
+		case viewSeasons, viewPlanning:
+			m.view = viewShows
+		case viewEpisodes:
+			m.view = viewSeasons
+		}
+	case "enter", "right", "l":
+		return m.enter()
+	case "p":
+		m.view = viewPlanning
+		m.cursors[viewPlanning] = 0
+		m.loading = true
+		return m, m.loadPlanning
+	case "r":
+		m.loading = true
+		switch m.view {
+		case viewShows:
+			return m, m.loadShows
+		case viewPlanning:
+			return m, m.loadPlanning
+		}
+		return m, m.loadEpisodes(m.showID)
+	case "w", "d", "1", "2", "3", "4", "5":
+		if episode := m.current(); episode != nil {
+			return m, m.toggle(episode, key)
+		}
+	}
+	return m, nil
+}
+
+func (m Model) enter() (tea.Model, tea.Cmd) {
+	switch m.view {
+	case viewShows:
+		if len(m.shows) == 0 {
+			break
+		}
+		m.showID = m.shows[m.cursors[viewShows]].ID
+		m.view = viewSeasons
+		m.cursors[viewSeasons] = 0
+		m.episodes, m.seasons = nil, nil
+		m.loading = true
+		return m, m.loadEpisodes(m.showID)
+	case viewSeasons:
+		if len(m.seasons) == 0 {
+			break
+		}
+		m.season = m.cursors[viewSeasons]
+		m.view = viewEpisodes
+		m.cursors[viewEpisodes] = 0
+		// start on the first unseen episode
+		for i, index := range m.seasons[m.season].episodes {
+			if !m.episodes[index].User.Seen {
+				m.cursors[viewEpisodes] = i
+				break
+			}
+		}
+	}
+	return m, nil
+}
+
+// toggle returns the command applying the change of 'key' to an episode.
+func (m Model) toggle(episode *bsclient.Episode, key string) tea.Cmd {
+	code := episode.Code
+	switch key {
+	case "w":
+		if episode.User.Seen {
+			return m.updateEpisode(episode, code+" not watched", func(ref bsclient.EpisodeRef) error {
+				_, err := m.client.EpisodeNotWatched(ref)
+				return err
+			}, func(e *bsclient.Episode) { e.User.Seen = false })
+		}
+		return m.updateEpisode(episode, code+" watched", func(ref bsclient.EpisodeRef) error {
+			_, err := m.client.EpisodeWatched(ref, &bsclient.EpisodeWatchedOptions{Bulk: bsclient.Bool(false)})
+			return err
+		}, func(e *bsclient.Episode) { e.User.Seen = true })
+	case "d":
+		if episode.User.Downloaded {
+			return m.updateEpisode(episode, code+" not downloaded", func(ref bsclient.EpisodeRef) error {
+				_, err := m.client.EpisodeNotDownloaded(ref)
+				return err
+			}, func(e *bsclient.Episode) { e.User.Downloaded = false })
+		}
+		return m.updateEpisode(episode, code+" downloaded", func(ref bsclient.EpisodeRef) error {
+			_, err := m.client.EpisodeDownloaded(ref)
+			return err
+		}, func(e *bsclient.Episode) { e.User.Downloaded = true })
+	}
+	note := int(key[0] - '0')
+	return m.updateEpisode(episode, code+" rated "+key, func(ref bsclient.EpisodeRef) error {
+		_, err := m.client.EpisodeNote(ref, note)
+		return err
+	}, func(e *bsclient.Episode) { e.Note.User = note })
+}
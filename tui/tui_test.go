@@ -0,0 +1,185 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dns-gh/bs-client/bsclient"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+// fakeClient records the changes of episodes.
+type fakeClient struct {
+	calls []string
+	fail  bool
+}
+
+func episode(id, season, number int, seen bool, date string) bsclient.Episode {
+	e := bsclient.Episode{ID: id, Season: season, Episode: number,
+		Code: fmt.Sprintf("S%02dE%02d", season, number), Title: fmt.Sprintf("Episode %d", id)}
+	e.User.Seen = seen
+	e.Show.Title = "Breaking Bad"
+	e.Date, _ = time.Parse("2006-01-02", date)
+	return e
+}
+
+func (f *fakeClient) MembersInfos(opts *bsclient.MembersInfosOptions) (*bsclient.Member, error) {
+	wire := bsclient.Show{ID: 1, Title: "The Wire"}
+	bb := bsclient.Show{ID: 481, Title: "Breaking Bad"}
+	bb.User.Remaining = 2
+	return &bsclient.Member{Shows: []bsclient.Show{wire, bb}}, nil
+}
+
+func (f *fakeClient) ShowsEpisodes(show bsclient.ShowRef, opts *bsclient.ShowsEpisodesOptions) ([]bsclient.Episode, error) {
+	return []bsclient.Episode{
+		episode(21, 2, 1, false, "2009-03-08"),
+		episode(11, 1, 1, true, "2008-01-20"),
+		episode(12, 1, 2, false, "2008-01-27"),
+	}, nil
+}
+
+func (f *fakeClient) PlanningMember(opts *bsclient.PlanningMemberOptions) ([]bsclient.Episode, error) {
+	return []bsclient.Episode{
+		episode(32, 3, 2, false, "2016-03-20"),
+		episode(30, 2, 9, false, "2016-01-01"),
+		episode(31, 3, 1, false, "2016-03-13"),
+	}, nil
+}
+
+func (f *fakeClient) call(format string, args ...interface{}) (*bsclient.Episode, error) {
+	if f.fail {
+		return nil, fmt.Errorf("api error")
+	}
+	f.calls = append(f.calls, fmt.Sprintf(format, args...))
+	return nil, nil
+}
+
+func (f *fakeClient) EpisodeWatched(episode bsclient.EpisodeRef, opts *bsclient.EpisodeWatchedOptions) (*bsclient.Episode, error) {
+	return f.call("watched %d bulk=%v", episode.ID, *opts.Bulk)
+}
+
+func (f *fakeClient) EpisodeNotWatched(episode bsclient.EpisodeRef) (*bsclient.Episode, error) {
+	return f.call("not watched %d", episode.ID)
+}
+
+func (f *fakeClient) EpisodeDownloaded(episode bsclient.EpisodeRef) (*bsclient.Episode, error) {
+	return f.call("downloaded %d", episode.ID)
+}
+
+func (f *fakeClient) EpisodeNotDownloaded(episode bsclient.EpisodeRef) (*bsclient.Episode, error) {
+	return f.call("not downloaded %d", episode.ID)
+}
+
+func (f *fakeClient) EpisodeNote(episode bsclient.EpisodeRef, note int) (*bsclient.Episode, error) {
+	return f.call("note %d %d", episode.ID, note)
+}
+
+// send updates the model with a message and the messages of the resulting commands.
+func send(m Model, msg tea.Msg) Model {
+	model, cmd := m.Update(msg)
+	m = model.(Model)
+	if cmd != nil {
+		if msg := cmd(); msg != nil {
+			if _, quit := msg.(tea.QuitMsg); !quit {
+				return send(m, msg)
+			}
+		}
+	}
+	return m
+}
+
+func keys(m Model, keys ...string) Model {
+	for _, key := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		}
+		m = send(m, msg)
+	}
+	return m
+}
+
+func (s *MySuite) TestNavigation(c *C) {
+	client := &fakeClient{}
+	m := newModel(client)
+	m = send(m, m.Init()())
+	view := m.View()
+	// sorted by remaining episodes
+	c.Assert(strings.Index(view, "Breaking Bad") < strings.Index(view, "The Wire"), Equals, true)
+	c.Assert(view, Matches, `(?s)Shows.*> Breaking Bad +2 remaining.*`)
+
+	m = keys(m, "enter")
+	c.Assert(m.view, Equals, viewSeasons)
+	c.Assert(m.View(), Matches, `(?s)Breaking Bad\n\n> Season 1 +1/2 seen\n  Season 2 +0/1 seen.*`)
+
+	// the cursor starts on the first unseen episode
+	m = keys(m, "enter")
+	c.Assert(m.view, Equals, viewEpisodes)
+	c.Assert(m.current().ID, Equals, 12)
+	c.Assert(m.View(), Matches, `(?s).*> S01E02 \[ \] \[ \] Episode 12.*`)
+
+	m = keys(m, "esc", "down", "enter")
+	c.Assert(m.current().ID, Equals, 21)
+	m = keys(m, "esc", "esc")
+	c.Assert(m.view, Equals, viewShows)
+
+	m.now = func() time.Time { return time.Date(2016, 3, 10, 12, 0, 0, 0, time.UTC) }
+	m = keys(m, "p")
+	c.Assert(m.view, Equals, viewPlanning)
+	c.Assert(m.planning, HasLen, 2)
+	c.Assert(m.View(), Matches, `(?s)Planning\n\n> 2016-03-13 +Breaking Bad +S03E01.*\n  2016-03-20 .*`)
+	c.Assert(client.calls, HasLen, 0)
+
+	// today is the local day, whatever the time zone
+	m.now = func() time.Time { return time.Date(2016, 3, 13, 23, 0, 0, 0, time.FixedZone("HST", -10*3600)) }
+	m = send(m, m.loadPlanning())
+	c.Assert(m.planning, HasLen, 2)
+	m.now = func() time.Time { return time.Date(2016, 3, 14, 1, 0, 0, 0, time.FixedZone("JST", 9*3600)) }
+	m = send(m, m.loadPlanning())
+	c.Assert(m.planning, HasLen, 1)
+}
+
+func (s *MySuite) TestToggle(c *C) {
+	client := &fakeClient{}
+	m := newModel(client)
+	m = send(m, m.Init()())
+	m = keys(m, "enter", "enter")
+
+	m = keys(m, "w")
+	c.Assert(m.current().User.Seen, Equals, true)
+	c.Assert(m.shows[0].User.Remaining, Equals, 1)
+	c.Assert(m.status, Equals, "S01E02 watched")
+	m = keys(m, "w", "d", "d", "4")
+	c.Assert(m.current().User.Seen, Equals, false)
+	c.Assert(m.current().User.Downloaded, Equals, false)
+	c.Assert(m.current().Note.User, Equals, 4)
+	c.Assert(m.shows[0].User.Remaining, Equals, 2)
+	c.Assert(client.calls, DeepEquals, []string{
+		"watched 12 bulk=false", "not watched 12", "downloaded 12", "not downloaded 12", "note 12 4",
+	})
+	c.Assert(m.View(), Matches, `(?s).*> S01E02 \[ \] \[ \] Episode 12 +2008-01-27 \*\*\*\*.*`)
+
+	// errors are shown and the episode is unchanged
+	client.fail = true
+	m = keys(m, "w")
+	c.Assert(m.current().User.Seen, Equals, false)
+	c.Assert(m.View(), Matches, `(?s).*error: api error.*`)
+
+	// keys only change episodes in the episodes view
+	m = keys(m, "esc", "w")
+	c.Assert(client.calls, HasLen, 5)
+}
@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+var help = map[view]string{
+	viewShows:    "enter: seasons  p: planning  r: refresh  q: quit",
+	viewSeasons:  "enter: episodes  esc: shows  p: planning  q: quit",
+	viewEpisodes: "w: watched  d: downloaded  1-5: rate  esc: seasons  q: quit",
+	viewPlanning: "esc: shows  r: refresh  q: quit",
+}
+
+// check returns a check box.
+func check(checked bool) string {
+	if checked {
+		return "[x]"
+	}
+	return "[ ]"
+}
+
+func (m *Model) showTitle() string {
+	for _, show := range m.shows {
+		if show.ID == m.showID {
+			return show.Title
+		}
+	}
+	return ""
+}
+
+// lines returns the title and the lines of the current view.
+func (m *Model) lines() (string, []string) {
+	lines := []string{}
+	switch m.view {
+	case viewShows:
+		for _, show := range m.shows {
+			lines = append(lines, fmt.Sprintf("%-40s %4d remaining", show.Title, show.User.Remaining))
+		}
+		return "Shows", lines
+	case viewSeasons:
+		for _, s := range m.seasons {
+			lines = append(lines, fmt.Sprintf("Season %-3d %3d/%d seen", s.number, s.seen, len(s.episodes)))
+		}
+		return m.showTitle(), lines
+	case viewEpisodes:
+		if m.season >= len(m.seasons) {
+			return m.showTitle(), nil
+		}
+		for _, index := range m.seasons[m.season].episodes {
+			episode := &m.episodes[index]
+			note := ""
+			if episode.Note.User > 0 {
+				note = strings.Repeat("*", episode.Note.User)
+			}
+			lines = append(lines, fmt.Sprintf("%s %s %s %-40s %-10s %s",
+				episode.Code, check(episode.User.Seen), check(episode.User.Downloaded),
+				episode.Title, date(episode), note))
+		}
+		return fmt.Sprintf("%s - season %d   (seen, downloaded)", m.showTitle(), m.seasons[m.season].number), lines
+	case viewPlanning:
+		for _, episode := range m.planning {
+			lines = append(lines, fmt.Sprintf("%-10s %-30s %s %s",
+				date(&episode), episode.Show.Title, episode.Code, episode.Title))
+		}
+		return "Planning", lines
+	}
+	return "", nil
+}
+
+func date(episode *bsclient.Episode) string {
+	if episode.Date.IsZero() {
+		return ""
+	}
+	return episode.Date.Format("2006-01-02")
+}
+
+// View renders the current view, scrolled to keep the cursor visible.
+func (m Model) View() string {
+	title, lines := m.lines()
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "%s\n\n", title)
+
+	cursor := m.cursors[m.view]
+	height := len(lines)
+	if m.height > 5 && height > m.height-5 {
+		height = m.height - 5
+	}
+	first := 0
+	if cursor >= height {
+		first = cursor - height + 1
+	}
+	for i := first; i < first+height && i < len(lines); i++ {
+		prefix := "  "
+		if i == cursor {
+			prefix = "> "
+		}
+		fmt.Fprintf(b, "%s%s\n", prefix, lines[i])
+	}
+	if len(lines) == 0 && !m.loading {
+		b.WriteString("  nothing to show\n")
+	}
+
+	status := m.status
+	if m.loading {
+		status = "loading..."
+	}
+	fmt.Fprintf(b, "\n%s\n%s", status, help[m.view])
+	return b.String()
+}
@@ -0,0 +1,92 @@
+// Package watch polls the betaseries API and emits events for new episodes,
+// subtitles, news and show status changes
+package watch
+
+import (
+	"strconv"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+// Event is implemented by EpisodeAired, SubtitleAdded, NewsPublished and ShowStatusChanged.
+type Event interface {
+	// Key identifies the item of the event.
+	Key() string
+	// Value is compared to the value stored for the key: an event is
+	// only emitted if its key is new or if its value changed.
+	Value() string
+}
+
+// changeEvent is implemented by the events only emitted when the value
+// of an already known key changes.
+type changeEvent interface {
+	Event
+	withPrevious(previous string) Event
+}
+
+// EpisodeAired is emitted when an episode has been broadcast.
+type EpisodeAired struct {
+	Episode bsclient.Episode
+}
+
+// Key implements Event.
+func (e EpisodeAired) Key() string {
+	return "episode/" + strconv.Itoa(e.Episode.ID)
+}
+
+// Value implements Event.
+func (e EpisodeAired) Value() string {
+	return ""
+}
+
+// SubtitleAdded is emitted when a subtitle has been published.
+type SubtitleAdded struct {
+	Subtitle bsclient.Subtitle
+}
+
+// Key implements Event.
+func (e SubtitleAdded) Key() string {
+	return "subtitle/" + strconv.Itoa(e.Subtitle.ID)
+}
+
+// Value implements Event.
+func (e SubtitleAdded) Value() string {
+	return ""
+}
+
+// NewsPublished is emitted when a news has been published.
+type NewsPublished struct {
+	News bsclient.News
+}
+
+// Key implements Event.
+func (e NewsPublished) Key() string {
+	return "news/" + e.News.ID
+}
+
+// Value implements Event.
+func (e NewsPublished) Value() string {
+	return ""
+}
+
+// ShowStatusChanged is emitted when the status of a show changes,
+// like from 'Continuing' to 'Ended'.
+type ShowStatusChanged struct {
+	Show     bsclient.Show
+	Previous string
+}
+
+// Key implements Event.
+func (e ShowStatusChanged) Key() string {
+	return "show/" + strconv.Itoa(e.Show.ID)
+}
+
+// Value implements Event.
+func (e ShowStatusChanged) Value() string {
+	return e.Show.Status
+}
+
+func (e ShowStatusChanged) withPrevious(previous string) Event {
+	e.Previous = previous
+	return e
+}
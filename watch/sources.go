@@ -0,0 +1,185 @@
+package watch
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+// Source polls the betaseries API and returns the events of its current items.
+type Source interface {
+	// Name identifies the source in the state file.
+	Name() string
+	Poll(ctx context.Context, bs *bsclient.BetaSeries) ([]Event, error)
+}
+
+type source struct {
+	name string
+	poll func(bs *bsclient.BetaSeries) ([]Event, error)
+}
+
+func (s *source) Name() string {
+	return s.name
+}
+
+func (s *source) Poll(ctx context.Context, bs *bsclient.BetaSeries) ([]Event, error) {
+	events, err := s.poll(bs.WithContext(ctx))
+	if bsclient.IsNotFound(err) {
+		return nil, nil
+	}
+	return events, err
+}
+
+// NewSource returns a source named 'name' polled with the given function.
+// The name must be unique among the sources sharing a state file.
+func NewSource(name string, poll func(bs *bsclient.BetaSeries) ([]Event, error)) Source {
+	return &source{name: name, poll: poll}
+}
+
+// sourceName returns 'base' followed by the given parameters, so that the
+// built-in sources polled with different options keep their own state.
+// The base name alone is kept for default options.
+func sourceName(base string, params url.Values, extra url.Values) string {
+	for key, values := range extra {
+		params[key] = values
+	}
+	if len(params) == 0 {
+		return base
+	}
+	return base + "?" + params.Encode()
+}
+
+// airedEvents returns the events of the episodes broadcast before 'now'.
+func airedEvents(episodes []bsclient.Episode, now time.Time) []Event {
+	events := []Event{}
+	for _, episode := range episodes {
+		if !episode.Date.IsZero() && !episode.Date.After(now) {
+			events = append(events, EpisodeAired{Episode: episode})
+		}
+	}
+	return events
+}
+
+// Incoming returns a source emitting EpisodeAired for the episodes of
+// PlanningIncoming once their air date is reached.
+func Incoming() Source {
+	return NewSource("incoming", func(bs *bsclient.BetaSeries) ([]Event, error) {
+		episodes, err := bs.PlanningIncoming()
+		return airedEvents(episodes, time.Now()), err
+	})
+}
+
+// MemberPlanning returns a source emitting EpisodeAired for the episodes
+// of PlanningMember once their air date is reached.
+func MemberPlanning(opts *bsclient.PlanningMemberOptions) Source {
+	params := url.Values{}
+	var extra url.Values
+	if opts != nil {
+		if opts.ID != nil {
+			params.Set("id", strconv.Itoa(*opts.ID))
+		}
+		if opts.Unseen {
+			params.Set("unseen", "true")
+		}
+		if opts.Month != "" {
+			params.Set("month", opts.Month)
+		}
+		extra = opts.Extra
+	}
+	return NewSource(sourceName("planning", params, extra), func(bs *bsclient.BetaSeries) ([]Event, error) {
+		episodes, err := bs.PlanningMember(opts)
+		return airedEvents(episodes, time.Now()), err
+	})
+}
+
+// LastSubtitles returns a source emitting SubtitleAdded for the subtitles of SubtitlesLast.
+func LastSubtitles(opts *bsclient.SubtitlesLastOptions) Source {
+	params := url.Values{}
+	var extra url.Values
+	if opts != nil {
+		if opts.Language != "" {
+			params.Set("language", opts.Language)
+		}
+		if opts.Number != nil {
+			params.Set("number", strconv.Itoa(*opts.Number))
+		}
+		extra = opts.Extra
+	}
+	return NewSource(sourceName("subtitles", params, extra), func(bs *bsclient.BetaSeries) ([]Event, error) {
+		subtitles, err := bs.SubtitlesLast(opts)
+		events := make([]Event, 0, len(subtitles))
+		for _, subtitle := range subtitles {
+			events = append(events, SubtitleAdded{Subtitle: subtitle})
+		}
+		return events, err
+	})
+}
+
+// LastNews returns a source emitting NewsPublished for the news of NewsLast.
+func LastNews(opts *bsclient.NewsLastOptions) Source {
+	params := url.Values{}
+	var extra url.Values
+	if opts != nil {
+		if opts.Number != nil {
+			params.Set("number", strconv.Itoa(*opts.Number))
+		}
+		if opts.Tailored {
+			params.Set("tailored", "true")
+		}
+		extra = opts.Extra
+	}
+	return NewSource(sourceName("news", params, extra), func(bs *bsclient.BetaSeries) ([]Event, error) {
+		news, err := bs.NewsLast(opts)
+		events := make([]Event, 0, len(news))
+		for _, n := range news {
+			events = append(events, NewsPublished{News: n})
+		}
+		return events, err
+	})
+}
+
+// ShowStatuses returns a source emitting ShowStatusChanged for the given
+// shows, or for the shows of the identified member if none is given.
+func ShowStatuses(shows ...bsclient.ShowRef) Source {
+	params := url.Values{}
+	for _, ref := range shows {
+		switch {
+		case ref.ID > 0:
+			params.Add("id", strconv.Itoa(ref.ID))
+		case ref.TVDB > 0:
+			params.Add("thetvdb_id", strconv.Itoa(ref.TVDB))
+		case ref.IMDB != "":
+			params.Add("imdb_id", ref.IMDB)
+		case ref.TMDB > 0:
+			params.Add("tmdb_id", strconv.Itoa(ref.TMDB))
+		default:
+			params.Add("title", ref.Title)
+		}
+	}
+	return NewSource(sourceName("shows", params, nil), func(bs *bsclient.BetaSeries) ([]Event, error) {
+		events := []Event{}
+		if len(shows) == 0 {
+			member, err := bs.MembersInfos(&bsclient.MembersInfosOptions{Only: "shows"})
+			if err != nil {
+				return nil, err
+			}
+			for _, show := range member.Shows {
+				events = append(events, ShowStatusChanged{Show: show})
+			}
+			return events, nil
+		}
+		for _, ref := range shows {
+			show, err := bs.ShowDisplay(ref)
+			if err != nil {
+				return nil, err
+			}
+			if show != nil {
+				events = append(events, ShowStatusChanged{Show: *show})
+			}
+		}
+		return events, nil
+	})
+}
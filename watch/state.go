@@ -0,0 +1,102 @@
+package watch
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const stateVersion = 1
+
+// ItemState represents what is known about an item of a source.
+type ItemState struct {
+	Value    string    `json:"value,omitempty"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// SourceState represents the items already seen by a source.
+type SourceState struct {
+	// Primed is set once the source has been polled a first time.
+	Primed bool                  `json:"primed"`
+	Items  map[string]*ItemState `json:"items"`
+}
+
+// State represents the persistent state of a watcher, so that
+// items are not emitted again after a restart.
+type State struct {
+	Version int                     `json:"version"`
+	Sources map[string]*SourceState `json:"sources"`
+}
+
+// NewState returns an empty state.
+func NewState() *State {
+	return &State{
+		Version: stateVersion,
+		Sources: map[string]*SourceState{},
+	}
+}
+
+// source returns the state of a source, creating it if needed.
+func (s *State) source(name string) *SourceState {
+	source, ok := s.Sources[name]
+	if !ok {
+		source = &SourceState{Items: map[string]*ItemState{}}
+		s.Sources[name] = source
+	}
+	if source.Items == nil {
+		source.Items = map[string]*ItemState{}
+	}
+	return source
+}
+
+// prune removes the items not seen since 'before'.
+func (s *SourceState) prune(before time.Time) {
+	for key, item := range s.Items {
+		if item.LastSeen.Before(before) {
+			delete(s.Items, key)
+		}
+	}
+}
+
+// LoadState reads a state file. An empty state is returned if it does not exist.
+func LoadState(path string) (*State, error) {
+	state := NewState()
+	if path == "" {
+		return state, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Sources == nil {
+		state.Sources = map[string]*SourceState{}
+	}
+	return state, nil
+}
+
+// Save writes the state file atomically.
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,142 @@
+package watch
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dns-gh/bs-client/bsclient"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+// fakeSource returns the items set by the test.
+type fakeSource struct {
+	news  []string
+	shows map[int]string
+}
+
+func (f *fakeSource) sources() (Source, Source) {
+	news := NewSource("news", func(bs *bsclient.BetaSeries) ([]Event, error) {
+		events := []Event{}
+		for _, id := range f.news {
+			events = append(events, NewsPublished{News: bsclient.News{ID: id}})
+		}
+		return events, nil
+	})
+	shows := NewSource("shows", func(bs *bsclient.BetaSeries) ([]Event, error) {
+		events := []Event{}
+		for id, status := range f.shows {
+			events = append(events, ShowStatusChanged{Show: bsclient.Show{ID: id, Status: status}})
+		}
+		return events, nil
+	})
+	return news, shows
+}
+
+// pollAll polls the sources once and returns the emitted events.
+func pollAll(c *C, w *Watcher, sources ...Source) []Event {
+	events := make(chan Event, 10)
+	for _, source := range sources {
+		c.Assert(w.poll(context.Background(), source, events), IsNil)
+	}
+	close(events)
+	emitted := []Event{}
+	for event := range events {
+		emitted = append(emitted, event)
+	}
+	return emitted
+}
+
+func newTestWatcher(c *C, stateFile string) *Watcher {
+	w := NewWatcher(&bsclient.BetaSeries{}, stateFile)
+	state, err := LoadState(stateFile)
+	c.Assert(err, IsNil)
+	w.state = state
+	return w
+}
+
+func (s *MySuite) TestWatcherDeduplication(c *C) {
+	stateFile := filepath.Join(c.MkDir(), "watch.json")
+	fake := &fakeSource{news: []string{"1", "2"}, shows: map[int]string{481: "Continuing"}}
+	news, shows := fake.sources()
+
+	// the first poll only primes the state
+	w := newTestWatcher(c, stateFile)
+	c.Assert(pollAll(c, w, news, shows), HasLen, 0)
+
+	fake.news = append(fake.news, "3")
+	c.Assert(pollAll(c, w, news, shows), DeepEquals, []Event{
+		NewsPublished{News: bsclient.News{ID: "3"}},
+	})
+
+	// a restarted watcher does not emit old items again
+	w = newTestWatcher(c, stateFile)
+	c.Assert(pollAll(c, w, news, shows), HasLen, 0)
+
+	fake.shows[481] = "Ended"
+	fake.shows[1] = "Continuing"
+	c.Assert(pollAll(c, w, news, shows), DeepEquals, []Event{
+		ShowStatusChanged{Show: bsclient.Show{ID: 481, Status: "Ended"}, Previous: "Continuing"},
+	})
+}
+
+func (s *MySuite) TestWatcherEmitInitialAndRetention(c *C) {
+	fake := &fakeSource{news: []string{"1"}}
+	news, _ := fake.sources()
+	w := newTestWatcher(c, "")
+	w.EmitInitial = true
+	w.Retention = time.Nanosecond
+	c.Assert(pollAll(c, w, news), HasLen, 1)
+
+	fake.news = []string{"2"}
+	c.Assert(pollAll(c, w, news), HasLen, 1)
+	// the expired item is emitted again
+	time.Sleep(time.Millisecond)
+	fake.news = []string{"1"}
+	c.Assert(pollAll(c, w, news), HasLen, 1)
+}
+
+func (s *MySuite) TestWatch(c *C) {
+	fake := &fakeSource{news: []string{"1"}}
+	news, _ := fake.sources()
+	w := NewWatcher(&bsclient.BetaSeries{}, filepath.Join(c.MkDir(), "watch.json"))
+	w.EmitInitial = true
+	w.Add(news, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, errs := w.Watch(ctx)
+	select {
+	case event := <-events:
+		c.Assert(event.Key(), Equals, "news/1")
+	case err := <-errs:
+		c.Fatal(err)
+	case <-time.After(5 * time.Second):
+		c.Fatal("no event")
+	}
+	cancel()
+	for range events {
+	}
+	for range errs {
+	}
+}
+
+func (s *MySuite) TestSourceNames(c *C) {
+	c.Assert(MemberPlanning(nil).Name(), Equals, "planning")
+	c.Assert(MemberPlanning(&bsclient.PlanningMemberOptions{}).Name(), Equals, "planning")
+	c.Assert(MemberPlanning(&bsclient.PlanningMemberOptions{ID: bsclient.Int(12), Unseen: true}).Name(),
+		Equals, "planning?id=12&unseen=true")
+	c.Assert(LastSubtitles(&bsclient.SubtitlesLastOptions{Number: bsclient.Int(10)}).Name(), Equals, "subtitles?number=10")
+	c.Assert(LastNews(&bsclient.NewsLastOptions{Tailored: true}).Name(), Equals, "news?tailored=true")
+	c.Assert(ShowStatuses().Name(), Equals, "shows")
+	c.Assert(ShowStatuses(bsclient.ShowID(481), bsclient.ShowIMDB("tt0903747")).Name(),
+		Equals, "shows?id=481&imdb_id=tt0903747")
+	c.Assert(ShowStatuses(bsclient.ShowID(1)).Name(), Not(Equals), ShowStatuses(bsclient.ShowID(2)).Name())
+}
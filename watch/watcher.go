@@ -0,0 +1,174 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dns-gh/bs-client/bsclient"
+)
+
+const (
+	// DefaultRetention is the duration items are kept in the state after
+	// they stopped being returned by their source.
+	DefaultRetention = 30 * 24 * time.Hour
+	// DefaultInterval is the polling interval of sources added without one.
+	DefaultInterval = 5 * time.Minute
+)
+
+// schedule is a source polled every 'interval'.
+type schedule struct {
+	source   Source
+	interval time.Duration
+}
+
+// Watcher polls sources on schedules and emits the events of their new items.
+// Items are deduplicated with a persistent state file, so that a restarted
+// watcher does not emit them again.
+type Watcher struct {
+	Client *bsclient.BetaSeries
+	// StateFile is the path of the state file, the state is kept in memory if empty.
+	StateFile string
+	// Retention defaults to DefaultRetention.
+	Retention time.Duration
+	// EmitInitial emits the items found by the first poll of a source
+	// instead of only recording them.
+	EmitInitial bool
+
+	schedules []schedule
+	mutex     sync.Mutex
+	state     *State
+}
+
+// NewWatcher creates a watcher using the given state file.
+func NewWatcher(bs *bsclient.BetaSeries, stateFile string) *Watcher {
+	return &Watcher{
+		Client:    bs,
+		StateFile: stateFile,
+	}
+}
+
+// Add schedules the polling of a source every 'interval', DefaultInterval if 0.
+func (w *Watcher) Add(source Source, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	w.schedules = append(w.schedules, schedule{source: source, interval: interval})
+}
+
+// filter returns the events of a poll to emit, without changing the state.
+func (w *Watcher) filter(name string, events []Event) []Event {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	source, ok := w.state.Sources[name]
+	if !ok || !source.Primed {
+		if !w.EmitInitial {
+			return nil
+		}
+		source = &SourceState{}
+	}
+	emit := []Event{}
+	for _, event := range events {
+		item, known := source.Items[event.Key()]
+		if change, ok := event.(changeEvent); ok {
+			if known && item.Value != event.Value() {
+				emit = append(emit, change.withPrevious(item.Value))
+			}
+		} else if !known {
+			emit = append(emit, event)
+		}
+	}
+	return emit
+}
+
+// record stores the items of a poll in the state and removes the expired ones.
+func (w *Watcher) record(name string, events []Event, now time.Time) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	source := w.state.source(name)
+	for _, event := range events {
+		source.Items[event.Key()] = &ItemState{Value: event.Value(), LastSeen: now}
+	}
+	source.Primed = true
+	retention := w.Retention
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	source.prune(now.Add(-retention))
+}
+
+// save writes the state file, if any.
+func (w *Watcher) save() error {
+	if w.StateFile == "" {
+		return nil
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.state.Save(w.StateFile)
+}
+
+// poll polls a source once and sends the events of its new items. The items
+// are only recorded once every event has been sent, so that events interrupted
+// by the cancellation of the context are emitted again after a restart.
+func (w *Watcher) poll(ctx context.Context, source Source, events chan<- Event) error {
+	found, err := source.Poll(ctx, w.Client)
+	if err != nil {
+		return fmt.Errorf("%s: %w", source.Name(), err)
+	}
+	for _, event := range w.filter(source.Name(), found) {
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	w.record(source.Name(), found, time.Now())
+	return w.save()
+}
+
+// Watch polls every source until the context is cancelled, beginning
+// immediately. Events are sent on the first channel. Poll errors are sent on
+// the second one and do not stop the watcher. Both channels must be drained,
+// and are closed once the watcher stopped.
+func (w *Watcher) Watch(ctx context.Context) (<-chan Event, <-chan error) {
+	events := make(chan Event)
+	errs := make(chan error, 1)
+	state, err := LoadState(w.StateFile)
+	if err != nil {
+		errs <- err
+		close(errs)
+		close(events)
+		return events, errs
+	}
+	w.state = state
+
+	wg := sync.WaitGroup{}
+	for _, s := range w.schedules {
+		wg.Add(1)
+		go func(s schedule) {
+			defer wg.Done()
+			ticker := time.NewTicker(s.interval)
+			defer ticker.Stop()
+			for {
+				if err := w.poll(ctx, s.source, events); err != nil && ctx.Err() == nil {
+					select {
+					case errs <- err:
+					case <-ctx.Done():
+					}
+				}
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(s)
+	}
+	go func() {
+		wg.Wait()
+		close(events)
+		close(errs)
+	}()
+	return events, errs
+}